@@ -3,11 +3,17 @@ package main
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/lovesway/hassio-addons/mq-lightshow/colors"
+	"github.com/lovesway/hassio-addons/mq-lightshow/cron"
 	"github.com/lovesway/hassio-addons/mq-lightshow/database"
+	"github.com/lovesway/hassio-addons/mq-lightshow/devicetypes"
 	"github.com/lovesway/hassio-addons/mq-lightshow/models"
 )
 
@@ -18,6 +24,52 @@ var (
 	errNoGroupID  = errors.New("no groupid given")
 	errNoActionID = errors.New("no actionid given")
 	errNoSortID   = errors.New("no sortid given")
+	errNoDeviceID = errors.New("no deviceid given")
+	errNoTypeID   = errors.New("no typeid given")
+
+	errDeviceName  = errors.New("device name is required")
+	errDeviceTopic = errors.New("device topic is required")
+	errDeviceType  = errors.New("unknown device type")
+
+	errDeviceTypeInUse = errors.New("device type is used by a device")
+	errActionCommand   = errors.New("unknown command")
+
+	errActionType       = errors.New("unknown action type")
+	errActionDevices    = errors.New("an action needs at least one device or zone")
+	errActionColorEmpty = errors.New("a color, color temperature or brightness is required")
+	errActionColorAndCT = errors.New("set either a color or a color temperature")
+	errActionCT         = errors.New("color temperature is out of range")
+	errActionBrightness = errors.New("brightness is out of range")
+	errActionTransition = errors.New("transition cannot be negative")
+
+	errGroupFadeDuration = errors.New("fade duration cannot be negative")
+	errGroupFadeRate     = errors.New("fade rate is out of range")
+	errGroupFadeEasing   = errors.New("unknown fade easing")
+
+	errCycleSceneOrEffect = errors.New("a cycle runs either a scene or an effect")
+	errNoEffectID         = errors.New("no effectid given")
+	errEffectName         = errors.New("effect name is required")
+	errEffectType         = errors.New("unknown effect type")
+	errEffectDirection    = errors.New("unknown effect direction")
+	errEffectSpeed        = errors.New("effect speed must be from 0 to 20 steps per second")
+	errEffectDuration     = errors.New("effect duration must be more than 0 seconds")
+	errEffectDevices      = errors.New("an effect needs at least one device")
+	errEffectInUse        = errors.New("effect is used by a show")
+
+	errNoPaletteID          = errors.New("no paletteid given")
+	errPaletteName          = errors.New("palette name is required")
+	errPaletteColors        = errors.New("a palette needs at least one color")
+	errPaletteInUse         = errors.New("palette is used by a show")
+	errActionPaletteColor   = errors.New("palette color must be a number from 1 or next")
+	errActionPaletteCommand = errors.New("palette colors need a command with a color parameter")
+
+	errNoScheduleID        = errors.New("no scheduleid given")
+	errScheduleName        = errors.New("schedule name is required")
+	errScheduleStart       = errors.New("a schedule needs a cron expression, a start time or a stop time")
+	errScheduleCronAndTime = errors.New("set either a cron expression or a start time")
+	errScheduleDay         = errors.New("days of the week must be from 0 (Sunday) to 6 (Saturday)")
+	errScheduleDate        = errors.New("dates must be given as MM-DD")
+	errScheduleDates       = errors.New("a date range needs both a start and an end date")
 )
 
 // APIController represents the controller for the API.
@@ -25,14 +77,23 @@ type APIController struct {
 	md Modeler
 	ss StringsToStruct
 	db *database.Sqlite
+	mq *MQController
+	dt *devicetypes.DeviceTypes
+	st scheduleTimes
 }
 
 // NewAPIController provides an instance of APIController.
-func NewAPIController(md Modeler, ss StringsToStruct, db *database.Sqlite) APIController {
+func NewAPIController(
+	md Modeler, ss StringsToStruct, db *database.Sqlite, mq *MQController, dt *devicetypes.DeviceTypes,
+	st scheduleTimes,
+) APIController {
 	return APIController{
 		md: md,
 		ss: ss,
 		db: db,
+		mq: mq,
+		dt: dt,
+		st: st,
 	}
 }
 
@@ -41,6 +102,7 @@ type Response struct {
 	Status  int16
 	Error   bool
 	Message string
+	Field   string `json:",omitempty"` // the posted field an error is about.
 }
 
 // ResponseData object.
@@ -63,6 +125,32 @@ func getResponseError(message string) Response {
 	}
 }
 
+// fieldError is an error about one posted field.
+type fieldError struct {
+	field string
+	err   error
+}
+
+func (e fieldError) Error() string {
+	return e.field + ": " + e.err.Error()
+}
+
+func (e fieldError) Unwrap() error {
+	return e.err
+}
+
+// getResponseFieldError returns an error response naming the field when err is a fieldError.
+func getResponseFieldError(err error) Response {
+	re := getResponseError(err.Error())
+
+	var fe fieldError
+	if errors.As(err, &fe) {
+		re.Field = fe.field
+	}
+
+	return re
+}
+
 func getResponse(message string) Response {
 	if message == "" {
 		message = "success"
@@ -136,6 +224,40 @@ func (ac APIController) Show(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// ShowStatus returns the progress of a show.
+func (ac APIController) ShowStatus(w http.ResponseWriter, r *http.Request) {
+	showID, err := getShowIDFromRequest(r)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	show, err := ac.md.GetShow(showID)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	progress := ex.GetShowProgress(showID)
+	progress.Name = show.Name
+
+	re := getResponseData()
+	re.Data = progress
+
+	jsonErr := json.NewEncoder(w).Encode(re)
+	if jsonErr != nil {
+		log.Error(jsonErr)
+	}
+}
+
 type showStrings struct {
 	Name             string
 	Topic            string
@@ -144,6 +266,9 @@ type showStrings struct {
 	GlobalSpeed      string
 	GlobalParameter1 string
 	GlobalParameter2 string
+	PaletteID        string
+	Priority         string
+	ConflictPolicy   string
 }
 
 func getShowIDFromRequest(r *http.Request) (int, error) {
@@ -190,7 +315,7 @@ func (ac APIController) ShowCreate(w http.ResponseWriter, r *http.Request) {
 		}
 	}()
 
-	show := models.Show{}
+	show := models.Show{ConflictPolicy: conflictPolicyShare}
 
 	show, err = ac.ss.Show(dd, show)
 	if err != nil {
@@ -202,7 +327,17 @@ func (ac APIController) ShowCreate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	_, err = ac.md.AddShow(show)
+	err = ac.validateShow(show)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseFieldError(err))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	show.ID, err = ac.md.AddShow(show)
 	if err != nil {
 		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
 		if jsonErr != nil {
@@ -212,6 +347,8 @@ func (ac APIController) ShowCreate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	ac.mq.RegisterShow(show)
+
 	re := getResponse("Show created successfully")
 	re.Status = http.StatusCreated
 
@@ -221,6 +358,22 @@ func (ac APIController) ShowCreate(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// validateShow checks that the palette of a show exists and that its conflict policy is known.
+func (ac APIController) validateShow(show models.Show) error {
+	if show.PaletteID != 0 {
+		_, err := ac.md.GetPalette(show.PaletteID)
+		if err != nil {
+			return fieldError{"PaletteID", err}
+		}
+	}
+
+	if err := checkConflictPolicy(show.ConflictPolicy); err != nil {
+		return fieldError{"ConflictPolicy", err}
+	}
+
+	return nil
+}
+
 // ShowConfigure will update a Show.
 func (ac APIController) ShowConfigure(w http.ResponseWriter, r *http.Request) {
 	showID, err := getShowIDFromRequest(r)
@@ -233,7 +386,22 @@ func (ac APIController) ShowConfigure(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var dd showStrings
+	previous, err := ac.md.GetShow(showID)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	// Priority and ConflictPolicy keep their value when they are left out, so clients that
+	// configure shows without them do not reset them.
+	dd := showStrings{
+		Priority:       strconv.Itoa(previous.Priority),
+		ConflictPolicy: previous.ConflictPolicy,
+	}
 
 	dec := json.NewDecoder(r.Body)
 	dec.DisallowUnknownFields()
@@ -267,6 +435,20 @@ func (ac APIController) ShowConfigure(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if show.ConflictPolicy == "" {
+		show.ConflictPolicy = conflictPolicyShare
+	}
+
+	err = ac.validateShow(show)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseFieldError(err))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
 	show.ID = showID
 
 	err = ac.md.SetShow(show)
@@ -274,6 +456,13 @@ func (ac APIController) ShowConfigure(w http.ResponseWriter, r *http.Request) {
 		log.Error(err)
 	}
 
+	// a renamed topic is no longer listened to, the discovery config keeps its ID so the entity is updated.
+	if previous.Topic != show.Topic && previous.Topic != "" {
+		ac.mq.Unsubscribe(fmt.Sprintf("mqlightshow/show/%v/cmnd/#", previous.Topic))
+	}
+
+	ac.mq.RegisterShow(show)
+
 	re := getResponse("Show updated successfully")
 	re.Status = http.StatusCreated
 
@@ -295,11 +484,23 @@ func (ac APIController) ShowDelete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	show, err := ac.md.GetShow(showID)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
 	err = ac.md.DeleteShow(showID)
 	if err != nil {
 		log.Error(err)
 	}
 
+	ac.mq.UnregisterShow(show)
+
 	re := getResponse("Show deleted successfully")
 	re.Status = http.StatusNoContent
 
@@ -332,9 +533,15 @@ func (ac APIController) ShowStart(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = ex.StartShow(showID)
+	// the running shows that use the same devices are reported in Data, whether the show started or not.
+	conflicts, err := ex.StartShowConflicts(showID)
 	if err != nil {
-		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		re := ResponseData{Status: http.StatusInternalServerError, Error: true, Message: err.Error(), Data: conflicts}
+		if errors.Is(err, errShowConflict) {
+			re.Status = http.StatusConflict
+		}
+
+		jsonErr := json.NewEncoder(w).Encode(re)
 		if jsonErr != nil {
 			log.Error(jsonErr)
 		}
@@ -342,7 +549,11 @@ func (ac APIController) ShowStart(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	jsonErr := json.NewEncoder(w).Encode(getResponse("Show started"))
+	re := getResponseData()
+	re.Message = "Show started"
+	re.Data = conflicts
+
+	jsonErr := json.NewEncoder(w).Encode(re)
 	if jsonErr != nil {
 		log.Error(jsonErr)
 	}
@@ -387,8 +598,8 @@ func (ac APIController) ShowStop(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// ShowCycles will return a list of Cycle objects for given showID.
-func (ac APIController) ShowCycles(w http.ResponseWriter, r *http.Request) {
+// ShowPause will hold a running show on its current scene.
+func (ac APIController) ShowPause(w http.ResponseWriter, r *http.Request) {
 	showID, err := getShowIDFromRequest(r)
 	if err != nil {
 		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
@@ -399,7 +610,18 @@ func (ac APIController) ShowCycles(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	data, err := ac.md.GetShowCyclesRecursive(showID)
+	if showID == 0 {
+		re := getResponseError("No showID given")
+
+		jsonErr := json.NewEncoder(w).Encode(re)
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	err = ex.PauseShow(showID)
 	if err != nil {
 		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
 		if jsonErr != nil {
@@ -409,18 +631,15 @@ func (ac APIController) ShowCycles(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	re := getResponseData()
-	re.Data = data
-
-	jsonErr := json.NewEncoder(w).Encode(re)
+	jsonErr := json.NewEncoder(w).Encode(getResponse("Show paused"))
 	if jsonErr != nil {
 		log.Error(jsonErr)
 	}
 }
 
-// ShowCycle will return a Cycle object for given cycleID.
-func (ac APIController) ShowCycle(w http.ResponseWriter, r *http.Request) {
-	cycleID, err := getCycleIDFromRequest(r)
+// ShowResume will continue a paused show.
+func (ac APIController) ShowResume(w http.ResponseWriter, r *http.Request) {
+	showID, err := getShowIDFromRequest(r)
 	if err != nil {
 		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
 		if jsonErr != nil {
@@ -430,9 +649,10 @@ func (ac APIController) ShowCycle(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	data, err := ac.md.GetShowCycle(cycleID)
-	if err != nil {
-		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+	if showID == 0 {
+		re := getResponseError("No showID given")
+
+		jsonErr := json.NewEncoder(w).Encode(re)
 		if jsonErr != nil {
 			log.Error(jsonErr)
 		}
@@ -440,50 +660,7 @@ func (ac APIController) ShowCycle(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	re := getResponseData()
-	re.Data = data
-
-	jsonErr := json.NewEncoder(w).Encode(re)
-	if jsonErr != nil {
-		log.Error(jsonErr)
-	}
-}
-
-type cycleStrings struct {
-	ShowID           string
-	SceneID          string
-	SceneCycles      string
-	EndDelay         string
-	LoopInclude      string
-	GlobalDelay      string
-	GlobalSpeed      string
-	GlobalParameter1 string
-	GlobalParameter2 string
-}
-
-func getCycleIDFromRequest(r *http.Request) (int, error) {
-	var err error
-
-	var cycleID int
-
-	v := mux.Vars(r)
-	cycleIDString := v["cycleID"]
-
-	if cycleIDString == "" {
-		return cycleID, errNoCycleID
-	}
-
-	cycleIDInt, err := strconv.Atoi(cycleIDString)
-	if err != nil {
-		return cycleID, err
-	}
-
-	return cycleIDInt, err
-}
-
-// ShowCycleCreate will create a show.
-func (ac APIController) ShowCycleCreate(w http.ResponseWriter, r *http.Request) {
-	showID, err := getShowIDFromRequest(r)
+	err = ex.ResumeShow(showID)
 	if err != nil {
 		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
 		if jsonErr != nil {
@@ -493,12 +670,15 @@ func (ac APIController) ShowCycleCreate(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	var dd cycleStrings
-
-	dec := json.NewDecoder(r.Body)
-	dec.DisallowUnknownFields()
+	jsonErr := json.NewEncoder(w).Encode(getResponse("Show resumed"))
+	if jsonErr != nil {
+		log.Error(jsonErr)
+	}
+}
 
-	err = dec.Decode(&dd)
+// ShowNext will skip a running show to its next cycle.
+func (ac APIController) ShowNext(w http.ResponseWriter, r *http.Request) {
+	showID, err := getShowIDFromRequest(r)
 	if err != nil {
 		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
 		if jsonErr != nil {
@@ -508,18 +688,10 @@ func (ac APIController) ShowCycleCreate(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	defer func() {
-		err := r.Body.Close()
-		if err != nil {
-			log.Error(err.Error())
-		}
-	}()
-
-	cycle := models.Cycle{}
+	if showID == 0 {
+		re := getResponseError("No showID given")
 
-	cycle, err = ac.ss.Cycle(dd, cycle)
-	if err != nil {
-		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		jsonErr := json.NewEncoder(w).Encode(re)
 		if jsonErr != nil {
 			log.Error(jsonErr)
 		}
@@ -527,9 +699,7 @@ func (ac APIController) ShowCycleCreate(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	cycle.ShowID = showID
-
-	_, err = ac.md.AddShowCycle(cycle)
+	err = ex.NextCycle(showID)
 	if err != nil {
 		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
 		if jsonErr != nil {
@@ -539,17 +709,14 @@ func (ac APIController) ShowCycleCreate(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	re := getResponse("Cycle created successfully")
-	re.Status = http.StatusCreated
-
-	jsonErr := json.NewEncoder(w).Encode(re)
+	jsonErr := json.NewEncoder(w).Encode(getResponse("Skipped to next cycle"))
 	if jsonErr != nil {
 		log.Error(jsonErr)
 	}
 }
 
-// ShowCycleEdit will update a Cycle.
-func (ac APIController) ShowCycleEdit(w http.ResponseWriter, r *http.Request) {
+// ShowPrevious will skip a running show back to its previous cycle.
+func (ac APIController) ShowPrevious(w http.ResponseWriter, r *http.Request) {
 	showID, err := getShowIDFromRequest(r)
 	if err != nil {
 		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
@@ -560,9 +727,10 @@ func (ac APIController) ShowCycleEdit(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	cycleID, err := getCycleIDFromRequest(r)
-	if err != nil {
-		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+	if showID == 0 {
+		re := getResponseError("No showID given")
+
+		jsonErr := json.NewEncoder(w).Encode(re)
 		if jsonErr != nil {
 			log.Error(jsonErr)
 		}
@@ -570,12 +738,7 @@ func (ac APIController) ShowCycleEdit(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var dd cycleStrings
-
-	dec := json.NewDecoder(r.Body)
-	dec.DisallowUnknownFields()
-
-	err = dec.Decode(&dd)
+	err = ex.PreviousCycle(showID)
 	if err != nil {
 		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
 		if jsonErr != nil {
@@ -585,16 +748,15 @@ func (ac APIController) ShowCycleEdit(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	defer func() {
-		err := r.Body.Close()
-		if err != nil {
-			log.Error(err.Error())
-		}
-	}()
-
-	cycle := models.Cycle{}
+	jsonErr := json.NewEncoder(w).Encode(getResponse("Skipped to previous cycle"))
+	if jsonErr != nil {
+		log.Error(jsonErr)
+	}
+}
 
-	cycle, err = ac.ss.Cycle(dd, cycle)
+// ShowCycleGoto will skip a running show to the given cycle.
+func (ac APIController) ShowCycleGoto(w http.ResponseWriter, r *http.Request) {
+	showID, err := getShowIDFromRequest(r)
 	if err != nil {
 		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
 		if jsonErr != nil {
@@ -604,25 +766,17 @@ func (ac APIController) ShowCycleEdit(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	cycle.ID = cycleID
-	cycle.ShowID = showID
-
-	err = ac.md.SetShowCycle(cycle)
-	if err != nil {
-		log.Error(err)
-	}
+	if showID == 0 {
+		re := getResponseError("No showID given")
 
-	re := getResponse("Cycle updated successfully")
-	re.Status = http.StatusCreated
+		jsonErr := json.NewEncoder(w).Encode(re)
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
 
-	jsonErr := json.NewEncoder(w).Encode(re)
-	if jsonErr != nil {
-		log.Error(jsonErr)
+		return
 	}
-}
 
-// ShowCycleDelete will delete a Cycle.
-func (ac APIController) ShowCycleDelete(w http.ResponseWriter, r *http.Request) {
 	cycleID, err := getCycleIDFromRequest(r)
 	if err != nil {
 		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
@@ -633,7 +787,299 @@ func (ac APIController) ShowCycleDelete(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	err = ac.md.DeleteShowCycle(cycleID)
+	err = ex.GotoCycle(showID, cycleID)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	jsonErr := json.NewEncoder(w).Encode(getResponse("Skipped to cycle"))
+	if jsonErr != nil {
+		log.Error(jsonErr)
+	}
+}
+
+// ShowCycles will return a list of Cycle objects for given showID.
+func (ac APIController) ShowCycles(w http.ResponseWriter, r *http.Request) {
+	showID, err := getShowIDFromRequest(r)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	data, err := ac.md.GetShowCyclesRecursive(showID)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	re := getResponseData()
+	re.Data = data
+
+	jsonErr := json.NewEncoder(w).Encode(re)
+	if jsonErr != nil {
+		log.Error(jsonErr)
+	}
+}
+
+// ShowCycle will return a Cycle object for given cycleID.
+func (ac APIController) ShowCycle(w http.ResponseWriter, r *http.Request) {
+	cycleID, err := getCycleIDFromRequest(r)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	data, err := ac.md.GetShowCycle(cycleID)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	re := getResponseData()
+	re.Data = data
+
+	jsonErr := json.NewEncoder(w).Encode(re)
+	if jsonErr != nil {
+		log.Error(jsonErr)
+	}
+}
+
+type cycleStrings struct {
+	ShowID           string
+	SceneID          string
+	EffectID         string
+	SceneCycles      string
+	EndDelay         string
+	LoopInclude      string
+	GlobalDelay      string
+	GlobalSpeed      string
+	GlobalParameter1 string
+	GlobalParameter2 string
+}
+
+// validateCycle checks that a cycle runs either a scene or an effect.
+func validateCycle(cycle models.Cycle) error {
+	if (cycle.SceneID == 0) == (cycle.EffectID == 0) {
+		return fieldError{"SceneID", errCycleSceneOrEffect}
+	}
+
+	return nil
+}
+
+func getCycleIDFromRequest(r *http.Request) (int, error) {
+	var err error
+
+	var cycleID int
+
+	v := mux.Vars(r)
+	cycleIDString := v["cycleID"]
+
+	if cycleIDString == "" {
+		return cycleID, errNoCycleID
+	}
+
+	cycleIDInt, err := strconv.Atoi(cycleIDString)
+	if err != nil {
+		return cycleID, err
+	}
+
+	return cycleIDInt, err
+}
+
+// ShowCycleCreate will create a show.
+func (ac APIController) ShowCycleCreate(w http.ResponseWriter, r *http.Request) {
+	showID, err := getShowIDFromRequest(r)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	var dd cycleStrings
+
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+
+	err = dec.Decode(&dd)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	defer func() {
+		err := r.Body.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	cycle := models.Cycle{}
+
+	cycle, err = ac.ss.Cycle(dd, cycle)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	err = validateCycle(cycle)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseFieldError(err))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	cycle.ShowID = showID
+
+	_, err = ac.md.AddShowCycle(cycle)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	re := getResponse("Cycle created successfully")
+	re.Status = http.StatusCreated
+
+	jsonErr := json.NewEncoder(w).Encode(re)
+	if jsonErr != nil {
+		log.Error(jsonErr)
+	}
+}
+
+// ShowCycleEdit will update a Cycle.
+func (ac APIController) ShowCycleEdit(w http.ResponseWriter, r *http.Request) {
+	showID, err := getShowIDFromRequest(r)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	cycleID, err := getCycleIDFromRequest(r)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	var dd cycleStrings
+
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+
+	err = dec.Decode(&dd)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	defer func() {
+		err := r.Body.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	cycle := models.Cycle{}
+
+	cycle, err = ac.ss.Cycle(dd, cycle)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	cycle.ID = cycleID
+	cycle.ShowID = showID
+
+	err = validateCycle(cycle)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseFieldError(err))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	err = ac.md.SetShowCycle(cycle)
+	if err != nil {
+		log.Error(err)
+	}
+
+	re := getResponse("Cycle updated successfully")
+	re.Status = http.StatusCreated
+
+	jsonErr := json.NewEncoder(w).Encode(re)
+	if jsonErr != nil {
+		log.Error(jsonErr)
+	}
+}
+
+// ShowCycleDelete will delete a Cycle.
+func (ac APIController) ShowCycleDelete(w http.ResponseWriter, r *http.Request) {
+	cycleID, err := getCycleIDFromRequest(r)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	err = ac.md.DeleteShowCycle(cycleID)
 	if err != nil {
 		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
 		if jsonErr != nil {
@@ -652,34 +1098,1598 @@ func (ac APIController) ShowCycleDelete(w http.ResponseWriter, r *http.Request)
 	}
 }
 
-type sceneStrings struct {
-	Name             string
-	AllowedDeviceIDs []string
+type sceneStrings struct {
+	Name             string
+	AllowedDeviceIDs []string
+	AllowedZoneNames []string
+}
+
+func getSceneIDFromRequest(r *http.Request) (int, error) {
+	var err error
+
+	var sceneID int
+
+	v := mux.Vars(r)
+	sceneIDString := v["sceneID"]
+
+	if sceneIDString == "" {
+		return sceneID, errNoSceneID
+	}
+
+	sceneIDInt, err := strconv.Atoi(sceneIDString)
+	if err != nil {
+		return sceneID, err
+	}
+
+	return sceneIDInt, err
+}
+
+// Scenes will return a list of Scene objects.
+func (ac APIController) Scenes(w http.ResponseWriter, r *http.Request) {
+	scenes, err := ac.md.GetScenes()
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	re := getResponseData()
+	re.Data = scenes
+
+	jsonErr := json.NewEncoder(w).Encode(re)
+	if jsonErr != nil {
+		log.Error(jsonErr)
+	}
+}
+
+// SceneCreate will create a new Scene.
+func (ac APIController) SceneCreate(w http.ResponseWriter, r *http.Request) {
+	var dd sceneStrings
+
+	dec := json.NewDecoder(r.Body)
+
+	dec.DisallowUnknownFields()
+
+	err := dec.Decode(&dd)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	defer func() {
+		err := r.Body.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	scene := models.Scene{}
+
+	scene, err = ac.ss.Scene(dd, scene)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	for _, deviceIDString := range dd.AllowedDeviceIDs {
+		deviceID, err := strconv.Atoi(deviceIDString)
+		if err != nil {
+			jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+			if jsonErr != nil {
+				log.Error(jsonErr)
+			}
+
+			return
+		}
+
+		device, err := ac.db.GetDevice(deviceID)
+		if err != nil {
+			jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+			if jsonErr != nil {
+				log.Error(jsonErr)
+			}
+
+			return
+		}
+
+		scene.AllowedDevices = append(scene.AllowedDevices, device)
+	}
+
+	scene.AllowedZones, err = parseZones(dd.AllowedZoneNames)
+	if err == nil {
+		err = checkZones(scene.AllowedZones, ac.md.GetDevices())
+	}
+
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseFieldError(fieldError{"AllowedZoneNames", err}))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	scene.ID, err = ac.md.AddScene(scene)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	ac.mq.PublishSceneDiscovery(scene)
+
+	re := getResponse("Scene created successfully")
+	re.Status = http.StatusCreated
+
+	jsonErr := json.NewEncoder(w).Encode(re)
+	if jsonErr != nil {
+		log.Error(jsonErr)
+	}
+}
+
+// Scene will return a Scene.
+func (ac APIController) Scene(w http.ResponseWriter, r *http.Request) {
+	sceneID, err := getSceneIDFromRequest(r)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	scene, err := ac.md.GetScene(sceneID)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	re := getResponseData()
+	re.Data = scene
+
+	jsonErr := json.NewEncoder(w).Encode(re)
+	if jsonErr != nil {
+		log.Error(jsonErr)
+	}
+}
+
+// SceneConfigure will update a Scene.
+func (ac APIController) SceneConfigure(w http.ResponseWriter, r *http.Request) {
+	sceneID, err := getSceneIDFromRequest(r)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	var dd sceneStrings
+
+	dec := json.NewDecoder(r.Body)
+
+	dec.DisallowUnknownFields()
+
+	err = dec.Decode(&dd)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	defer func() {
+		err := r.Body.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	scene := models.Scene{}
+
+	scene, err = ac.ss.Scene(dd, scene)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	for _, deviceIDString := range dd.AllowedDeviceIDs {
+		deviceID, err := strconv.Atoi(deviceIDString)
+		if err != nil {
+			jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+			if jsonErr != nil {
+				log.Error(jsonErr)
+			}
+
+			return
+		}
+
+		device, err := ac.db.GetDevice(deviceID)
+		if err != nil {
+			jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+			if jsonErr != nil {
+				log.Error(jsonErr)
+			}
+
+			return
+		}
+
+		scene.AllowedDevices = append(scene.AllowedDevices, device)
+	}
+
+	scene.AllowedZones, err = parseZones(dd.AllowedZoneNames)
+	if err == nil {
+		err = checkZones(scene.AllowedZones, ac.md.GetDevices())
+	}
+
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseFieldError(fieldError{"AllowedZoneNames", err}))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	scene.ID = sceneID
+
+	err = ac.md.SetScene(scene)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	ac.mq.PublishSceneDiscovery(scene)
+
+	re := getResponse("Scene configured successfully")
+	re.Status = http.StatusCreated
+
+	jsonErr := json.NewEncoder(w).Encode(re)
+	if jsonErr != nil {
+		log.Error(jsonErr)
+	}
+}
+
+// SceneRun will execute a Scene.
+func (ac APIController) SceneRun(w http.ResponseWriter, r *http.Request) {
+	sceneID, err := getSceneIDFromRequest(r)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	if !ac.mq.IsConnected() {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(errMQTTNotConnected.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	go ex.ExecuteSceneByID(sceneID)
+
+	jsonErr := json.NewEncoder(w).Encode(getResponse("Scene run successful"))
+	if jsonErr != nil {
+		log.Error(jsonErr)
+	}
+}
+
+// SceneDuplicate will duplicate a Scene.
+func (ac APIController) SceneDuplicate(w http.ResponseWriter, r *http.Request) {
+	sceneID, err := getSceneIDFromRequest(r)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	scene, err := ac.md.GetScene(sceneID)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	scene.Name += " (dupe)"
+
+	dupeSceneID, err := ac.md.AddScene(scene)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	groups, err := ac.md.GetGroups(sceneID)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	for _, group := range groups {
+		group.SceneID = dupeSceneID
+
+		dupeGroupID, err := ac.md.AddGroup(group)
+		if err != nil {
+			jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+			if jsonErr != nil {
+				log.Error(jsonErr)
+			}
+
+			return
+		}
+
+		actions, err := ac.md.GetActions(group.ID)
+		if err != nil {
+			jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+			if jsonErr != nil {
+				log.Error(jsonErr)
+			}
+
+			return
+		}
+
+		// duplicate actions.
+		for _, action := range actions {
+			action.GroupID = dupeGroupID
+
+			_, err := ac.md.AddAction(action)
+			if err != nil {
+				jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+				if jsonErr != nil {
+					log.Error(jsonErr)
+				}
+
+				return
+			}
+		}
+	}
+
+	ac.mq.PublishSceneDiscovery(models.Scene{ID: dupeSceneID, Name: scene.Name})
+
+	re := getResponse("Scene duplicated successfully")
+	re.Status = 204
+
+	jsonErr := json.NewEncoder(w).Encode(re)
+	if jsonErr != nil {
+		log.Error(jsonErr)
+	}
+}
+
+// SceneDelete will delete a Scene.
+func (ac APIController) SceneDelete(w http.ResponseWriter, r *http.Request) {
+	sceneID, err := getSceneIDFromRequest(r)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	err = ac.md.DeleteScene(sceneID)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	ac.mq.RemoveSceneDiscovery(sceneID)
+
+	re := getResponse("Scene deleted successfully")
+	re.Status = 204
+
+	jsonErr := json.NewEncoder(w).Encode(re)
+	if jsonErr != nil {
+		log.Error(jsonErr)
+	}
+}
+
+type groupStrings struct {
+	SceneID      string
+	Delay        string
+	GlobalDelay  string
+	FadeDuration string
+	FadeRate     string
+	FadeEasing   string
+	Order        string
+}
+
+// validateGroup checks the fade of a group.
+func validateGroup(group models.Group) error {
+	if group.FadeDuration < 0 {
+		return fieldError{"FadeDuration", errGroupFadeDuration}
+	}
+
+	if group.FadeRate < 0 || group.FadeRate > maxFadeRate {
+		return fieldError{"FadeRate", fmt.Errorf("%w: from 1 to %v steps per second", errGroupFadeRate, maxFadeRate)}
+	}
+
+	switch group.FadeEasing {
+	case "", fadeEasingLinear, fadeEasingEaseInOut, fadeEasingSine:
+	default:
+		return fieldError{"FadeEasing", fmt.Errorf("%w: %v", errGroupFadeEasing, group.FadeEasing)}
+	}
+
+	return nil
+}
+
+func getGroupIDFromRequest(r *http.Request) (int, error) {
+	var err error
+
+	var groupID int
+
+	v := mux.Vars(r)
+	groupIDString := v["groupID"]
+
+	if groupIDString == "" {
+		return groupID, errNoGroupID
+	}
+
+	groupIDInt, err := strconv.Atoi(groupIDString)
+	if err != nil {
+		return groupID, err
+	}
+
+	return groupIDInt, err
+}
+
+// SceneGroups will return a list of Group objects.
+func (ac APIController) SceneGroups(w http.ResponseWriter, r *http.Request) {
+	sceneID, err := getSceneIDFromRequest(r)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	groups, err := ac.md.GetGroups(sceneID)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	re := getResponseData()
+	re.Data = groups
+
+	jsonErr := json.NewEncoder(w).Encode(re)
+	if jsonErr != nil {
+		log.Error(jsonErr)
+	}
+}
+
+// SceneGroup will return a Group object.
+func (ac APIController) SceneGroup(w http.ResponseWriter, r *http.Request) {
+	groupID, err := getGroupIDFromRequest(r)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	group, err := ac.md.GetGroup(groupID)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	re := getResponseData()
+	re.Data = group
+
+	jsonErr := json.NewEncoder(w).Encode(re)
+	if jsonErr != nil {
+		log.Error(jsonErr)
+	}
+}
+
+// SceneGroupCreate will create a new Group.
+func (ac APIController) SceneGroupCreate(w http.ResponseWriter, r *http.Request) {
+	sceneID, err := getSceneIDFromRequest(r)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	var dd groupStrings
+
+	dec := json.NewDecoder(r.Body)
+
+	dec.DisallowUnknownFields()
+
+	err = dec.Decode(&dd)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	defer func() {
+		err := r.Body.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	group := models.Group{}
+
+	group, err = ac.ss.Group(dd, group)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	orderNext, err := ac.md.GetGroupOrderNext(sceneID)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	group.SceneID = sceneID
+	group.Order = orderNext
+
+	err = validateGroup(group)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseFieldError(err))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	_, err = ac.md.AddGroup(group)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	re := getResponse("Group created successfully")
+	re.Status = http.StatusCreated
+
+	jsonErr := json.NewEncoder(w).Encode(re)
+	if jsonErr != nil {
+		log.Error(jsonErr)
+	}
+}
+
+// SceneGroupConfigure will update a Group.
+func (ac APIController) SceneGroupConfigure(w http.ResponseWriter, r *http.Request) {
+	sceneID, err := getSceneIDFromRequest(r)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	groupID, err := getGroupIDFromRequest(r)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	var dd groupStrings
+
+	dec := json.NewDecoder(r.Body)
+
+	dec.DisallowUnknownFields()
+
+	err = dec.Decode(&dd)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	defer func() {
+		err := r.Body.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	group := models.Group{}
+
+	group, err = ac.ss.Group(dd, group)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	group.ID = groupID
+	group.SceneID = sceneID
+
+	err = validateGroup(group)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseFieldError(err))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	err = ac.md.SetGroup(group)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	re := getResponse("Group configured successfully")
+	re.Status = http.StatusCreated
+
+	jsonErr := json.NewEncoder(w).Encode(re)
+	if jsonErr != nil {
+		log.Error(jsonErr)
+	}
+}
+
+// SceneGroupRun will execute a Group.
+func (ac APIController) SceneGroupRun(w http.ResponseWriter, r *http.Request) {
+	groupID, err := getGroupIDFromRequest(r)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	if !ac.mq.IsConnected() {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(errMQTTNotConnected.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	go ex.ExecuteActionGroupByID(groupID)
+
+	jsonErr := json.NewEncoder(w).Encode(getResponse("Group run successful"))
+	if jsonErr != nil {
+		log.Error(jsonErr)
+	}
+}
+
+// SceneGroupDuplicate will duplicate a Group.
+func (ac APIController) SceneGroupDuplicate(w http.ResponseWriter, r *http.Request) {
+	sceneID, err := getSceneIDFromRequest(r)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	groupID, err := getGroupIDFromRequest(r)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	group, err := ac.md.GetGroup(groupID)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	actions, err := ac.md.GetActions(groupID)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	orderNext, err := ac.md.GetGroupOrderNext(sceneID)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	group.ID = 0
+	group.Order = orderNext
+
+	dupeGroupID, err := ac.md.AddGroup(group)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	// duplicate actions.
+	for _, action := range actions {
+		action.GroupID = dupeGroupID
+
+		_, err := ac.md.AddAction(action)
+		if err != nil {
+			jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+			if jsonErr != nil {
+				log.Error(jsonErr)
+			}
+
+			return
+		}
+	}
+
+	re := getResponse("Group duplicated successfully")
+	re.Status = 204
+
+	jsonErr := json.NewEncoder(w).Encode(re)
+	if jsonErr != nil {
+		log.Error(jsonErr)
+	}
+}
+
+// SceneGroupSort will change the sort order of a Group.
+func (ac APIController) SceneGroupSort(w http.ResponseWriter, r *http.Request) {
+	sceneID, err := getSceneIDFromRequest(r)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	groupID, err := getGroupIDFromRequest(r)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	sortID, err := getSortIDFromRequest(r)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	err = ac.md.SortGroup(sceneID, groupID, sortID)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	re := getResponse("Group sort order changed successfully")
+	re.Status = 204
+
+	jsonErr := json.NewEncoder(w).Encode(re)
+	if jsonErr != nil {
+		log.Error(jsonErr)
+	}
+}
+
+// SceneGroupDelete will delete a Group.
+func (ac APIController) SceneGroupDelete(w http.ResponseWriter, r *http.Request) {
+	groupID, err := getGroupIDFromRequest(r)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	group, err := ac.md.GetGroup(groupID)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	for _, a := range group.Actions {
+		err = ac.md.DeleteAction(a.ID)
+		if err != nil {
+			jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+			if jsonErr != nil {
+				log.Error(jsonErr)
+			}
+
+			return
+		}
+	}
+
+	err = ac.md.DeleteGroup(groupID)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	re := getResponse("Group deleted successfully")
+	re.Status = http.StatusNoContent
+
+	jsonErr := json.NewEncoder(w).Encode(re)
+	if jsonErr != nil {
+		log.Error(jsonErr)
+	}
+}
+
+type actionStrings struct {
+	GroupID         string
+	DeviceIDs       []string
+	ZoneNames       []string
+	Type            string
+	Command         string
+	Parameter       string
+	GlobalParameter string
+	Color           string
+	CT              string
+	Brightness      string
+	Transition      string
+	PaletteColor    string
+}
+
+func getActionIDFromRequest(r *http.Request) (int, error) {
+	var err error
+
+	var actionID int
+
+	v := mux.Vars(r)
+	actionIDString := v["actionID"]
+
+	if actionIDString == "" {
+		return actionID, errNoActionID
+	}
+
+	actionIDInt, err := strconv.Atoi(actionIDString)
+	if err != nil {
+		return actionID, err
+	}
+
+	return actionIDInt, err
+}
+
+// validateAction checks that the command is known to the type of every device of the action
+// and that the parameter is valid for it. Color actions are checked for the state they set.
+func (ac APIController) validateAction(action models.Action) error {
+	return validateActionDevices(action, ac.md.GetDevices())
+}
+
+// validateActionDevices is validateAction with all the devices that zones are resolved from.
+func validateActionDevices(action models.Action, all []models.Device) error {
+	if err := checkZones(action.Zones, all); err != nil {
+		return fieldError{"ZoneNames", err}
+	}
+
+	devices := zoneDevices(action.Devices, action.Zones, all)
+	if len(devices) == 0 {
+		return fieldError{"DeviceIDs", errActionDevices}
+	}
+
+	if action.PaletteColor != "" && action.PaletteColor != models.PaletteColorNext {
+		n, err := strconv.Atoi(action.PaletteColor)
+		if err != nil || n < 1 {
+			return fieldError{"PaletteColor", errActionPaletteColor}
+		}
+	}
+
+	switch action.Type {
+	case "", models.ActionTypeCommand:
+	case models.ActionTypeColor:
+		return validateColorAction(action)
+	default:
+		return fieldError{"Type", fmt.Errorf("%w: %v", errActionType, action.Type)}
+	}
+
+	for _, device := range devices {
+		command, ok := devicetypes.FindCommand(device.Type, action.Command)
+		if !ok {
+			return fieldError{
+				"Command", fmt.Errorf("%w: %v is not a command of %v", errActionCommand, action.Command, device.Name),
+			}
+		}
+
+		err := devicetypes.ValidateParameter(command, action.Parameter)
+		if err != nil {
+			return fieldError{"Parameter", err}
+		}
+
+		switch devicetypes.ParameterType(command) {
+		case devicetypes.ParameterColor, devicetypes.ParameterHSB:
+		default:
+			if action.PaletteColor != "" {
+				return fieldError{"PaletteColor", fmt.Errorf("%w: %v of %v", errActionPaletteCommand, action.Command, device.Name)}
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateColorAction checks the state a color action sets, which every device type can be
+// set to.
+func validateColorAction(action models.Action) error {
+	if action.Color == "" && action.CT == 0 && action.Brightness == 0 && action.PaletteColor == "" {
+		return fieldError{"Color", errActionColorEmpty}
+	}
+
+	if action.Color != "" {
+		if action.CT != 0 {
+			return fieldError{"CT", errActionColorAndCT}
+		}
+
+		_, err := colors.ParseRGB(action.Color)
+		if err != nil {
+			return fieldError{"Color", err}
+		}
+	}
+
+	if action.CT != 0 && (action.CT < colors.MinMireds || action.CT > colors.MaxMireds) {
+		return fieldError{"CT", fmt.Errorf("%w: from %v to %v", errActionCT, colors.MinMireds, colors.MaxMireds)}
+	}
+
+	const maxBrightness = 100
+	if action.Brightness < 0 || action.Brightness > maxBrightness {
+		return fieldError{"Brightness", fmt.Errorf("%w: from 0 to %v", errActionBrightness, maxBrightness)}
+	}
+
+	if action.Transition < 0 {
+		return fieldError{"Transition", errActionTransition}
+	}
+
+	return nil
+}
+
+// SceneGroupActions will return a list of Action objects.
+func (ac APIController) SceneGroupActions(w http.ResponseWriter, r *http.Request) {
+	groupID, err := getGroupIDFromRequest(r)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	actions, err := ac.md.GetActions(groupID)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	re := getResponseData()
+	re.Data = actions
+
+	jsonErr := json.NewEncoder(w).Encode(re)
+	if jsonErr != nil {
+		log.Error(jsonErr)
+	}
+}
+
+// SceneGroupAction will return an Action object.
+func (ac APIController) SceneGroupAction(w http.ResponseWriter, r *http.Request) {
+	actionID, err := getActionIDFromRequest(r)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	action, err := ac.md.GetAction(actionID)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	re := getResponseData()
+	re.Data = action
+
+	jsonErr := json.NewEncoder(w).Encode(re)
+	if jsonErr != nil {
+		log.Error(jsonErr)
+	}
+}
+
+// SceneGroupActionCreate will create a new Action.
+func (ac APIController) SceneGroupActionCreate(w http.ResponseWriter, r *http.Request) {
+	groupID, err := getGroupIDFromRequest(r)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	var dd actionStrings
+
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+
+	err = dec.Decode(&dd)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	defer func() {
+		err := r.Body.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	action := models.Action{}
+
+	action, err = ac.ss.Action(dd, action)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	orderNext, err := ac.md.GetActionOrderNext(groupID)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	action.GroupID = groupID
+	action.Order = orderNext
+
+	for _, deviceIDString := range dd.DeviceIDs {
+		deviceID, err := strconv.Atoi(deviceIDString)
+		if err != nil {
+			jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+			if jsonErr != nil {
+				log.Error(jsonErr)
+			}
+
+			return
+		}
+
+		device, err := ac.db.GetDevice(deviceID)
+		if err != nil {
+			jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+			if jsonErr != nil {
+				log.Error(jsonErr)
+			}
+
+			return
+		}
+
+		action.Devices = append(action.Devices, device)
+	}
+
+	action.Zones, err = parseZones(dd.ZoneNames)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseFieldError(fieldError{"ZoneNames", err}))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	err = ac.validateAction(action)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseFieldError(err))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	_, err = ac.md.AddAction(action)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	re := getResponse("Action created successfully")
+	re.Status = http.StatusCreated
+
+	jsonErr := json.NewEncoder(w).Encode(re)
+	if jsonErr != nil {
+		log.Error(jsonErr)
+	}
+}
+
+// SceneGroupActionEdit will update an Action.
+func (ac APIController) SceneGroupActionEdit(w http.ResponseWriter, r *http.Request) {
+	groupID, err := getGroupIDFromRequest(r)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	actionID, err := getActionIDFromRequest(r)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	var dd actionStrings
+
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+
+	err = dec.Decode(&dd)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	defer func() {
+		err := r.Body.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	action := models.Action{}
+
+	action, err = ac.ss.Action(dd, action)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	action.ID = actionID
+	action.GroupID = groupID
+
+	existingAction, err := ac.md.GetAction(actionID)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	action.Order = existingAction.Order
+
+	for _, deviceIDString := range dd.DeviceIDs {
+		deviceID, err := strconv.Atoi(deviceIDString)
+		if err != nil {
+			jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+			if jsonErr != nil {
+				log.Error(jsonErr)
+			}
+
+			return
+		}
+
+		device, err := ac.db.GetDevice(deviceID)
+		if err != nil {
+			jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+			if jsonErr != nil {
+				log.Error(jsonErr)
+			}
+
+			return
+		}
+
+		action.Devices = append(action.Devices, device)
+	}
+
+	action.Zones, err = parseZones(dd.ZoneNames)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseFieldError(fieldError{"ZoneNames", err}))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	err = ac.validateAction(action)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseFieldError(err))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	err = ac.md.SetAction(action)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	re := getResponse("Action updated successfully")
+	re.Status = http.StatusCreated
+
+	jsonErr := json.NewEncoder(w).Encode(re)
+	if jsonErr != nil {
+		log.Error(jsonErr)
+	}
+}
+
+// SceneGroupActionRun will execute an Action.
+func (ac APIController) SceneGroupActionRun(w http.ResponseWriter, r *http.Request) {
+	actionID, err := getActionIDFromRequest(r)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	if !ac.mq.IsConnected() {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(errMQTTNotConnected.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	go ex.ExecuteActionByID(actionID)
+
+	jsonErr := json.NewEncoder(w).Encode(getResponse("Action run successful"))
+	if jsonErr != nil {
+		log.Error(jsonErr)
+	}
 }
 
-func getSceneIDFromRequest(r *http.Request) (int, error) {
+// SceneGroupActionDuplicate will duplicate an Action.
+func (ac APIController) SceneGroupActionDuplicate(w http.ResponseWriter, r *http.Request) {
+	groupID, err := getGroupIDFromRequest(r)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	actionID, err := getActionIDFromRequest(r)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	action, err := ac.md.GetAction(actionID)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	orderNext, err := ac.md.GetActionOrderNext(groupID)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	action.ID = 0
+	action.Order = orderNext
+
+	_, err = ac.md.AddAction(action)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	re := getResponse("Action duplicated successfully")
+	re.Status = http.StatusNoContent
+
+	jsonErr := json.NewEncoder(w).Encode(re)
+	if jsonErr != nil {
+		log.Error(jsonErr)
+	}
+}
+
+func getSortIDFromRequest(r *http.Request) (int, error) {
 	var err error
 
-	var sceneID int
+	var sortID int
 
 	v := mux.Vars(r)
-	sceneIDString := v["sceneID"]
+	sortIDString := v["sortID"]
 
-	if sceneIDString == "" {
-		return sceneID, errNoSceneID
+	if sortIDString == "" {
+		return sortID, errNoSortID
 	}
 
-	sceneIDInt, err := strconv.Atoi(sceneIDString)
+	sortIDInt, err := strconv.Atoi(sortIDString)
 	if err != nil {
-		return sceneID, err
+		return sortID, err
+	}
+
+	return sortIDInt, err
+}
+
+// SceneGroupActionSort will change the sort order of an Action.
+func (ac APIController) SceneGroupActionSort(w http.ResponseWriter, r *http.Request) {
+	groupID, err := getGroupIDFromRequest(r)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	actionID, err := getActionIDFromRequest(r)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	sortID, err := getSortIDFromRequest(r)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	err = ac.md.SortAction(groupID, actionID, sortID)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	re := getResponse("Action sort order changed successfully")
+	re.Status = 204
+
+	jsonErr := json.NewEncoder(w).Encode(re)
+	if jsonErr != nil {
+		log.Error(jsonErr)
+	}
+}
+
+// SceneGroupActionDelete will delete an Action.
+func (ac APIController) SceneGroupActionDelete(w http.ResponseWriter, r *http.Request) {
+	actionID, err := getActionIDFromRequest(r)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	err = ac.md.DeleteAction(actionID)
+	if err != nil {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
 	}
 
-	return sceneIDInt, err
+	re := getResponse("Action deleted successfully")
+	re.Status = http.StatusNoContent
+
+	jsonErr := json.NewEncoder(w).Encode(re)
+	if jsonErr != nil {
+		log.Error(jsonErr)
+	}
 }
 
-// Scenes will return a list of Scene objects.
-func (ac APIController) Scenes(w http.ResponseWriter, r *http.Request) {
-	scenes, err := ac.md.GetScenes()
+// Export will return a Backup document of all devices, scenes and shows.
+func (ac APIController) Export(w http.ResponseWriter, r *http.Request) {
+	backup, err := ac.md.Export()
 	if err != nil {
 		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
 		if jsonErr != nil {
@@ -689,24 +2699,26 @@ func (ac APIController) Scenes(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	re := getResponseData()
-	re.Data = scenes
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", "attachment; filename=\"mq-lightshow-backup.json\"")
 
-	jsonErr := json.NewEncoder(w).Encode(re)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	jsonErr := enc.Encode(backup)
 	if jsonErr != nil {
 		log.Error(jsonErr)
 	}
 }
 
-// SceneCreate will create a new Scene.
-func (ac APIController) SceneCreate(w http.ResponseWriter, r *http.Request) {
-	var dd sceneStrings
+// Import will re-create the devices, scenes and shows of a Backup document.
+func (ac APIController) Import(w http.ResponseWriter, r *http.Request) {
+	var backup models.Backup
 
 	dec := json.NewDecoder(r.Body)
-
 	dec.DisallowUnknownFields()
 
-	err := dec.Decode(&dd)
+	err := dec.Decode(&backup)
 	if err != nil {
 		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
 		if jsonErr != nil {
@@ -723,9 +2735,7 @@ func (ac APIController) SceneCreate(w http.ResponseWriter, r *http.Request) {
 		}
 	}()
 
-	scene := models.Scene{}
-
-	scene, err = ac.ss.Scene(dd, scene)
+	err = ac.md.Import(backup)
 	if err != nil {
 		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
 		if jsonErr != nil {
@@ -735,32 +2745,95 @@ func (ac APIController) SceneCreate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	for _, deviceIDString := range dd.AllowedDeviceIDs {
-		deviceID, err := strconv.Atoi(deviceIDString)
-		if err != nil {
-			jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
-			if jsonErr != nil {
-				log.Error(jsonErr)
-			}
+	ac.mq.SubscribeDevices()
+	ac.mq.PublishDiscovery()
 
-			return
-		}
+	re := getResponse("Import completed successfully")
+	re.Status = http.StatusCreated
+
+	jsonErr := json.NewEncoder(w).Encode(re)
+	if jsonErr != nil {
+		log.Error(jsonErr)
+	}
+}
+
+type deviceStrings struct {
+	Name           string
+	Topic          string
+	FullTopic      string
+	Hostname       string
+	TypeID         string
+	SupportsDimmer string
+	SupportsColor  string
+	SupportsCT     string
+	ZoneNames      []string // the zones of an edited device are kept when not posted.
+}
+
+func getDeviceIDFromRequest(r *http.Request) (int, error) {
+	var err error
+
+	var deviceID int
+
+	v := mux.Vars(r)
+	deviceIDString := v["deviceID"]
 
-		scene.AllowedDevices = append(scene.AllowedDevices, ac.db.GetDevice(deviceID))
+	if deviceIDString == "" {
+		return deviceID, errNoDeviceID
 	}
 
-	_, err = ac.md.AddScene(scene)
+	deviceIDInt, err := strconv.Atoi(deviceIDString)
 	if err != nil {
-		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
-		if jsonErr != nil {
-			log.Error(jsonErr)
+		return deviceID, err
+	}
+
+	return deviceIDInt, err
+}
+
+// deviceFromStrings converts and validates posted device values. Values that are not posted
+// keep their value in device.
+func (ac APIController) deviceFromStrings(dd deviceStrings, device models.Device) (models.Device, error) {
+	device, err := ac.ss.Device(dd, device)
+	if err != nil {
+		return device, err
+	}
+
+	if device.Name == "" {
+		return device, errDeviceName
+	}
+
+	if device.Topic == "" {
+		return device, errDeviceTopic
+	}
+
+	if dd.ZoneNames != nil {
+		device.Zones, err = parseZones(dd.ZoneNames)
+		if err != nil {
+			return device, err
 		}
+	}
 
-		return
+	// an edit without a TypeID keeps the current type.
+	if dd.TypeID == "" && device.Type.ID != 0 {
+		return device, nil
 	}
 
-	re := getResponse("Scene created successfully")
-	re.Status = http.StatusCreated
+	typeID, err := strconv.Atoi(dd.TypeID)
+	if err != nil {
+		return device, fmt.Errorf("%w: %v", errDeviceType, dd.TypeID)
+	}
+
+	device.Type = ac.md.GetDeviceType(typeID)
+	if device.Type.ID == 0 {
+		return device, fmt.Errorf("%w: %v", errDeviceType, dd.TypeID)
+	}
+
+	return device, nil
+}
+
+// Devices will return a list of Device objects.
+func (ac APIController) Devices(w http.ResponseWriter, r *http.Request) {
+	re := getResponseData()
+	re.Data = ac.mq.WithHealth(ac.md.GetDevices())
 
 	jsonErr := json.NewEncoder(w).Encode(re)
 	if jsonErr != nil {
@@ -768,9 +2841,20 @@ func (ac APIController) SceneCreate(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// Scene will return a Scene.
-func (ac APIController) Scene(w http.ResponseWriter, r *http.Request) {
-	sceneID, err := getSceneIDFromRequest(r)
+// Zones will return the zones devices are in, with their devices.
+func (ac APIController) Zones(w http.ResponseWriter, r *http.Request) {
+	re := getResponseData()
+	re.Data = ac.md.GetZones()
+
+	jsonErr := json.NewEncoder(w).Encode(re)
+	if jsonErr != nil {
+		log.Error(jsonErr)
+	}
+}
+
+// Device will return a Device object.
+func (ac APIController) Device(w http.ResponseWriter, r *http.Request) {
+	deviceID, err := getDeviceIDFromRequest(r)
 	if err != nil {
 		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
 		if jsonErr != nil {
@@ -780,7 +2864,7 @@ func (ac APIController) Scene(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	scene, err := ac.md.GetScene(sceneID)
+	device, err := ac.md.GetDevice(deviceID)
 	if err != nil {
 		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
 		if jsonErr != nil {
@@ -791,7 +2875,7 @@ func (ac APIController) Scene(w http.ResponseWriter, r *http.Request) {
 	}
 
 	re := getResponseData()
-	re.Data = scene
+	re.Data = ac.mq.WithHealth([]models.Device{device})[0]
 
 	jsonErr := json.NewEncoder(w).Encode(re)
 	if jsonErr != nil {
@@ -799,25 +2883,14 @@ func (ac APIController) Scene(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// SceneConfigure will update a Scene.
-func (ac APIController) SceneConfigure(w http.ResponseWriter, r *http.Request) {
-	sceneID, err := getSceneIDFromRequest(r)
-	if err != nil {
-		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
-		if jsonErr != nil {
-			log.Error(jsonErr)
-		}
-
-		return
-	}
-
-	var dd sceneStrings
+// DeviceCreate will create a Device.
+func (ac APIController) DeviceCreate(w http.ResponseWriter, r *http.Request) {
+	var dd deviceStrings
 
 	dec := json.NewDecoder(r.Body)
-
 	dec.DisallowUnknownFields()
 
-	err = dec.Decode(&dd)
+	err := dec.Decode(&dd)
 	if err != nil {
 		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
 		if jsonErr != nil {
@@ -834,9 +2907,7 @@ func (ac APIController) SceneConfigure(w http.ResponseWriter, r *http.Request) {
 		}
 	}()
 
-	scene := models.Scene{}
-
-	scene, err = ac.ss.Scene(dd, scene)
+	device, err := ac.deviceFromStrings(dd, models.Device{})
 	if err != nil {
 		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
 		if jsonErr != nil {
@@ -846,23 +2917,7 @@ func (ac APIController) SceneConfigure(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	for _, deviceIDString := range dd.AllowedDeviceIDs {
-		deviceID, err := strconv.Atoi(deviceIDString)
-		if err != nil {
-			jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
-			if jsonErr != nil {
-				log.Error(jsonErr)
-			}
-
-			return
-		}
-
-		scene.AllowedDevices = append(scene.AllowedDevices, ac.db.GetDevice(deviceID))
-	}
-
-	scene.ID = sceneID
-
-	err = ac.md.SetScene(scene)
+	device.ID, err = ac.md.AddDevice(device)
 	if err != nil {
 		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
 		if jsonErr != nil {
@@ -872,8 +2927,12 @@ func (ac APIController) SceneConfigure(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	re := getResponse("Scene configured successfully")
+	ac.mq.SubscribeDevices()
+
+	re := getResponseData()
 	re.Status = http.StatusCreated
+	re.Message = "Device created successfully"
+	re.Data = device
 
 	jsonErr := json.NewEncoder(w).Encode(re)
 	if jsonErr != nil {
@@ -881,9 +2940,9 @@ func (ac APIController) SceneConfigure(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// SceneRun will execute a Scene.
-func (ac APIController) SceneRun(w http.ResponseWriter, r *http.Request) {
-	sceneID, err := getSceneIDFromRequest(r)
+// DeviceEdit will update a Device.
+func (ac APIController) DeviceEdit(w http.ResponseWriter, r *http.Request) {
+	deviceID, err := getDeviceIDFromRequest(r)
 	if err != nil {
 		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
 		if jsonErr != nil {
@@ -893,17 +2952,12 @@ func (ac APIController) SceneRun(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	go ex.ExecuteSceneByID(sceneID)
+	var dd deviceStrings
 
-	jsonErr := json.NewEncoder(w).Encode(getResponse("Scene run successful"))
-	if jsonErr != nil {
-		log.Error(jsonErr)
-	}
-}
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
 
-// SceneDuplicate will duplicate a Scene.
-func (ac APIController) SceneDuplicate(w http.ResponseWriter, r *http.Request) {
-	sceneID, err := getSceneIDFromRequest(r)
+	err = dec.Decode(&dd)
 	if err != nil {
 		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
 		if jsonErr != nil {
@@ -913,7 +2967,14 @@ func (ac APIController) SceneDuplicate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	scene, err := ac.md.GetScene(sceneID)
+	defer func() {
+		err := r.Body.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	device, err := ac.md.GetDevice(deviceID)
 	if err != nil {
 		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
 		if jsonErr != nil {
@@ -923,9 +2984,7 @@ func (ac APIController) SceneDuplicate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	scene.Name += " (dupe)"
-
-	dupeSceneID, err := ac.md.AddScene(scene)
+	device, err = ac.deviceFromStrings(dd, device)
 	if err != nil {
 		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
 		if jsonErr != nil {
@@ -935,10 +2994,9 @@ func (ac APIController) SceneDuplicate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	groups, err := ac.md.GetGroups(sceneID)
+	err = ac.md.SetDevice(device)
 	if err != nil {
 		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
-
 		if jsonErr != nil {
 			log.Error(jsonErr)
 		}
@@ -946,47 +3004,10 @@ func (ac APIController) SceneDuplicate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	for _, group := range groups {
-		group.SceneID = dupeSceneID
-
-		dupeGroupID, err := ac.md.AddGroup(group)
-		if err != nil {
-			jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
-			if jsonErr != nil {
-				log.Error(jsonErr)
-			}
-
-			return
-		}
-
-		actions, err := ac.md.GetActions(group.ID)
-		if err != nil {
-			jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
-			if jsonErr != nil {
-				log.Error(jsonErr)
-			}
-
-			return
-		}
-
-		// duplicate actions.
-		for _, action := range actions {
-			action.GroupID = dupeGroupID
+	ac.mq.SubscribeDevices()
 
-			_, err := ac.md.AddAction(action)
-			if err != nil {
-				jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
-				if jsonErr != nil {
-					log.Error(jsonErr)
-				}
-
-				return
-			}
-		}
-	}
-
-	re := getResponse("Scene duplicated successfully")
-	re.Status = 204
+	re := getResponse("Device updated successfully")
+	re.Status = http.StatusCreated
 
 	jsonErr := json.NewEncoder(w).Encode(re)
 	if jsonErr != nil {
@@ -994,9 +3015,9 @@ func (ac APIController) SceneDuplicate(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// SceneDelete will delete a Scene.
-func (ac APIController) SceneDelete(w http.ResponseWriter, r *http.Request) {
-	sceneID, err := getSceneIDFromRequest(r)
+// DeviceDelete will delete a Device.
+func (ac APIController) DeviceDelete(w http.ResponseWriter, r *http.Request) {
+	deviceID, err := getDeviceIDFromRequest(r)
 	if err != nil {
 		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
 		if jsonErr != nil {
@@ -1006,7 +3027,7 @@ func (ac APIController) SceneDelete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = ac.md.DeleteScene(sceneID)
+	err = ac.md.DeleteDevice(deviceID)
 	if err != nil {
 		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
 		if jsonErr != nil {
@@ -1016,8 +3037,10 @@ func (ac APIController) SceneDelete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	re := getResponse("Scene deleted successfully")
-	re.Status = 204
+	ac.mq.SubscribeDevices()
+
+	re := getResponse("Device deleted successfully")
+	re.Status = http.StatusNoContent
 
 	jsonErr := json.NewEncoder(w).Encode(re)
 	if jsonErr != nil {
@@ -1025,67 +3048,50 @@ func (ac APIController) SceneDelete(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-type groupStrings struct {
-	SceneID     string
-	Delay       string
-	GlobalDelay string
-	Order       string
-}
-
-func getGroupIDFromRequest(r *http.Request) (int, error) {
-	var err error
+// DeviceTypes will return a list of DeviceType objects.
+func (ac APIController) DeviceTypes(w http.ResponseWriter, r *http.Request) {
+	re := getResponseData()
+	re.Data = ac.md.GetDeviceTypes()
 
-	var groupID int
+	jsonErr := json.NewEncoder(w).Encode(re)
+	if jsonErr != nil {
+		log.Error(jsonErr)
+	}
+}
 
+func getTypeIDFromRequest(r *http.Request) (int, error) {
 	v := mux.Vars(r)
-	groupIDString := v["groupID"]
-
-	if groupIDString == "" {
-		return groupID, errNoGroupID
-	}
 
-	groupIDInt, err := strconv.Atoi(groupIDString)
-	if err != nil {
-		return groupID, err
+	typeIDString := v["typeID"]
+	if typeIDString == "" {
+		return 0, errNoTypeID
 	}
 
-	return groupIDInt, err
+	return strconv.Atoi(typeIDString)
 }
 
-// SceneGroups will return a list of Group objects.
-func (ac APIController) SceneGroups(w http.ResponseWriter, r *http.Request) {
-	sceneID, err := getSceneIDFromRequest(r)
-	if err != nil {
-		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
-		if jsonErr != nil {
-			log.Error(jsonErr)
-		}
-
-		return
-	}
+// decodeDeviceType reads a posted DeviceType.
+func decodeDeviceType(r *http.Request) (models.DeviceType, error) {
+	var dt models.DeviceType
 
-	groups, err := ac.md.GetGroups(sceneID)
-	if err != nil {
-		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
-		if jsonErr != nil {
-			log.Error(jsonErr)
+	defer func() {
+		err := r.Body.Close()
+		if err != nil {
+			log.Error(err.Error())
 		}
+	}()
 
-		return
-	}
-
-	re := getResponseData()
-	re.Data = groups
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
 
-	jsonErr := json.NewEncoder(w).Encode(re)
-	if jsonErr != nil {
-		log.Error(jsonErr)
-	}
-}
+	err := dec.Decode(&dt)
 
-// SceneGroup will return a Group object.
-func (ac APIController) SceneGroup(w http.ResponseWriter, r *http.Request) {
-	groupID, err := getGroupIDFromRequest(r)
+	return dt, err
+}
+
+// DeviceType will return a DeviceType object.
+func (ac APIController) DeviceType(w http.ResponseWriter, r *http.Request) {
+	typeID, err := getTypeIDFromRequest(r)
 	if err != nil {
 		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
 		if jsonErr != nil {
@@ -1095,9 +3101,9 @@ func (ac APIController) SceneGroup(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	group, err := ac.md.GetGroup(groupID)
-	if err != nil {
-		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+	dt, ok := ac.dt.GetDeviceType(typeID)
+	if !ok {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(fmt.Errorf("%w: %v", errDeviceType, typeID).Error()))
 		if jsonErr != nil {
 			log.Error(jsonErr)
 		}
@@ -1106,7 +3112,7 @@ func (ac APIController) SceneGroup(w http.ResponseWriter, r *http.Request) {
 	}
 
 	re := getResponseData()
-	re.Data = group
+	re.Data = dt
 
 	jsonErr := json.NewEncoder(w).Encode(re)
 	if jsonErr != nil {
@@ -1114,9 +3120,9 @@ func (ac APIController) SceneGroup(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// SceneGroupCreate will create a new Group.
-func (ac APIController) SceneGroupCreate(w http.ResponseWriter, r *http.Request) {
-	sceneID, err := getSceneIDFromRequest(r)
+// DeviceTypeCreate will create a user defined DeviceType.
+func (ac APIController) DeviceTypeCreate(w http.ResponseWriter, r *http.Request) {
+	dt, err := decodeDeviceType(r)
 	if err != nil {
 		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
 		if jsonErr != nil {
@@ -1126,13 +3132,7 @@ func (ac APIController) SceneGroupCreate(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	var dd groupStrings
-
-	dec := json.NewDecoder(r.Body)
-
-	dec.DisallowUnknownFields()
-
-	err = dec.Decode(&dd)
+	dt, err = ac.dt.AddDeviceType(dt)
 	if err != nil {
 		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
 		if jsonErr != nil {
@@ -1142,16 +3142,20 @@ func (ac APIController) SceneGroupCreate(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	defer func() {
-		err := r.Body.Close()
-		if err != nil {
-			log.Error(err.Error())
-		}
-	}()
+	re := getResponseData()
+	re.Status = http.StatusCreated
+	re.Message = "Device type created successfully"
+	re.Data = dt
 
-	group := models.Group{}
+	jsonErr := json.NewEncoder(w).Encode(re)
+	if jsonErr != nil {
+		log.Error(jsonErr)
+	}
+}
 
-	group, err = ac.ss.Group(dd, group)
+// DeviceTypeEdit will replace a user defined DeviceType.
+func (ac APIController) DeviceTypeEdit(w http.ResponseWriter, r *http.Request) {
+	typeID, err := getTypeIDFromRequest(r)
 	if err != nil {
 		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
 		if jsonErr != nil {
@@ -1161,7 +3165,7 @@ func (ac APIController) SceneGroupCreate(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	orderNext, err := ac.md.GetGroupOrderNext(sceneID)
+	dt, err := decodeDeviceType(r)
 	if err != nil {
 		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
 		if jsonErr != nil {
@@ -1171,10 +3175,9 @@ func (ac APIController) SceneGroupCreate(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	group.SceneID = sceneID
-	group.Order = orderNext
+	dt.ID = typeID
 
-	_, err = ac.md.AddGroup(group)
+	err = ac.dt.SetDeviceType(dt)
 	if err != nil {
 		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
 		if jsonErr != nil {
@@ -1184,7 +3187,7 @@ func (ac APIController) SceneGroupCreate(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	re := getResponse("Group created successfully")
+	re := getResponse("Device type updated successfully")
 	re.Status = http.StatusCreated
 
 	jsonErr := json.NewEncoder(w).Encode(re)
@@ -1193,9 +3196,9 @@ func (ac APIController) SceneGroupCreate(w http.ResponseWriter, r *http.Request)
 	}
 }
 
-// SceneGroupConfigure will update a Group.
-func (ac APIController) SceneGroupConfigure(w http.ResponseWriter, r *http.Request) {
-	sceneID, err := getSceneIDFromRequest(r)
+// DeviceTypeDelete will delete a user defined DeviceType that no device uses.
+func (ac APIController) DeviceTypeDelete(w http.ResponseWriter, r *http.Request) {
+	typeID, err := getTypeIDFromRequest(r)
 	if err != nil {
 		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
 		if jsonErr != nil {
@@ -1205,7 +3208,18 @@ func (ac APIController) SceneGroupConfigure(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	groupID, err := getGroupIDFromRequest(r)
+	for _, d := range ac.md.GetDevices() {
+		if d.Type.ID == typeID {
+			jsonErr := json.NewEncoder(w).Encode(getResponseError(fmt.Errorf("%w: %v", errDeviceTypeInUse, d.Name).Error()))
+			if jsonErr != nil {
+				log.Error(jsonErr)
+			}
+
+			return
+		}
+	}
+
+	err = ac.dt.DeleteDeviceType(typeID)
 	if err != nil {
 		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
 		if jsonErr != nil {
@@ -1215,13 +3229,40 @@ func (ac APIController) SceneGroupConfigure(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	var dd groupStrings
+	re := getResponse("Device type deleted successfully")
+	re.Status = http.StatusNoContent
 
-	dec := json.NewDecoder(r.Body)
+	jsonErr := json.NewEncoder(w).Encode(re)
+	if jsonErr != nil {
+		log.Error(jsonErr)
+	}
+}
+
+// DevicesDiscovered will return a list of announced devices that have not been added yet.
+func (ac APIController) DevicesDiscovered(w http.ResponseWriter, r *http.Request) {
+	re := getResponseData()
+	re.Data = ac.mq.GetDiscoveredDevices()
+
+	jsonErr := json.NewEncoder(w).Encode(re)
+	if jsonErr != nil {
+		log.Error(jsonErr)
+	}
+}
 
+type adoptStrings struct {
+	Topic  string
+	Name   string
+	TypeID string
+}
+
+// DeviceAdopt will add an announced device as a Device.
+func (ac APIController) DeviceAdopt(w http.ResponseWriter, r *http.Request) {
+	var dd adoptStrings
+
+	dec := json.NewDecoder(r.Body)
 	dec.DisallowUnknownFields()
 
-	err = dec.Decode(&dd)
+	err := dec.Decode(&dd)
 	if err != nil {
 		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
 		if jsonErr != nil {
@@ -1238,9 +3279,7 @@ func (ac APIController) SceneGroupConfigure(w http.ResponseWriter, r *http.Reque
 		}
 	}()
 
-	group := models.Group{}
-
-	group, err = ac.ss.Group(dd, group)
+	discovered, err := ac.mq.GetDiscoveredDevice(dd.Topic)
 	if err != nil {
 		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
 		if jsonErr != nil {
@@ -1250,10 +3289,38 @@ func (ac APIController) SceneGroupConfigure(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	group.ID = groupID
-	group.SceneID = sceneID
+	device := ac.mq.discoveredToDevice(discovered)
+	if dd.Name != "" {
+		device.Name = dd.Name
+	}
 
-	err = ac.md.SetGroup(group)
+	// announcements come from Tasmota, so that is the default type.
+	device.Type = ac.md.GetDeviceType(devicetypes.TypeTasmota)
+
+	if dd.TypeID != "" {
+		typeID, err := strconv.Atoi(dd.TypeID)
+		if err != nil {
+			jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+			if jsonErr != nil {
+				log.Error(jsonErr)
+			}
+
+			return
+		}
+
+		device.Type = ac.md.GetDeviceType(typeID)
+	}
+
+	if device.Type.ID == 0 {
+		jsonErr := json.NewEncoder(w).Encode(getResponseError(fmt.Errorf("%w: %v", errDeviceType, dd.TypeID).Error()))
+		if jsonErr != nil {
+			log.Error(jsonErr)
+		}
+
+		return
+	}
+
+	device.ID, err = ac.md.AddDevice(device)
 	if err != nil {
 		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
 		if jsonErr != nil {
@@ -1263,8 +3330,12 @@ func (ac APIController) SceneGroupConfigure(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	re := getResponse("Group configured successfully")
+	ac.mq.SubscribeDevices()
+
+	re := getResponseData()
 	re.Status = http.StatusCreated
+	re.Message = "Device adopted successfully"
+	re.Data = device
 
 	jsonErr := json.NewEncoder(w).Encode(re)
 	if jsonErr != nil {
@@ -1272,39 +3343,116 @@ func (ac APIController) SceneGroupConfigure(w http.ResponseWriter, r *http.Reque
 	}
 }
 
-// SceneGroupRun will execute a Group.
-func (ac APIController) SceneGroupRun(w http.ResponseWriter, r *http.Request) {
-	groupID, err := getGroupIDFromRequest(r)
+type effectStrings struct {
+	Name      string
+	Type      string
+	DeviceIDs []string
+	Palette   []string
+	Speed     string
+	Direction string
+	Duration  string
+}
+
+func getEffectIDFromRequest(r *http.Request) (int, error) {
+	v := mux.Vars(r)
+
+	effectIDString := v["effectID"]
+	if effectIDString == "" {
+		return 0, errNoEffectID
+	}
+
+	return strconv.Atoi(effectIDString)
+}
+
+// decodeEffect reads a posted Effect and checks it.
+func (ac APIController) decodeEffect(r *http.Request) (models.Effect, error) {
+	var dd effectStrings
+
+	defer func() {
+		err := r.Body.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+
+	err := dec.Decode(&dd)
 	if err != nil {
-		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
-		if jsonErr != nil {
-			log.Error(jsonErr)
+		return models.Effect{}, err
+	}
+
+	effect := models.Effect{Devices: []models.Device{}, Palette: []string{}}
+
+	effect, err = ac.ss.Effect(dd, effect)
+	if err != nil {
+		return effect, err
+	}
+
+	effect.Name = strings.TrimSpace(effect.Name)
+
+	for _, deviceIDString := range dd.DeviceIDs {
+		deviceID, err := strconv.Atoi(deviceIDString)
+		if err != nil {
+			return effect, fieldError{"DeviceIDs", err}
 		}
 
-		return
+		device, err := ac.md.GetDevice(deviceID)
+		if err != nil {
+			return effect, fieldError{"DeviceIDs", err}
+		}
+
+		effect.Devices = append(effect.Devices, device)
 	}
 
-	go ex.ExecuteActionGroupByID(groupID)
+	// colors are stored as hex so they can be kept in a list separated by spaces.
+	for _, p := range dd.Palette {
+		c, err := colors.ParseRGB(p)
+		if err != nil {
+			return effect, fieldError{"Palette", err}
+		}
 
-	jsonErr := json.NewEncoder(w).Encode(getResponse("Group run successful"))
-	if jsonErr != nil {
-		log.Error(jsonErr)
+		effect.Palette = append(effect.Palette, c.Hex())
 	}
+
+	return effect, validateEffect(effect)
 }
 
-// SceneGroupDuplicate will duplicate a Group.
-func (ac APIController) SceneGroupDuplicate(w http.ResponseWriter, r *http.Request) {
-	sceneID, err := getSceneIDFromRequest(r)
-	if err != nil {
-		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
-		if jsonErr != nil {
-			log.Error(jsonErr)
-		}
+// validateEffect checks the settings of an effect.
+func validateEffect(effect models.Effect) error {
+	if effect.Name == "" {
+		return fieldError{"Name", errEffectName}
+	}
 
-		return
+	if _, ok := getEffectGenerator(effect.Type); !ok {
+		return fieldError{"Type", fmt.Errorf("%w: %v", errEffectType, effect.Type)}
 	}
 
-	groupID, err := getGroupIDFromRequest(r)
+	switch effect.Direction {
+	case "", models.EffectForward, models.EffectReverse:
+	default:
+		return fieldError{"Direction", fmt.Errorf("%w: %v", errEffectDirection, effect.Direction)}
+	}
+
+	if len(effect.Devices) == 0 {
+		return fieldError{"DeviceIDs", errEffectDevices}
+	}
+
+	if effect.Speed < 0 || effect.Speed > maxEffectSpeed {
+		return fieldError{"Speed", errEffectSpeed}
+	}
+
+	if effect.Duration <= 0 {
+		return fieldError{"Duration", errEffectDuration}
+	}
+
+	return nil
+}
+
+// Effects will return a list of Effect objects.
+func (ac APIController) Effects(w http.ResponseWriter, r *http.Request) {
+	effects, err := ac.md.GetEffects()
 	if err != nil {
 		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
 		if jsonErr != nil {
@@ -1314,7 +3462,18 @@ func (ac APIController) SceneGroupDuplicate(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	group, err := ac.md.GetGroup(groupID)
+	re := getResponseData()
+	re.Data = effects
+
+	jsonErr := json.NewEncoder(w).Encode(re)
+	if jsonErr != nil {
+		log.Error(jsonErr)
+	}
+}
+
+// Effect will return an Effect object.
+func (ac APIController) Effect(w http.ResponseWriter, r *http.Request) {
+	effectID, err := getEffectIDFromRequest(r)
 	if err != nil {
 		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
 		if jsonErr != nil {
@@ -1324,7 +3483,7 @@ func (ac APIController) SceneGroupDuplicate(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	actions, err := ac.md.GetActions(groupID)
+	effect, err := ac.md.GetEffect(effectID)
 	if err != nil {
 		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
 		if jsonErr != nil {
@@ -1334,9 +3493,20 @@ func (ac APIController) SceneGroupDuplicate(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	orderNext, err := ac.md.GetGroupOrderNext(sceneID)
+	re := getResponseData()
+	re.Data = effect
+
+	jsonErr := json.NewEncoder(w).Encode(re)
+	if jsonErr != nil {
+		log.Error(jsonErr)
+	}
+}
+
+// EffectCreate will create a new Effect.
+func (ac APIController) EffectCreate(w http.ResponseWriter, r *http.Request) {
+	effect, err := ac.decodeEffect(r)
 	if err != nil {
-		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		jsonErr := json.NewEncoder(w).Encode(getResponseFieldError(err))
 		if jsonErr != nil {
 			log.Error(jsonErr)
 		}
@@ -1344,10 +3514,7 @@ func (ac APIController) SceneGroupDuplicate(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	group.ID = 0
-	group.Order = orderNext
-
-	dupeGroupID, err := ac.md.AddGroup(group)
+	effect.ID, err = ac.md.AddEffect(effect)
 	if err != nil {
 		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
 		if jsonErr != nil {
@@ -1357,23 +3524,10 @@ func (ac APIController) SceneGroupDuplicate(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	// duplicate actions.
-	for _, action := range actions {
-		action.GroupID = dupeGroupID
-
-		_, err := ac.md.AddAction(action)
-		if err != nil {
-			jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
-			if jsonErr != nil {
-				log.Error(jsonErr)
-			}
-
-			return
-		}
-	}
-
-	re := getResponse("Group duplicated successfully")
-	re.Status = 204
+	re := getResponseData()
+	re.Status = http.StatusCreated
+	re.Message = "Effect created successfully"
+	re.Data = effect
 
 	jsonErr := json.NewEncoder(w).Encode(re)
 	if jsonErr != nil {
@@ -1381,9 +3535,9 @@ func (ac APIController) SceneGroupDuplicate(w http.ResponseWriter, r *http.Reque
 	}
 }
 
-// SceneGroupSort will change the sort order of a Group.
-func (ac APIController) SceneGroupSort(w http.ResponseWriter, r *http.Request) {
-	sceneID, err := getSceneIDFromRequest(r)
+// EffectEdit will update an Effect.
+func (ac APIController) EffectEdit(w http.ResponseWriter, r *http.Request) {
+	effectID, err := getEffectIDFromRequest(r)
 	if err != nil {
 		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
 		if jsonErr != nil {
@@ -1393,9 +3547,9 @@ func (ac APIController) SceneGroupSort(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	groupID, err := getGroupIDFromRequest(r)
+	effect, err := ac.decodeEffect(r)
 	if err != nil {
-		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		jsonErr := json.NewEncoder(w).Encode(getResponseFieldError(err))
 		if jsonErr != nil {
 			log.Error(jsonErr)
 		}
@@ -1403,17 +3557,9 @@ func (ac APIController) SceneGroupSort(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	sortID, err := getSortIDFromRequest(r)
-	if err != nil {
-		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
-		if jsonErr != nil {
-			log.Error(jsonErr)
-		}
-
-		return
-	}
+	effect.ID = effectID
 
-	err = ac.md.SortGroup(sceneID, groupID, sortID)
+	err = ac.md.SetEffect(effect)
 	if err != nil {
 		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
 		if jsonErr != nil {
@@ -1423,8 +3569,8 @@ func (ac APIController) SceneGroupSort(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	re := getResponse("Group sort order changed successfully")
-	re.Status = 204
+	re := getResponse("Effect updated successfully")
+	re.Status = http.StatusCreated
 
 	jsonErr := json.NewEncoder(w).Encode(re)
 	if jsonErr != nil {
@@ -1432,9 +3578,9 @@ func (ac APIController) SceneGroupSort(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// SceneGroupDelete will delete a Group.
-func (ac APIController) SceneGroupDelete(w http.ResponseWriter, r *http.Request) {
-	groupID, err := getGroupIDFromRequest(r)
+// EffectDelete will delete an Effect that no show uses.
+func (ac APIController) EffectDelete(w http.ResponseWriter, r *http.Request) {
+	effectID, err := getEffectIDFromRequest(r)
 	if err != nil {
 		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
 		if jsonErr != nil {
@@ -1444,7 +3590,11 @@ func (ac APIController) SceneGroupDelete(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	group, err := ac.md.GetGroup(groupID)
+	used, err := ac.md.IsEffectUsed(effectID)
+	if err == nil && used {
+		err = errEffectInUse
+	}
+
 	if err != nil {
 		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
 		if jsonErr != nil {
@@ -1454,19 +3604,7 @@ func (ac APIController) SceneGroupDelete(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	for _, a := range group.Actions {
-		err = ac.md.DeleteAction(a.ID)
-		if err != nil {
-			jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
-			if jsonErr != nil {
-				log.Error(jsonErr)
-			}
-
-			return
-		}
-	}
-
-	err = ac.md.DeleteGroup(groupID)
+	err = ac.md.DeleteEffect(effectID)
 	if err != nil {
 		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
 		if jsonErr != nil {
@@ -1476,7 +3614,7 @@ func (ac APIController) SceneGroupDelete(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	re := getResponse("Group deleted successfully")
+	re := getResponse("Effect deleted successfully")
 	re.Status = http.StatusNoContent
 
 	jsonErr := json.NewEncoder(w).Encode(re)
@@ -1485,47 +3623,71 @@ func (ac APIController) SceneGroupDelete(w http.ResponseWriter, r *http.Request)
 	}
 }
 
-type actionStrings struct {
-	GroupID         string
-	DeviceIDs       []string
-	Command         string
-	Parameter       string
-	GlobalParameter string
-}
-
-func getActionIDFromRequest(r *http.Request) (int, error) {
-	var err error
-
-	var actionID int
+// paletteRequest is a posted Palette.
+type paletteRequest struct {
+	Name   string
+	Colors []string
+}
 
+func getPaletteIDFromRequest(r *http.Request) (int, error) {
 	v := mux.Vars(r)
-	actionIDString := v["actionID"]
 
-	if actionIDString == "" {
-		return actionID, errNoActionID
+	paletteIDString := v["paletteID"]
+	if paletteIDString == "" {
+		return 0, errNoPaletteID
 	}
 
-	actionIDInt, err := strconv.Atoi(actionIDString)
+	return strconv.Atoi(paletteIDString)
+}
+
+// decodePalette reads a posted Palette and checks it.
+func decodePalette(r *http.Request) (models.Palette, error) {
+	var pr paletteRequest
+
+	defer func() {
+		err := r.Body.Close()
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}()
+
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+
+	err := dec.Decode(&pr)
 	if err != nil {
-		return actionID, err
+		return models.Palette{}, err
 	}
 
-	return actionIDInt, err
-}
+	palette := models.Palette{
+		Name:   strings.TrimSpace(pr.Name),
+		Colors: []string{},
+	}
 
-// SceneGroupActions will return a list of Action objects.
-func (ac APIController) SceneGroupActions(w http.ResponseWriter, r *http.Request) {
-	groupID, err := getGroupIDFromRequest(r)
-	if err != nil {
-		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
-		if jsonErr != nil {
-			log.Error(jsonErr)
+	if palette.Name == "" {
+		return palette, fieldError{"Name", errPaletteName}
+	}
+
+	if len(pr.Colors) == 0 {
+		return palette, fieldError{"Colors", errPaletteColors}
+	}
+
+	// colors are stored as hex so they can be kept in a list separated by spaces.
+	for _, c := range pr.Colors {
+		rgb, err := colors.ParseRGB(c)
+		if err != nil {
+			return palette, fieldError{"Colors", err}
 		}
 
-		return
+		palette.Colors = append(palette.Colors, rgb.Hex())
 	}
 
-	actions, err := ac.md.GetActions(groupID)
+	return palette, nil
+}
+
+// Palettes will return a list of Palette objects.
+func (ac APIController) Palettes(w http.ResponseWriter, r *http.Request) {
+	palettes, err := ac.md.GetPalettes()
 	if err != nil {
 		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
 		if jsonErr != nil {
@@ -1536,7 +3698,7 @@ func (ac APIController) SceneGroupActions(w http.ResponseWriter, r *http.Request
 	}
 
 	re := getResponseData()
-	re.Data = actions
+	re.Data = palettes
 
 	jsonErr := json.NewEncoder(w).Encode(re)
 	if jsonErr != nil {
@@ -1544,9 +3706,9 @@ func (ac APIController) SceneGroupActions(w http.ResponseWriter, r *http.Request
 	}
 }
 
-// SceneGroupAction will return an Action object.
-func (ac APIController) SceneGroupAction(w http.ResponseWriter, r *http.Request) {
-	actionID, err := getActionIDFromRequest(r)
+// Palette will return a Palette object.
+func (ac APIController) Palette(w http.ResponseWriter, r *http.Request) {
+	paletteID, err := getPaletteIDFromRequest(r)
 	if err != nil {
 		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
 		if jsonErr != nil {
@@ -1556,7 +3718,7 @@ func (ac APIController) SceneGroupAction(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	action, err := ac.md.GetAction(actionID)
+	palette, err := ac.md.GetPalette(paletteID)
 	if err != nil {
 		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
 		if jsonErr != nil {
@@ -1567,7 +3729,7 @@ func (ac APIController) SceneGroupAction(w http.ResponseWriter, r *http.Request)
 	}
 
 	re := getResponseData()
-	re.Data = action
+	re.Data = palette
 
 	jsonErr := json.NewEncoder(w).Encode(re)
 	if jsonErr != nil {
@@ -1575,11 +3737,11 @@ func (ac APIController) SceneGroupAction(w http.ResponseWriter, r *http.Request)
 	}
 }
 
-// SceneGroupActionCreate will create a new Action.
-func (ac APIController) SceneGroupActionCreate(w http.ResponseWriter, r *http.Request) {
-	groupID, err := getGroupIDFromRequest(r)
+// PaletteCreate will create a new Palette.
+func (ac APIController) PaletteCreate(w http.ResponseWriter, r *http.Request) {
+	palette, err := decodePalette(r)
 	if err != nil {
-		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		jsonErr := json.NewEncoder(w).Encode(getResponseFieldError(err))
 		if jsonErr != nil {
 			log.Error(jsonErr)
 		}
@@ -1587,12 +3749,7 @@ func (ac APIController) SceneGroupActionCreate(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	var dd actionStrings
-
-	dec := json.NewDecoder(r.Body)
-	dec.DisallowUnknownFields()
-
-	err = dec.Decode(&dd)
+	palette.ID, err = ac.md.AddPalette(palette)
 	if err != nil {
 		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
 		if jsonErr != nil {
@@ -1602,16 +3759,20 @@ func (ac APIController) SceneGroupActionCreate(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	defer func() {
-		err := r.Body.Close()
-		if err != nil {
-			log.Error(err.Error())
-		}
-	}()
+	re := getResponseData()
+	re.Status = http.StatusCreated
+	re.Message = "Palette created successfully"
+	re.Data = palette
 
-	action := models.Action{}
+	jsonErr := json.NewEncoder(w).Encode(re)
+	if jsonErr != nil {
+		log.Error(jsonErr)
+	}
+}
 
-	action, err = ac.ss.Action(dd, action)
+// PaletteEdit will update a Palette.
+func (ac APIController) PaletteEdit(w http.ResponseWriter, r *http.Request) {
+	paletteID, err := getPaletteIDFromRequest(r)
 	if err != nil {
 		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
 		if jsonErr != nil {
@@ -1621,9 +3782,9 @@ func (ac APIController) SceneGroupActionCreate(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	orderNext, err := ac.md.GetActionOrderNext(groupID)
+	palette, err := decodePalette(r)
 	if err != nil {
-		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		jsonErr := json.NewEncoder(w).Encode(getResponseFieldError(err))
 		if jsonErr != nil {
 			log.Error(jsonErr)
 		}
@@ -1631,24 +3792,9 @@ func (ac APIController) SceneGroupActionCreate(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	action.GroupID = groupID
-	action.Order = orderNext
-
-	for _, deviceIDString := range dd.DeviceIDs {
-		deviceID, err := strconv.Atoi(deviceIDString)
-		if err != nil {
-			jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
-			if jsonErr != nil {
-				log.Error(jsonErr)
-			}
-
-			return
-		}
-
-		action.Devices = append(action.Devices, ac.db.GetDevice(deviceID))
-	}
+	palette.ID = paletteID
 
-	_, err = ac.md.AddAction(action)
+	err = ac.md.SetPalette(palette)
 	if err != nil {
 		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
 		if jsonErr != nil {
@@ -1658,7 +3804,7 @@ func (ac APIController) SceneGroupActionCreate(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	re := getResponse("Action created successfully")
+	re := getResponse("Palette updated successfully")
 	re.Status = http.StatusCreated
 
 	jsonErr := json.NewEncoder(w).Encode(re)
@@ -1667,9 +3813,9 @@ func (ac APIController) SceneGroupActionCreate(w http.ResponseWriter, r *http.Re
 	}
 }
 
-// SceneGroupActionEdit will update an Action.
-func (ac APIController) SceneGroupActionEdit(w http.ResponseWriter, r *http.Request) {
-	groupID, err := getGroupIDFromRequest(r)
+// PaletteDelete will delete a Palette that no show uses.
+func (ac APIController) PaletteDelete(w http.ResponseWriter, r *http.Request) {
+	paletteID, err := getPaletteIDFromRequest(r)
 	if err != nil {
 		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
 		if jsonErr != nil {
@@ -1679,7 +3825,11 @@ func (ac APIController) SceneGroupActionEdit(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	actionID, err := getActionIDFromRequest(r)
+	used, err := ac.md.IsPaletteUsed(paletteID)
+	if err == nil && used {
+		err = errPaletteInUse
+	}
+
 	if err != nil {
 		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
 		if jsonErr != nil {
@@ -1689,12 +3839,7 @@ func (ac APIController) SceneGroupActionEdit(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	var dd actionStrings
-
-	dec := json.NewDecoder(r.Body)
-	dec.DisallowUnknownFields()
-
-	err = dec.Decode(&dd)
+	err = ac.md.DeletePalette(paletteID)
 	if err != nil {
 		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
 		if jsonErr != nil {
@@ -1704,6 +3849,42 @@ func (ac APIController) SceneGroupActionEdit(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	re := getResponse("Palette deleted successfully")
+	re.Status = http.StatusNoContent
+
+	jsonErr := json.NewEncoder(w).Encode(re)
+	if jsonErr != nil {
+		log.Error(jsonErr)
+	}
+}
+
+type scheduleStrings struct {
+	Name      string
+	ShowID    string
+	Enabled   string
+	Cron      string
+	Days      []string
+	StartTime string
+	StopTime  string
+	StartDate string
+	EndDate   string
+}
+
+func getScheduleIDFromRequest(r *http.Request) (int, error) {
+	v := mux.Vars(r)
+
+	scheduleIDString := v["scheduleID"]
+	if scheduleIDString == "" {
+		return 0, errNoScheduleID
+	}
+
+	return strconv.Atoi(scheduleIDString)
+}
+
+// decodeSchedule reads a posted Schedule and checks it.
+func (ac APIController) decodeSchedule(r *http.Request) (models.Schedule, error) {
+	var dd scheduleStrings
+
 	defer func() {
 		err := r.Body.Close()
 		if err != nil {
@@ -1711,69 +3892,105 @@ func (ac APIController) SceneGroupActionEdit(w http.ResponseWriter, r *http.Requ
 		}
 	}()
 
-	action := models.Action{}
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
 
-	action, err = ac.ss.Action(dd, action)
+	err := dec.Decode(&dd)
 	if err != nil {
-		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
-		if jsonErr != nil {
-			log.Error(jsonErr)
+		return models.Schedule{}, err
+	}
+
+	schedule := models.Schedule{Days: []int{}}
+
+	schedule, err = ac.ss.Schedule(dd, schedule)
+	if err != nil {
+		return schedule, err
+	}
+
+	schedule.Name = strings.TrimSpace(schedule.Name)
+	schedule.Cron = strings.TrimSpace(schedule.Cron)
+	schedule.StartTime = strings.TrimSpace(schedule.StartTime)
+	schedule.StopTime = strings.TrimSpace(schedule.StopTime)
+	schedule.StartDate = strings.TrimSpace(schedule.StartDate)
+	schedule.EndDate = strings.TrimSpace(schedule.EndDate)
+
+	for _, dayString := range dd.Days {
+		day, err := strconv.Atoi(dayString)
+		if err != nil {
+			return schedule, fieldError{"Days", err}
 		}
 
-		return
+		schedule.Days = append(schedule.Days, day)
 	}
 
-	action.ID = actionID
-	action.GroupID = groupID
+	if schedule.Name == "" {
+		return schedule, fieldError{"Name", errScheduleName}
+	}
 
-	existingAction, err := ac.md.GetAction(actionID)
+	show, err := ac.md.GetShow(schedule.ShowID)
 	if err != nil {
-		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
-		if jsonErr != nil {
-			log.Error(jsonErr)
-		}
+		return schedule, fieldError{"ShowID", err}
+	}
 
-		return
+	schedule.ShowName = show.Name
+
+	return schedule, validateSchedule(schedule, ac.st)
+}
+
+// validateSchedule checks the rules of a schedule.
+func validateSchedule(schedule models.Schedule, st scheduleTimes) error {
+	if schedule.Cron == "" && schedule.StartTime == "" && schedule.StopTime == "" {
+		return fieldError{"Cron", errScheduleStart}
 	}
 
-	action.Order = existingAction.Order
+	if schedule.Cron != "" && schedule.StartTime != "" {
+		return fieldError{"Cron", errScheduleCronAndTime}
+	}
 
-	for _, deviceIDString := range dd.DeviceIDs {
-		deviceID, err := strconv.Atoi(deviceIDString)
-		if err != nil {
-			jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
-			if jsonErr != nil {
-				log.Error(jsonErr)
-			}
+	if schedule.Cron != "" {
+		if _, err := cron.Parse(schedule.Cron); err != nil {
+			return fieldError{"Cron", err}
+		}
+	}
 
-			return
+	if schedule.StartTime != "" {
+		if err := st.check(schedule.StartTime); err != nil {
+			return fieldError{"StartTime", err}
 		}
+	}
 
-		action.Devices = append(action.Devices, ac.db.GetDevice(deviceID))
+	if schedule.StopTime != "" {
+		if err := st.check(schedule.StopTime); err != nil {
+			return fieldError{"StopTime", err}
+		}
 	}
 
-	err = ac.md.SetAction(action)
-	if err != nil {
-		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
-		if jsonErr != nil {
-			log.Error(jsonErr)
+	const saturday = 6
+
+	for _, d := range schedule.Days {
+		if d < 0 || d > saturday {
+			return fieldError{"Days", fmt.Errorf("%w: %v", errScheduleDay, d)}
 		}
+	}
 
-		return
+	if (schedule.StartDate == "") != (schedule.EndDate == "") {
+		return fieldError{"StartDate", errScheduleDates}
 	}
 
-	re := getResponse("Action updated successfully")
-	re.Status = http.StatusCreated
+	if _, err := time.Parse(scheduleDateFormat, schedule.StartDate); schedule.StartDate != "" && err != nil {
+		return fieldError{"StartDate", errScheduleDate}
+	}
 
-	jsonErr := json.NewEncoder(w).Encode(re)
-	if jsonErr != nil {
-		log.Error(jsonErr)
+	if _, err := time.Parse(scheduleDateFormat, schedule.EndDate); schedule.EndDate != "" && err != nil {
+		return fieldError{"EndDate", errScheduleDate}
 	}
+
+	return nil
 }
 
-// SceneGroupActionRun will execute an Action.
-func (ac APIController) SceneGroupActionRun(w http.ResponseWriter, r *http.Request) {
-	actionID, err := getActionIDFromRequest(r)
+// Schedules will return a list of Schedule objects.
+func (ac APIController) Schedules(w http.ResponseWriter, r *http.Request) {
+	schedules, err := ac.md.GetSchedules()
 	if err != nil {
 		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
 		if jsonErr != nil {
@@ -1783,17 +4000,18 @@ func (ac APIController) SceneGroupActionRun(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	go ex.ExecuteActionByID(actionID)
+	re := getResponseData()
+	re.Data = schedules
 
-	jsonErr := json.NewEncoder(w).Encode(getResponse("Action run successful"))
+	jsonErr := json.NewEncoder(w).Encode(re)
 	if jsonErr != nil {
 		log.Error(jsonErr)
 	}
 }
 
-// SceneGroupActionDuplicate will duplicate an Action.
-func (ac APIController) SceneGroupActionDuplicate(w http.ResponseWriter, r *http.Request) {
-	groupID, err := getGroupIDFromRequest(r)
+// Schedule will return a Schedule object.
+func (ac APIController) Schedule(w http.ResponseWriter, r *http.Request) {
+	scheduleID, err := getScheduleIDFromRequest(r)
 	if err != nil {
 		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
 		if jsonErr != nil {
@@ -1803,7 +4021,7 @@ func (ac APIController) SceneGroupActionDuplicate(w http.ResponseWriter, r *http
 		return
 	}
 
-	actionID, err := getActionIDFromRequest(r)
+	schedule, err := ac.md.GetSchedule(scheduleID)
 	if err != nil {
 		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
 		if jsonErr != nil {
@@ -1813,19 +4031,20 @@ func (ac APIController) SceneGroupActionDuplicate(w http.ResponseWriter, r *http
 		return
 	}
 
-	action, err := ac.md.GetAction(actionID)
-	if err != nil {
-		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
-		if jsonErr != nil {
-			log.Error(jsonErr)
-		}
+	re := getResponseData()
+	re.Data = schedule
 
-		return
+	jsonErr := json.NewEncoder(w).Encode(re)
+	if jsonErr != nil {
+		log.Error(jsonErr)
 	}
+}
 
-	orderNext, err := ac.md.GetActionOrderNext(groupID)
+// ScheduleCreate will create a new Schedule.
+func (ac APIController) ScheduleCreate(w http.ResponseWriter, r *http.Request) {
+	schedule, err := ac.decodeSchedule(r)
 	if err != nil {
-		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		jsonErr := json.NewEncoder(w).Encode(getResponseFieldError(err))
 		if jsonErr != nil {
 			log.Error(jsonErr)
 		}
@@ -1833,10 +4052,7 @@ func (ac APIController) SceneGroupActionDuplicate(w http.ResponseWriter, r *http
 		return
 	}
 
-	action.ID = 0
-	action.Order = orderNext
-
-	_, err = ac.md.AddAction(action)
+	schedule.ID, err = ac.md.AddSchedule(schedule)
 	if err != nil {
 		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
 		if jsonErr != nil {
@@ -1846,8 +4062,10 @@ func (ac APIController) SceneGroupActionDuplicate(w http.ResponseWriter, r *http
 		return
 	}
 
-	re := getResponse("Action duplicated successfully")
-	re.Status = http.StatusNoContent
+	re := getResponseData()
+	re.Status = http.StatusCreated
+	re.Message = "Schedule created successfully"
+	re.Data = schedule
 
 	jsonErr := json.NewEncoder(w).Encode(re)
 	if jsonErr != nil {
@@ -1855,29 +4073,9 @@ func (ac APIController) SceneGroupActionDuplicate(w http.ResponseWriter, r *http
 	}
 }
 
-func getSortIDFromRequest(r *http.Request) (int, error) {
-	var err error
-
-	var sortID int
-
-	v := mux.Vars(r)
-	sortIDString := v["sortID"]
-
-	if sortIDString == "" {
-		return sortID, errNoSortID
-	}
-
-	sortIDInt, err := strconv.Atoi(sortIDString)
-	if err != nil {
-		return sortID, err
-	}
-
-	return sortIDInt, err
-}
-
-// SceneGroupActionSort will change the sort order of an Action.
-func (ac APIController) SceneGroupActionSort(w http.ResponseWriter, r *http.Request) {
-	groupID, err := getGroupIDFromRequest(r)
+// ScheduleEdit will update a Schedule.
+func (ac APIController) ScheduleEdit(w http.ResponseWriter, r *http.Request) {
+	scheduleID, err := getScheduleIDFromRequest(r)
 	if err != nil {
 		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
 		if jsonErr != nil {
@@ -1887,9 +4085,9 @@ func (ac APIController) SceneGroupActionSort(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	actionID, err := getActionIDFromRequest(r)
+	schedule, err := ac.decodeSchedule(r)
 	if err != nil {
-		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
+		jsonErr := json.NewEncoder(w).Encode(getResponseFieldError(err))
 		if jsonErr != nil {
 			log.Error(jsonErr)
 		}
@@ -1897,17 +4095,9 @@ func (ac APIController) SceneGroupActionSort(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	sortID, err := getSortIDFromRequest(r)
-	if err != nil {
-		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
-		if jsonErr != nil {
-			log.Error(jsonErr)
-		}
-
-		return
-	}
+	schedule.ID = scheduleID
 
-	err = ac.md.SortAction(groupID, actionID, sortID)
+	err = ac.md.SetSchedule(schedule)
 	if err != nil {
 		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
 		if jsonErr != nil {
@@ -1917,8 +4107,8 @@ func (ac APIController) SceneGroupActionSort(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	re := getResponse("Action sort order changed successfully")
-	re.Status = 204
+	re := getResponse("Schedule updated successfully")
+	re.Status = http.StatusCreated
 
 	jsonErr := json.NewEncoder(w).Encode(re)
 	if jsonErr != nil {
@@ -1926,9 +4116,9 @@ func (ac APIController) SceneGroupActionSort(w http.ResponseWriter, r *http.Requ
 	}
 }
 
-// SceneGroupActionDelete will delete an Action.
-func (ac APIController) SceneGroupActionDelete(w http.ResponseWriter, r *http.Request) {
-	actionID, err := getActionIDFromRequest(r)
+// ScheduleDelete will delete a Schedule. A show it started keeps running.
+func (ac APIController) ScheduleDelete(w http.ResponseWriter, r *http.Request) {
+	scheduleID, err := getScheduleIDFromRequest(r)
 	if err != nil {
 		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
 		if jsonErr != nil {
@@ -1938,7 +4128,7 @@ func (ac APIController) SceneGroupActionDelete(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	err = ac.md.DeleteAction(actionID)
+	err = ac.md.DeleteSchedule(scheduleID)
 	if err != nil {
 		jsonErr := json.NewEncoder(w).Encode(getResponseError(err.Error()))
 		if jsonErr != nil {
@@ -1948,7 +4138,7 @@ func (ac APIController) SceneGroupActionDelete(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	re := getResponse("Action deleted successfully")
+	re := getResponse("Schedule deleted successfully")
 	re.Status = http.StatusNoContent
 
 	jsonErr := json.NewEncoder(w).Encode(re)
@@ -0,0 +1,110 @@
+package main_test
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	main "github.com/lovesway/hassio-addons/mq-lightshow"
+	"github.com/lovesway/hassio-addons/mq-lightshow/devicetypes"
+	"github.com/lovesway/hassio-addons/mq-lightshow/models"
+)
+
+func TestDecodePalette(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		body  string
+		want  []string
+		field string // the field of the error, empty when the palette is valid.
+		err   bool
+	}{
+		{body: `{"Name":"warm","Colors":["255,128,0","#ff0000"]}`, want: []string{"#FF8000", "#FF0000"}},
+		{body: `{"Name":" ","Colors":["#FF0000"]}`, field: "Name", err: true},
+		{body: `{"Name":"warm","Colors":[]}`, field: "Colors", err: true},
+		{body: `{"Name":"warm","Colors":["orange"]}`, field: "Colors", err: true},
+		{body: `{"Name":"warm","Colours":["#FF0000"]}`, err: true},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest("POST", "/api/palettes", strings.NewReader(tt.body))
+
+		palette, err := main.DecodePalette(r)
+		if (err != nil) != tt.err || main.ErrorField(err) != tt.field {
+			t.Errorf("decodePalette(%v) error = %v, want error %v about %q", tt.body, err, tt.err, tt.field)
+
+			continue
+		}
+
+		if !tt.err && !reflect.DeepEqual(palette.Colors, tt.want) {
+			t.Errorf("decodePalette(%v) colors = %v, want %v", tt.body, palette.Colors, tt.want)
+		}
+	}
+}
+
+func TestValidateActionPaletteColor(t *testing.T) {
+	t.Parallel()
+
+	device := models.Device{Name: "lamp", Type: models.DeviceType{Commands: []models.Command{
+		{Name: devicetypes.CommandColor, Type: devicetypes.ParameterColor},
+		{Name: devicetypes.CommandDimmer, Type: devicetypes.ParameterInt, Max: 100},
+	}}}
+
+	tests := []struct {
+		name   string
+		action models.Action
+		field  string // the field of the error, empty when the action is valid.
+	}{
+		{name: "numbered color", action: models.Action{Command: devicetypes.CommandColor, PaletteColor: "2"}},
+		{name: "next color", action: models.Action{Command: devicetypes.CommandColor, PaletteColor: "next"}},
+		{name: "color action", action: models.Action{Type: models.ActionTypeColor, PaletteColor: "1"}},
+		{name: "zero", action: models.Action{Command: devicetypes.CommandColor, PaletteColor: "0"}, field: "PaletteColor"},
+		{name: "name", action: models.Action{Command: devicetypes.CommandColor, PaletteColor: "red"}, field: "PaletteColor"},
+		{
+			name:   "command without a color",
+			action: models.Action{Command: devicetypes.CommandDimmer, Parameter: "50", PaletteColor: "1"},
+			field:  "PaletteColor",
+		},
+	}
+
+	for _, tt := range tests {
+		tt.action.Devices = []models.Device{device}
+
+		err := main.ValidateAction(tt.action, nil)
+		if (err != nil) != (tt.field != "") || main.ErrorField(err) != tt.field {
+			t.Errorf("%v: validateAction error = %v, want an error about %q", tt.name, err, tt.field)
+		}
+	}
+}
+
+func TestValidateActionZones(t *testing.T) {
+	t.Parallel()
+
+	commands := []models.Command{{Name: devicetypes.CommandDimmer, Type: devicetypes.ParameterInt, Max: 100}}
+	lamp := models.Device{ID: 1, Name: "lamp", Zones: []string{"porch"}, Type: models.DeviceType{Commands: commands}}
+	relay := models.Device{ID: 2, Name: "relay", Zones: []string{"garden"}}
+	all := []models.Device{lamp, relay}
+
+	tests := []struct {
+		name   string
+		action models.Action
+		field  string // the field of the error, empty when the action is valid.
+	}{
+		{name: "zone", action: models.Action{Zones: []string{"porch"}}},
+		{name: "device", action: models.Action{Devices: []models.Device{lamp}}},
+		{name: "no devices or zones", action: models.Action{}, field: "DeviceIDs"},
+		{name: "unknown zone", action: models.Action{Zones: []string{"attic"}}, field: "ZoneNames"},
+		// the command is checked against the devices of the zone too.
+		{name: "zone device without the command", action: models.Action{Zones: []string{"garden"}}, field: "Command"},
+	}
+
+	for _, tt := range tests {
+		tt.action.Command, tt.action.Parameter = devicetypes.CommandDimmer, "50"
+
+		err := main.ValidateAction(tt.action, all)
+		if (err != nil) != (tt.field != "") || main.ErrorField(err) != tt.field {
+			t.Errorf("%v: validateAction error = %v, want an error about %q", tt.name, err, tt.field)
+		}
+	}
+}
@@ -0,0 +1,455 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/lovesway/hassio-addons/mq-lightshow/database"
+	"github.com/lovesway/hassio-addons/mq-lightshow/models"
+)
+
+// backupVersion is bumped whenever the layout of models.Backup changes. Version 1 holds devices,
+// scenes and shows, version 2 added effects, palettes, zones, schedules and show priorities.
+const (
+	backupVersion   = 2
+	backupVersionV1 = 1
+)
+
+var (
+	errBackupVersion     = errors.New("unsupported backup version")
+	errBackupDeviceType  = errors.New("backup references an unknown device type")
+	errBackupDevice      = errors.New("backup references a device that is not in the backup")
+	errBackupScene       = errors.New("backup references a scene that is not in the backup")
+	errBackupEffect      = errors.New("backup references an effect that is not in the backup")
+	errBackupPalette     = errors.New("backup references a palette that is not in the backup")
+	errBackupShow        = errors.New("backup references a show that is not in the backup")
+	errBackupDeviceAdded = errors.New("device could not be added")
+)
+
+// backupDevice strips a device down to what is needed to identify it in a backup.
+func backupDevice(d models.Device) models.Device {
+	return models.Device{
+		ID:    d.ID,
+		Name:  d.Name,
+		Topic: d.Topic,
+	}
+}
+
+func backupDevices(devices []models.Device) []models.Device {
+	ds := []models.Device{}
+	for _, d := range devices {
+		ds = append(ds, backupDevice(d))
+	}
+
+	return ds
+}
+
+// Export to return a Backup of all devices, scenes, effects, palettes, shows and schedules.
+func (md *Modeler) Export() (models.Backup, error) {
+	b := models.Backup{Version: backupVersion}
+
+	for _, d := range md.GetDevices() {
+		device := backupDevice(d)
+		device.Type = models.DeviceType{ID: d.Type.ID, Name: d.Type.Name}
+		device.FullTopic = d.FullTopic
+		device.Hostname = d.Hostname
+		device.SupportsDimmer = d.SupportsDimmer
+		device.SupportsColor = d.SupportsColor
+		device.SupportsCT = d.SupportsCT
+		device.Zones = d.Zones
+		b.Devices = append(b.Devices, device)
+	}
+
+	scenes, err := md.GetScenesRecursive()
+	if err != nil {
+		return models.Backup{}, err
+	}
+
+	for i, scene := range scenes {
+		s := &scenes[i]
+		s.AllowedDevices = backupDevices(scene.AllowedDevices)
+
+		for j, group := range scene.Groups {
+			for k, action := range group.Actions {
+				s.Groups[j].Actions[k].Devices = backupDevices(action.Devices)
+			}
+		}
+	}
+
+	b.Scenes = scenes
+
+	effects, err := md.GetEffects()
+	if err != nil {
+		return models.Backup{}, err
+	}
+
+	for i, effect := range effects {
+		effects[i].Devices = backupDevices(effect.Devices)
+	}
+
+	b.Effects = effects
+
+	b.Palettes, err = md.GetPalettes()
+	if err != nil {
+		return models.Backup{}, err
+	}
+
+	shows, err := md.GetShows()
+	if err != nil {
+		return models.Backup{}, err
+	}
+
+	for i, show := range shows {
+		cycles, err := md.GetShowCycles(show.ID)
+		if err != nil {
+			return models.Backup{}, err
+		}
+
+		s := &shows[i]
+		s.Running = false
+		s.Paused = false
+		s.Cycles = cycles
+	}
+
+	b.Shows = shows
+
+	b.Schedules, err = md.GetSchedules()
+
+	return b, err
+}
+
+// Import to re-create the devices, scenes, effects, palettes, shows and schedules of a Backup.
+// Devices are matched against existing devices by topic, or by name when one of the topics is
+// empty, so that a backup taken on one install can be restored on another with different
+// device IDs. Matched devices keep their settings and are added to the zones of the backup.
+// The import runs in one transaction, so a backup that cannot be imported completely leaves
+// the database as it was. Shows whose topic is already used get a topic with a number added.
+func (md *Modeler) Import(b models.Backup) error {
+	b, err := upgradeBackup(b)
+	if err != nil {
+		return err
+	}
+
+	return md.db.Transaction(func(tx *database.Sqlite) error {
+		txmd := NewModler(tx)
+
+		return txmd.importBackup(b)
+	})
+}
+
+// upgradeBackup converts a backup of an older version to the layout of backupVersion.
+func upgradeBackup(b models.Backup) (models.Backup, error) {
+	switch b.Version {
+	case backupVersion:
+		return b, nil
+	case backupVersionV1:
+		// shows of version 1 shared their devices with every other show.
+		for i := range b.Shows {
+			b.Shows[i].Priority = 0
+			b.Shows[i].ConflictPolicy = conflictPolicyShare
+		}
+
+		b.Version = backupVersion
+
+		return b, nil
+	default:
+		return b, fmt.Errorf("%w: %v", errBackupVersion, b.Version)
+	}
+}
+
+func (md *Modeler) importBackup(b models.Backup) error {
+	deviceIDs, err := md.importDevices(b.Devices)
+	if err != nil {
+		return err
+	}
+
+	remap := func(devices []models.Device) ([]models.Device, error) {
+		ds := []models.Device{}
+
+		for _, d := range devices {
+			id, ok := deviceIDs[d.ID]
+			if !ok {
+				return ds, fmt.Errorf("%w: %v", errBackupDevice, d.ID)
+			}
+
+			ds = append(ds, models.Device{ID: id})
+		}
+
+		return ds, nil
+	}
+
+	sceneIDs := map[int]int{}
+
+	for _, scene := range b.Scenes {
+		scene.AllowedDevices, err = remap(scene.AllowedDevices)
+		if err != nil {
+			return err
+		}
+
+		var sceneID int
+
+		sceneID, err = md.AddScene(scene)
+		if err != nil {
+			return err
+		}
+
+		sceneIDs[scene.ID] = sceneID
+
+		for _, group := range scene.Groups {
+			group.SceneID = sceneID
+
+			var groupID int
+
+			groupID, err = md.AddGroup(group)
+			if err != nil {
+				return err
+			}
+
+			for _, action := range group.Actions {
+				action.GroupID = groupID
+
+				action.Devices, err = remap(action.Devices)
+				if err != nil {
+					return err
+				}
+
+				_, err = md.AddAction(action)
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	effectIDs := map[int]int{}
+
+	for _, effect := range b.Effects {
+		effect.Devices, err = remap(effect.Devices)
+		if err != nil {
+			return err
+		}
+
+		var effectID int
+
+		effectID, err = md.AddEffect(effect)
+		if err != nil {
+			return err
+		}
+
+		effectIDs[effect.ID] = effectID
+	}
+
+	paletteIDs := map[int]int{}
+
+	for _, palette := range b.Palettes {
+		var paletteID int
+
+		paletteID, err = md.AddPalette(palette)
+		if err != nil {
+			return err
+		}
+
+		paletteIDs[palette.ID] = paletteID
+	}
+
+	topics, err := md.showTopics()
+	if err != nil {
+		return err
+	}
+
+	showIDs := map[int]int{}
+
+	for _, show := range b.Shows {
+		if topic := uniqueTopic(show.Topic, topics); topic != show.Topic {
+			log.Warnf("Import: topic %v of show %v is already used, using %v", show.Topic, show.Name, topic)
+
+			show.Topic = topic
+		}
+
+		topics[show.Topic] = true
+
+		if show.PaletteID != 0 {
+			paletteID, ok := paletteIDs[show.PaletteID]
+			if !ok {
+				return fmt.Errorf("%w: %v", errBackupPalette, show.PaletteID)
+			}
+
+			show.PaletteID = paletteID
+		}
+
+		var showID int
+
+		showID, err = md.AddShow(show)
+		if err != nil {
+			return err
+		}
+
+		showIDs[show.ID] = showID
+
+		for _, cycle := range show.Cycles {
+			if cycle.EffectID != 0 {
+				effectID, ok := effectIDs[cycle.EffectID]
+				if !ok {
+					return fmt.Errorf("%w: %v", errBackupEffect, cycle.EffectID)
+				}
+
+				cycle.EffectID = effectID
+			} else {
+				sceneID, ok := sceneIDs[cycle.SceneID]
+				if !ok {
+					return fmt.Errorf("%w: %v", errBackupScene, cycle.SceneID)
+				}
+
+				cycle.SceneID = sceneID
+			}
+
+			cycle.ShowID = showID
+
+			_, err = md.AddShowCycle(cycle)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, schedule := range b.Schedules {
+		showID, ok := showIDs[schedule.ShowID]
+		if !ok {
+			return fmt.Errorf("%w: %v", errBackupShow, schedule.ShowID)
+		}
+
+		schedule.ShowID = showID
+
+		_, err = md.AddSchedule(schedule)
+		if err != nil {
+			return err
+		}
+	}
+
+	return err
+}
+
+// showTopics returns the topics of the shows in the database.
+func (md *Modeler) showTopics() (map[string]bool, error) {
+	shows, err := md.db.GetShows()
+	if err != nil {
+		return nil, err
+	}
+
+	topics := map[string]bool{}
+
+	for _, show := range shows {
+		topics[show.Topic] = true
+	}
+
+	return topics, nil
+}
+
+// importDevices maps the device IDs of a backup to device IDs on this install, adding missing devices.
+func (md *Modeler) importDevices(devices []models.Device) (map[int]int, error) {
+	deviceIDs := map[int]int{}
+	existing := md.GetDevices()
+
+	var err error
+
+	for _, d := range devices {
+		if i, ok := findDevice(existing, d); ok {
+			deviceIDs[d.ID] = existing[i].ID
+
+			err = md.importZones(&existing[i], d.Zones)
+			if err != nil {
+				return deviceIDs, fmt.Errorf("%v: %w", d.Name, err)
+			}
+
+			continue
+		}
+
+		dt, ok := findDeviceType(md.GetDeviceTypes(), d.Type)
+		if !ok {
+			return deviceIDs, fmt.Errorf("%w: %v", errBackupDeviceType, d.Type.Name)
+		}
+
+		device := d
+		device.ID = 0
+		device.Type = dt
+
+		device.ID, err = md.AddDevice(device)
+		if err != nil {
+			return deviceIDs, fmt.Errorf("%w: %v: %v", errBackupDeviceAdded, d.Name, err)
+		}
+
+		deviceIDs[d.ID] = device.ID
+		existing = append(existing, device)
+	}
+
+	return deviceIDs, nil
+}
+
+// importZones adds the zones of a backup device to the device it was matched with.
+func (md *Modeler) importZones(device *models.Device, zones []string) error {
+	merged, err := parseZones(append(append([]string{}, device.Zones...), zones...))
+	if err != nil {
+		return err
+	}
+
+	if len(merged) == len(device.Zones) {
+		return nil
+	}
+
+	device.Zones = merged
+
+	return md.SetDevice(*device)
+}
+
+// uniqueTopic returns topic, or topic with the lowest number from 2 up added to it that is not
+// in topics when topic is.
+func uniqueTopic(topic string, topics map[string]bool) string {
+	if topic == "" || !topics[topic] {
+		return topic
+	}
+
+	for n := 2; ; n++ {
+		t := topic + "-" + strconv.Itoa(n)
+		if !topics[t] {
+			return t
+		}
+	}
+}
+
+// findDevice returns the index of the device in devices that a backup device is. Devices are
+// matched by topic, and by name only when one of the topics is empty, as a device with the same
+// name but another topic is a different device.
+func findDevice(devices []models.Device, d models.Device) (int, bool) {
+	if d.Topic != "" {
+		for i, device := range devices {
+			if device.Topic == d.Topic {
+				return i, true
+			}
+		}
+	}
+
+	for i, device := range devices {
+		if device.Name == d.Name && (d.Topic == "" || device.Topic == "") {
+			return i, true
+		}
+	}
+
+	return 0, false
+}
+
+func findDeviceType(types []models.DeviceType, t models.DeviceType) (models.DeviceType, bool) {
+	for _, dt := range types {
+		if dt.Name == t.Name {
+			return dt, true
+		}
+	}
+
+	for _, dt := range types {
+		if dt.ID == t.ID {
+			return dt, true
+		}
+	}
+
+	return models.DeviceType{}, false
+}
@@ -0,0 +1,221 @@
+package main_test
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	main "github.com/lovesway/hassio-addons/mq-lightshow"
+	"github.com/lovesway/hassio-addons/mq-lightshow/database"
+	"github.com/lovesway/hassio-addons/mq-lightshow/devicetypes"
+	"github.com/lovesway/hassio-addons/mq-lightshow/models"
+)
+
+// openDatabase returns a new database in a temporary directory.
+func openDatabase(t *testing.T) *database.Sqlite {
+	t.Helper()
+
+	db := database.NewSqlite(devicetypes.NewDeviceTypes())
+	if err := db.Open(filepath.Join(t.TempDir(), "sqlite.db")); err != nil {
+		t.Fatalf("Open error = %v", err)
+	}
+
+	t.Cleanup(db.Disconnect)
+
+	return db
+}
+
+func TestUpgradeBackup(t *testing.T) {
+	t.Parallel()
+
+	v1 := models.Backup{
+		Version: 1,
+		Shows:   []models.Show{{ID: 1, Name: "party", Priority: 5, ConflictPolicy: "reject"}},
+	}
+
+	got, err := main.UpgradeBackup(v1)
+	if err != nil {
+		t.Fatalf("upgradeBackup(version 1) error = %v", err)
+	}
+
+	// shows of version 1 shared their devices.
+	want := models.Backup{Version: 2, Shows: []models.Show{{ID: 1, Name: "party", ConflictPolicy: "share"}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("upgradeBackup(version 1) = %+v, want %+v", got, want)
+	}
+
+	v2 := models.Backup{Version: 2, Shows: []models.Show{{ID: 1, Priority: 5, ConflictPolicy: "reject"}}}
+	if got, err := main.UpgradeBackup(v2); err != nil || !reflect.DeepEqual(got, v2) {
+		t.Errorf("upgradeBackup(version 2) = %+v, %v, want it unchanged", got, err)
+	}
+
+	for _, version := range []int{0, 3} {
+		if _, err := main.UpgradeBackup(models.Backup{Version: version}); err == nil {
+			t.Errorf("upgradeBackup(version %v) error = nil, want an error", version)
+		}
+	}
+}
+
+func TestUniqueTopic(t *testing.T) {
+	t.Parallel()
+
+	topics := map[string]bool{"party": true, "party-2": true, "calm": true}
+
+	tests := []struct {
+		topic string
+		want  string
+	}{
+		{topic: "new", want: "new"},
+		{topic: "", want: ""},
+		{topic: "calm", want: "calm-2"},
+		{topic: "party", want: "party-3"},
+	}
+
+	for _, tt := range tests {
+		if got := main.UniqueTopic(tt.topic, topics); got != tt.want {
+			t.Errorf("uniqueTopic(%q) = %q, want %q", tt.topic, got, tt.want)
+		}
+	}
+}
+
+func TestImport(t *testing.T) {
+	t.Parallel()
+
+	db := openDatabase(t)
+	md := main.NewModler(db)
+	tasmota := models.DeviceType{ID: devicetypes.TypeTasmota, Name: "Tasmota"}
+
+	// the devices and show that are already on this install.
+	lampID, _ := db.AddDevice(models.Device{Name: "lamp", Topic: "lamp", Type: tasmota, Zones: []string{"porch"}})
+	spotID, _ := db.AddDevice(models.Device{Name: "spot", Topic: "spot", Type: tasmota})
+	_, _ = db.AddShow(models.Show{Name: "party", Topic: "party", ConflictPolicy: "share"})
+
+	b := models.Backup{
+		Version: 2,
+		Devices: []models.Device{
+			// matched by topic, its zones are added to the lamp.
+			{ID: 10, Name: "old lamp", Topic: "lamp", Type: tasmota, Zones: []string{"garden", "porch"}},
+			// the same name as spot but another topic, so it is another device.
+			{ID: 11, Name: "spot", Topic: "garage/spot", Type: tasmota},
+			{ID: 12, Name: "strip", Topic: "strip", Type: tasmota},
+		},
+		Scenes: []models.Scene{{
+			ID:             20,
+			Name:           "scene",
+			AllowedDevices: []models.Device{{ID: 10}},
+			Groups: []models.Group{{
+				ID:      30,
+				Actions: []models.Action{{ID: 40, Devices: []models.Device{{ID: 10}, {ID: 12}}, Command: "Power"}},
+			}},
+		}},
+		Shows: []models.Show{{
+			ID:             50,
+			Name:           "party",
+			Topic:          "party",
+			ConflictPolicy: "share",
+			Cycles:         []models.Cycle{{ID: 60, SceneID: 20}},
+		}},
+		Schedules: []models.Schedule{{ID: 70, Name: "evening", ShowID: 50, StartTime: "18:00"}},
+	}
+
+	if err := md.Import(b); err != nil {
+		t.Fatalf("Import error = %v", err)
+	}
+
+	devices := map[string]models.Device{}
+	for _, d := range db.GetDevices() {
+		devices[d.Topic] = d
+	}
+
+	if len(devices) != 4 {
+		t.Fatalf("devices after Import = %v, want lamp, spot, garage/spot and strip", devices)
+	}
+
+	if lamp := devices["lamp"]; lamp.ID != lampID || lamp.Name != "lamp" ||
+		!reflect.DeepEqual(lamp.Zones, []string{"porch", "garden"}) {
+		t.Errorf("lamp after Import = %+v, want it kept with the zones porch and garden", lamp)
+	}
+
+	if spot := devices["spot"]; spot.ID != spotID || len(spot.Zones) != 0 {
+		t.Errorf("spot after Import = %+v, want it unchanged", spot)
+	}
+
+	scenes, err := db.GetScenes()
+	if err != nil || len(scenes) != 1 {
+		t.Fatalf("GetScenes = %v, %v, want the imported scene", scenes, err)
+	}
+
+	if allowed := scenes[0].AllowedDevices; len(allowed) != 1 || allowed[0].ID != lampID {
+		t.Errorf("allowed devices = %v, want the lamp", allowed)
+	}
+
+	groups, err := db.GetGroups(scenes[0].ID)
+	if err != nil || len(groups) != 1 {
+		t.Fatalf("GetGroups = %v, %v, want the imported group", groups, err)
+	}
+
+	actions, err := db.GetActions(groups[0].ID)
+	if err != nil || len(actions) != 1 {
+		t.Fatalf("GetActions = %v, %v, want the imported action", actions, err)
+	}
+
+	got := []int{}
+	for _, d := range actions[0].Devices {
+		got = append(got, d.ID)
+	}
+
+	if want := []int{lampID, devices["strip"].ID}; !reflect.DeepEqual(got, want) {
+		t.Errorf("action devices = %v, want %v", got, want)
+	}
+
+	shows, err := db.GetShows()
+	if err != nil || len(shows) != 2 {
+		t.Fatalf("GetShows = %v, %v, want the existing and the imported show", shows, err)
+	}
+
+	imported := shows[1]
+	if imported.Topic != "party-2" {
+		t.Errorf("imported show topic = %q, want party-2", imported.Topic)
+	}
+
+	cycles, err := db.GetShowCycles(imported.ID)
+	if err != nil || len(cycles) != 1 || cycles[0].SceneID != scenes[0].ID {
+		t.Errorf("imported show cycles = %+v, %v, want one with scene %v", cycles, err, scenes[0].ID)
+	}
+
+	schedules, err := db.GetSchedules()
+	if err != nil || len(schedules) != 1 || schedules[0].ShowID != imported.ID {
+		t.Errorf("schedules = %+v, %v, want one for show %v", schedules, err, imported.ID)
+	}
+}
+
+func TestImportRollsBack(t *testing.T) {
+	t.Parallel()
+
+	db := openDatabase(t)
+	md := main.NewModler(db)
+	tasmota := models.DeviceType{ID: devicetypes.TypeTasmota, Name: "Tasmota"}
+
+	b := models.Backup{
+		Version: 2,
+		Devices: []models.Device{{ID: 10, Name: "lamp", Topic: "lamp", Type: tasmota}},
+		// the action uses a device that is not in the backup.
+		Scenes: []models.Scene{{
+			ID:     20,
+			Name:   "scene",
+			Groups: []models.Group{{Actions: []models.Action{{Devices: []models.Device{{ID: 11}}}}}},
+		}},
+	}
+
+	if err := md.Import(b); err == nil {
+		t.Fatalf("Import error = nil, want an error")
+	}
+
+	if devices := db.GetDevices(); len(devices) != 0 {
+		t.Errorf("devices after a failed Import = %v, want none", devices)
+	}
+
+	if scenes, err := db.GetScenes(); err != nil || len(scenes) != 0 {
+		t.Errorf("scenes after a failed Import = %v, %v, want none", scenes, err)
+	}
+}
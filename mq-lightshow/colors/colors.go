@@ -0,0 +1,304 @@
+// Package colors converts between the color models lights are controlled with.
+package colors
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var errInvalidColor = errors.New("invalid color")
+
+const (
+	maxChannel = 255
+	maxHue     = 360
+	maxPercent = 100
+
+	// MinMireds is the coldest color temperature Tasmota accepts (6500K).
+	MinMireds = 153
+	// MaxMireds is the warmest color temperature Tasmota accepts (2000K).
+	MaxMireds = 500
+
+	million = 1000000
+)
+
+// RGB is a color with red, green and blue from 0 to 255.
+type RGB struct {
+	R int
+	G int
+	B int
+}
+
+// HSB is a color with hue from 0 to 360 and saturation and brightness from 0 to 100.
+type HSB struct {
+	H float64
+	S float64
+	B float64
+}
+
+// ParseRGB parses r,g,b or #RRGGBB (the # is optional).
+func ParseRGB(s string) (RGB, error) {
+	p := strings.TrimPrefix(strings.TrimSpace(s), "#")
+
+	const hexLength = 6
+	if !strings.Contains(p, ",") && len(p) == hexLength {
+		v, err := strconv.ParseUint(p, 16, 32)
+		if err != nil {
+			return RGB{}, fmt.Errorf("%w: %q", errInvalidColor, s)
+		}
+
+		const byteBits, byteMask = 8, 0xff
+
+		return RGB{
+			R: int(v >> (2 * byteBits) & byteMask),
+			G: int(v >> byteBits & byteMask),
+			B: int(v & byteMask),
+		}, nil
+	}
+
+	v, err := parseInts(p, maxChannel, maxChannel, maxChannel)
+	if err != nil {
+		return RGB{}, fmt.Errorf("%w: %q", errInvalidColor, s)
+	}
+
+	return RGB{R: v[0], G: v[1], B: v[2]}, nil
+}
+
+// ParseHSB parses hue,saturation,brightness as used by the Tasmota HsbColor command.
+func ParseHSB(s string) (HSB, error) {
+	v, err := parseInts(s, maxHue, maxPercent, maxPercent)
+	if err != nil {
+		return HSB{}, fmt.Errorf("%w: %q", errInvalidColor, s)
+	}
+
+	return HSB{H: float64(v[0]), S: float64(v[1]), B: float64(v[2])}, nil
+}
+
+// parseInts parses three comma separated numbers from 0 to their maximum.
+func parseInts(s string, max ...int) ([]int, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != len(max) {
+		return nil, errInvalidColor
+	}
+
+	v := make([]int, len(parts))
+
+	for i, part := range parts {
+		n, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || n < 0 || n > max[i] {
+			return nil, errInvalidColor
+		}
+
+		v[i] = n
+	}
+
+	return v, nil
+}
+
+// String returns r,g,b as used by the Tasmota Color1 command.
+func (c RGB) String() string {
+	return fmt.Sprintf("%d,%d,%d", c.R, c.G, c.B)
+}
+
+// Hex returns #RRGGBB.
+func (c RGB) Hex() string {
+	return fmt.Sprintf("#%02X%02X%02X", c.R, c.G, c.B)
+}
+
+// HSB converts a color to hue, saturation and brightness.
+func (c RGB) HSB() HSB {
+	r := float64(c.R) / maxChannel
+	g := float64(c.G) / maxChannel
+	b := float64(c.B) / maxChannel
+
+	max := math.Max(r, math.Max(g, b))
+	d := max - math.Min(r, math.Min(g, b))
+
+	// the hue is measured in sectors of 60 degrees from red, green or blue.
+	const (
+		sector, sectors         = 60.0, 6.0
+		greenSector, blueSector = 2.0, 4.0
+	)
+
+	var h float64
+
+	switch {
+	case d == 0:
+		h = 0
+	case max == r:
+		h = sector * math.Mod((g-b)/d, sectors)
+	case max == g:
+		h = sector * ((b-r)/d + greenSector)
+	default:
+		h = sector * ((r-g)/d + blueSector)
+	}
+
+	if h < 0 {
+		h += maxHue
+	}
+
+	var s float64
+	if max > 0 {
+		s = d / max
+	}
+
+	return HSB{H: h, S: s * maxPercent, B: max * maxPercent}
+}
+
+// String returns hue,saturation,brightness as used by the Tasmota HsbColor command.
+func (h HSB) String() string {
+	return fmt.Sprintf("%d,%d,%d", int(math.Round(h.H))%maxHue, int(math.Round(h.S)), int(math.Round(h.B)))
+}
+
+// RGB converts hue, saturation and brightness to a color.
+func (h HSB) RGB() RGB {
+	const sector, sectors = 60.0, 6
+
+	hue := math.Mod(h.H, maxHue)
+	if hue < 0 {
+		hue += maxHue
+	}
+
+	s := h.S / maxPercent
+	v := h.B / maxPercent * maxChannel
+	f := math.Mod(hue, sector) / sector
+
+	p := v * (1 - s)
+	q := v * (1 - s*f)
+	t := v * (1 - s*(1-f))
+
+	// the channels for each 60 degree sector of the color wheel.
+	rgbs := [sectors][3]float64{{v, t, p}, {q, v, p}, {p, v, t}, {p, q, v}, {t, p, v}, {v, p, q}}
+	c := rgbs[int(hue/sector)%sectors]
+
+	return RGB{R: int(math.Round(c[0])), G: int(math.Round(c[1])), B: int(math.Round(c[2]))}
+}
+
+// MiredsToKelvin converts a color temperature in mireds, as used by Tasmota, to kelvin.
+func MiredsToKelvin(mireds int) int {
+	if mireds <= 0 {
+		return 0
+	}
+
+	return million / mireds
+}
+
+// KelvinToMireds converts a color temperature in kelvin to mireds.
+func KelvinToMireds(kelvin int) int {
+	if kelvin <= 0 {
+		return 0
+	}
+
+	return million / kelvin
+}
+
+// ClampMireds limits a color temperature to the range Tasmota accepts.
+func ClampMireds(mireds int) int {
+	if mireds < MinMireds {
+		return MinMireds
+	}
+
+	if mireds > MaxMireds {
+		return MaxMireds
+	}
+
+	return mireds
+}
+
+// MiredsToRGB approximates the color of white light at a color temperature, for lights that
+// only have RGB channels. It uses the approximation of black body colors by Tanner Helland.
+func MiredsToRGB(mireds int) RGB {
+	const hundred = 100.0
+
+	t := float64(MiredsToKelvin(ClampMireds(mireds))) / hundred
+
+	var r, g, b float64
+
+	const (
+		redCold, redExp     = 329.698727446, -0.1332047592
+		greenWarm, greenOff = 99.4708025861, 161.1195681661
+		greenCold, greenExp = 288.1221695283, -0.0755148492
+		blueWarm, blueOff   = 138.5177312231, 305.0447927307
+		neutral, blueMin    = 66.0, 19.0
+		offsetCold, offsetB = 60.0, 10.0
+	)
+
+	if t <= neutral {
+		r = maxChannel
+		g = greenWarm*math.Log(t) - greenOff
+	} else {
+		r = redCold * math.Pow(t-offsetCold, redExp)
+		g = greenCold * math.Pow(t-offsetCold, greenExp)
+	}
+
+	switch {
+	case t >= neutral:
+		b = maxChannel
+	case t <= blueMin:
+		b = 0
+	default:
+		b = blueWarm*math.Log(t-offsetB) - blueOff
+	}
+
+	return RGB{R: clampChannel(r), G: clampChannel(g), B: clampChannel(b)}
+}
+
+// Mireds approximates the color temperature of a color, for lights that only have white
+// channels. It uses the formula by McCamy on the chromaticity of the color and is only
+// meaningful for whitish colors, the result is limited to the range Tasmota accepts.
+func (c RGB) Mireds() int {
+	linear := func(v int) float64 {
+		const (
+			threshold, divisor = 0.04045, 12.92
+			offset, scale, exp = 0.055, 1.055, 2.4
+		)
+
+		f := float64(v) / maxChannel
+		if f <= threshold {
+			return f / divisor
+		}
+
+		return math.Pow((f+offset)/scale, exp)
+	}
+
+	r, g, b := linear(c.R), linear(c.G), linear(c.B)
+
+	// sRGB (D65) to CIE XYZ.
+	x := 0.4124*r + 0.3576*g + 0.1805*b
+	y := 0.2126*r + 0.7152*g + 0.0722*b
+	z := 0.0193*r + 0.1192*g + 0.9505*b
+
+	sum := x + y + z
+	if sum == 0 {
+		return MaxMireds
+	}
+
+	const (
+		epiX, epiY         = 0.3320, 0.1858
+		cubic, square, lin = 449.0, 3525.0, 6823.3
+		constant           = 5520.33
+	)
+
+	n := (x/sum - epiX) / (y/sum - epiY)
+	kelvin := -cubic*n*n*n + square*n*n - lin*n + constant
+
+	if kelvin <= 0 {
+		return MaxMireds
+	}
+
+	return ClampMireds(int(math.Round(million / kelvin)))
+}
+
+func clampChannel(v float64) int {
+	if v < 0 {
+		return 0
+	}
+
+	if v > maxChannel {
+		return maxChannel
+	}
+
+	return int(math.Round(v))
+}
@@ -0,0 +1,95 @@
+package colors_test
+
+import (
+	"testing"
+
+	"github.com/lovesway/hassio-addons/mq-lightshow/colors"
+)
+
+func TestParseRGB(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in   string
+		want colors.RGB
+		err  bool
+	}{
+		{in: "255,128,0", want: colors.RGB{R: 255, G: 128, B: 0}},
+		{in: "#FF8000", want: colors.RGB{R: 255, G: 128, B: 0}},
+		{in: "ff8000", want: colors.RGB{R: 255, G: 128, B: 0}},
+		{in: " 1, 2, 3 ", want: colors.RGB{R: 1, G: 2, B: 3}},
+		{in: "256,0,0", err: true},
+		{in: "#zzzzzz", err: true},
+		{in: "1,2", err: true},
+		{in: "", err: true},
+	}
+
+	for _, tt := range tests {
+		got, err := colors.ParseRGB(tt.in)
+		if (err != nil) != tt.err {
+			t.Errorf("ParseRGB(%q) error = %v, want error %v", tt.in, err, tt.err)
+
+			continue
+		}
+
+		if got != tt.want {
+			t.Errorf("ParseRGB(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestRGBToHSB(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in   colors.RGB
+		want string
+	}{
+		{in: colors.RGB{R: 255, G: 0, B: 0}, want: "0,100,100"},
+		{in: colors.RGB{R: 0, G: 255, B: 0}, want: "120,100,100"},
+		{in: colors.RGB{R: 0, G: 0, B: 255}, want: "240,100,100"},
+		{in: colors.RGB{R: 255, G: 128, B: 0}, want: "30,100,100"},
+		{in: colors.RGB{R: 255, G: 255, B: 255}, want: "0,0,100"},
+		{in: colors.RGB{R: 0, G: 0, B: 0}, want: "0,0,0"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.in.HSB().String(); got != tt.want {
+			t.Errorf("%v.HSB() = %v, want %v", tt.in, got, tt.want)
+		}
+
+		// converting back should give the same color.
+		if got := tt.in.HSB().RGB(); got != tt.in {
+			t.Errorf("%v.HSB().RGB() = %v", tt.in, got)
+		}
+	}
+}
+
+func TestMireds(t *testing.T) {
+	t.Parallel()
+
+	if got := colors.MiredsToKelvin(250); got != 4000 {
+		t.Errorf("MiredsToKelvin(250) = %v, want 4000", got)
+	}
+
+	if got := colors.KelvinToMireds(2700); got != 370 {
+		t.Errorf("KelvinToMireds(2700) = %v, want 370", got)
+	}
+
+	const tolerance = 40
+
+	warm := colors.MiredsToRGB(colors.MaxMireds)
+	cold := colors.MiredsToRGB(colors.MinMireds)
+
+	if warm.R != 255 || warm.B >= warm.G || cold.B < cold.R-tolerance {
+		t.Errorf("MiredsToRGB warm = %v, cold = %v", warm, cold)
+	}
+
+	// the temperature of the approximated white should be close to where it came from.
+	for _, mireds := range []int{170, 250, 370, 450} {
+		got := colors.MiredsToRGB(mireds).Mireds()
+		if got < mireds-tolerance || got > mireds+tolerance {
+			t.Errorf("MiredsToRGB(%v).Mireds() = %v", mireds, got)
+		}
+	}
+}
@@ -18,26 +18,31 @@ type Controller struct {
 	md Modeler
 	db *database.Sqlite
 	mq *MQController
-	dt []models.DeviceType
+	dt *devicetypes.DeviceTypes
+	st scheduleTimes
 }
 
 // NewController provides an instance of Controller.
-func NewController(md Modeler, db *database.Sqlite, mq *MQController, dts devicetypes.DeviceTypes) Controller {
+func NewController(
+	md Modeler, db *database.Sqlite, mq *MQController, dts *devicetypes.DeviceTypes, st scheduleTimes,
+) Controller {
 	return Controller{
 		md: md,
 		db: db,
 		mq: mq,
-		dt: dts.GetDeviceTypes(),
+		dt: dts,
+		st: st,
 	}
 }
 
 // PageInfo represents common page items, mostly used in the header and footer.
 type PageInfo struct {
-	Title              string
-	Posted             bool
-	ScenesLinkEnabled  bool
-	DevicesLinkEnabled bool
-	MQTTLinkEnabled    bool
+	Title                string
+	Posted               bool
+	ScenesLinkEnabled    bool
+	DevicesLinkEnabled   bool
+	SchedulesLinkEnabled bool
+	MQTTLinkEnabled      bool
 }
 
 // httpRedirect is a handler for hassio ingress.
@@ -78,9 +83,19 @@ func (c Controller) ShowsAddHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	type data struct{ PageInfo PageInfo }
+	type data struct {
+		PageInfo PageInfo
+		Palettes []models.Palette
+	}
 
-	tplErr := tpl.ExecuteTemplate(w, "content", data{PageInfo: PageInfo{Title: "Adding Show"}})
+	palettes, err := c.md.GetPalettes()
+	if err != nil {
+		httpErrorHandler(w, err.Error())
+
+		return
+	}
+
+	tplErr := tpl.ExecuteTemplate(w, "content", data{PageInfo: PageInfo{Title: "Adding Show"}, Palettes: palettes})
 	if tplErr != nil {
 		log.Error(tplErr)
 	}
@@ -107,6 +122,7 @@ func (c Controller) ShowsConfigureHandler(w http.ResponseWriter, r *http.Request
 	type data struct {
 		PageInfo    PageInfo
 		Show        models.Show
+		Palettes    []models.Palette
 		GlobalDelay string // string conversion to maintain empty value comparison ability.
 		GlobalSpeed string // string conversion to maintain empty value comparison ability.
 	}
@@ -118,9 +134,17 @@ func (c Controller) ShowsConfigureHandler(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	palettes, err := c.md.GetPalettes()
+	if err != nil {
+		httpErrorHandler(w, err.Error())
+
+		return
+	}
+
 	dat := data{
 		PageInfo: pi,
 		Show:     show,
+		Palettes: palettes,
 	}
 
 	var sz models.Show // for null struct comparison.
@@ -219,6 +243,7 @@ func (c Controller) ShowsCyclesAddHandler(w http.ResponseWriter, r *http.Request
 		PageInfo PageInfo
 		ShowID   int
 		Scenes   []models.Scene
+		Effects  []models.Effect
 	}
 
 	scenes, err := c.md.GetScenes()
@@ -228,10 +253,18 @@ func (c Controller) ShowsCyclesAddHandler(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	effects, err := c.md.GetEffects()
+	if err != nil {
+		httpErrorHandler(w, err.Error())
+
+		return
+	}
+
 	dat := data{
 		PageInfo: pi,
 		ShowID:   showID,
 		Scenes:   scenes,
+		Effects:  effects,
 	}
 
 	tplErr := tpl.ExecuteTemplate(w, "content", dat)
@@ -261,6 +294,7 @@ func (c Controller) ShowsCyclesEditHandler(w http.ResponseWriter, r *http.Reques
 	type data struct {
 		PageInfo    PageInfo
 		Scenes      []models.Scene
+		Effects     []models.Effect
 		Cycle       models.Cycle
 		GlobalDelay string // string conversion to maintain empty value comparison ability.
 		GlobalSpeed string // string conversion to maintain empty value comparison ability.
@@ -280,9 +314,17 @@ func (c Controller) ShowsCyclesEditHandler(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	effects, err := c.md.GetEffects()
+	if err != nil {
+		httpErrorHandler(w, err.Error())
+
+		return
+	}
+
 	dat := data{
 		PageInfo: pi,
 		Scenes:   scenes,
+		Effects:  effects,
 		Cycle:    cycle,
 	}
 
@@ -369,9 +411,10 @@ func (c Controller) ScenesAddHandler(w http.ResponseWriter, r *http.Request) {
 	type data struct {
 		PageInfo PageInfo
 		Devices  []models.Device
+		Zones    []models.Zone
 	}
 
-	tplErr := tpl.ExecuteTemplate(w, "content", data{PageInfo: pi, Devices: c.db.GetDevices()})
+	tplErr := tpl.ExecuteTemplate(w, "content", data{PageInfo: pi, Devices: c.db.GetDevices(), Zones: c.md.GetZones()})
 	if tplErr != nil {
 		log.Error(tplErr)
 	}
@@ -419,6 +462,7 @@ func (c Controller) ScenesConfigureHandler(w http.ResponseWriter, r *http.Reques
 		PageInfo PageInfo
 		Scene    models.Scene
 		Devices  []models.Device
+		Zones    []models.Zone
 	}
 
 	scene, err := c.md.GetScene(sceneID)
@@ -439,6 +483,7 @@ func (c Controller) ScenesConfigureHandler(w http.ResponseWriter, r *http.Reques
 		PageInfo: pi,
 		Scene:    scene,
 		Devices:  devices,
+		Zones:    c.md.GetZones(),
 	}
 
 	tplErr := tpl.ExecuteTemplate(w, "content", dat)
@@ -718,6 +763,7 @@ func (c Controller) ScenesActionsAddHandler(w http.ResponseWriter, r *http.Reque
 		Commands    []models.Command
 		Devices     []models.Device
 		DeviceTypes []models.DeviceType
+		Zones       []models.Zone
 		OrderNext   int
 	}
 
@@ -739,12 +785,13 @@ func (c Controller) ScenesActionsAddHandler(w http.ResponseWriter, r *http.Reque
 		PageInfo:    pi,
 		SceneID:     sceneID,
 		GroupID:     groupID,
-		DeviceTypes: c.dt,
+		DeviceTypes: c.dt.GetDeviceTypes(),
+		Zones:       c.md.GetZones(),
 		OrderNext:   orderNext,
 	}
 
-	if len(scene.AllowedDevices) != 0 {
-		dat.Devices = scene.AllowedDevices
+	if len(scene.AllowedDevices) != 0 || len(scene.AllowedZones) != 0 {
+		dat.Devices = zoneDevices(scene.AllowedDevices, scene.AllowedZones, c.db.GetDevices())
 	} else {
 		dat.Devices = c.db.GetDevices()
 	}
@@ -795,6 +842,7 @@ func (c Controller) ScenesActionsEditHandler(w http.ResponseWriter, r *http.Requ
 		Commands    []models.Command
 		Devices     []models.Device
 		DeviceTypes []models.DeviceType
+		Zones       []models.Zone
 	}
 
 	action, err := c.md.GetAction(actionID)
@@ -809,7 +857,8 @@ func (c Controller) ScenesActionsEditHandler(w http.ResponseWriter, r *http.Requ
 		SceneID:     sceneID,
 		GroupID:     groupID,
 		Action:      action,
-		DeviceTypes: c.dt,
+		DeviceTypes: c.dt.GetDeviceTypes(),
+		Zones:       c.md.GetZones(),
 	}
 
 	scene, err := c.md.GetScene(sceneID)
@@ -819,8 +868,9 @@ func (c Controller) ScenesActionsEditHandler(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	if len(scene.AllowedDevices) != 0 {
-		dat.Devices = c.db.ReturnDevicesWithActionSelected(dat.Action, scene.AllowedDevices)
+	if len(scene.AllowedDevices) != 0 || len(scene.AllowedZones) != 0 {
+		allowed := zoneDevices(scene.AllowedDevices, scene.AllowedZones, c.db.GetDevices())
+		dat.Devices = c.db.ReturnDevicesWithActionSelected(dat.Action, allowed)
 	} else {
 		dat.Devices = c.db.GetDevicesWithActionSelected(actionID)
 	}
@@ -863,11 +913,14 @@ func (c Controller) DevicesHandler(w http.ResponseWriter, r *http.Request) {
 	pi := PageInfo{Title: "Devices", DevicesLinkEnabled: true}
 
 	type data struct {
-		PageInfo PageInfo
-		Devices  []models.Device
+		PageInfo   PageInfo
+		Devices    []models.Device
+		Discovered []models.DiscoveredDevice
 	}
 
-	tplErr := tpl.ExecuteTemplate(w, "base", data{PageInfo: pi, Devices: c.db.GetDevices()})
+	tplErr := tpl.ExecuteTemplate(
+		w, "base", data{PageInfo: pi, Devices: c.mq.WithHealth(c.db.GetDevices()), Discovered: c.mq.GetDiscoveredDevices()},
+	)
 	if tplErr != nil {
 		log.Error(tplErr)
 	}
@@ -883,13 +936,33 @@ func (c Controller) DevicesAddHandler(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		zones, zerr := parseZones(splitZones(r.PostFormValue("zones")))
+		if zerr != nil {
+			httpErrorHandler(w, zerr.Error())
+
+			return
+		}
+
 		d := models.Device{
-			Name:  r.PostFormValue("name"),
-			Topic: r.PostFormValue("topic"),
-			Type:  models.DeviceType{ID: tid},
+			Name:           r.PostFormValue("name"),
+			Topic:          r.PostFormValue("topic"),
+			FullTopic:      r.PostFormValue("fulltopic"),
+			Hostname:       r.PostFormValue("hostname"),
+			Type:           models.DeviceType{ID: tid},
+			SupportsDimmer: r.PostFormValue("supportsdimmer") == "true",
+			SupportsColor:  r.PostFormValue("supportscolor") == "true",
+			SupportsCT:     r.PostFormValue("supportsct") == "true",
+			Zones:          zones,
 		}
 
-		c.db.AddDevice(d)
+		_, err := c.db.AddDevice(d)
+		if err != nil {
+			httpErrorHandler(w, err.Error())
+
+			return
+		}
+
+		c.mq.SubscribeDevices()
 		httpRedirect(w, r, "devices")
 
 		return
@@ -942,8 +1015,14 @@ func (c Controller) DevicesDeleteHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	c.db.DeleteDevice(deviceID)
+	err := c.db.DeleteDevice(deviceID)
+	if err != nil {
+		httpErrorHandler(w, err.Error())
 
+		return
+	}
+
+	c.mq.SubscribeDevices()
 	httpRedirect(w, r, "devices")
 }
 
@@ -962,14 +1041,34 @@ func (c Controller) DevicesEditHandler(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		zones, zerr := parseZones(splitZones(r.PostFormValue("zones")))
+		if zerr != nil {
+			httpErrorHandler(w, zerr.Error())
+
+			return
+		}
+
 		d := models.Device{
-			ID:    deviceID,
-			Name:  r.PostFormValue("name"),
-			Topic: r.PostFormValue("topic"),
-			Type:  c.db.GetDeviceType(tid),
+			ID:             deviceID,
+			Name:           r.PostFormValue("name"),
+			Topic:          r.PostFormValue("topic"),
+			FullTopic:      r.PostFormValue("fulltopic"),
+			Hostname:       r.PostFormValue("hostname"),
+			Type:           c.db.GetDeviceType(tid),
+			SupportsDimmer: r.PostFormValue("supportsdimmer") == "true",
+			SupportsColor:  r.PostFormValue("supportscolor") == "true",
+			SupportsCT:     r.PostFormValue("supportsct") == "true",
+			Zones:          zones,
+		}
+
+		err := c.db.SetDevice(d)
+		if err != nil {
+			httpErrorHandler(w, err.Error())
+
+			return
 		}
 
-		c.db.SetDevice(d)
+		c.mq.SubscribeDevices()
 		httpRedirect(w, r, "devices")
 
 		return
@@ -982,6 +1081,13 @@ func (c Controller) DevicesEditHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	device, err := c.db.GetDevice(deviceID)
+	if err != nil {
+		httpErrorHandler(w, err.Error())
+
+		return
+	}
+
 	pi := PageInfo{Title: "Editing Device", DevicesLinkEnabled: true}
 
 	type data struct {
@@ -991,13 +1097,168 @@ func (c Controller) DevicesEditHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	tplErr := tpl.ExecuteTemplate(
-		w, "base", data{PageInfo: pi, Device: c.db.GetDevice(deviceID), DeviceTypes: c.db.GetDeviceTypes()},
+		w, "base", data{PageInfo: pi, Device: device, DeviceTypes: c.db.GetDeviceTypes()},
 	)
 	if tplErr != nil {
 		log.Error(tplErr)
 	}
 }
 
+// SchedulesHandler controller.
+func (c Controller) SchedulesHandler(w http.ResponseWriter, r *http.Request) {
+	tpl, err := template.ParseFiles("www/schedules.tpl", "www/base.tpl")
+	if err != nil {
+		httpErrorHandler(w, err.Error())
+
+		return
+	}
+
+	type data struct {
+		PageInfo    PageInfo
+		TimeZone    string
+		SolarEvents []models.SolarEvent
+	}
+
+	dat := data{
+		PageInfo:    PageInfo{Title: "Schedules", SchedulesLinkEnabled: true},
+		TimeZone:    c.st.loc.String(),
+		SolarEvents: c.st.solarDay(c.st.now()),
+	}
+
+	tplErr := tpl.ExecuteTemplate(w, "base", dat)
+	if tplErr != nil {
+		log.Error(tplErr)
+	}
+}
+
+// weekdayNames are the days of the week offered by the schedule forms, Sunday first like time.Weekday.
+var weekdayNames = []string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"}
+
+// scheduleDay is a day of the week checkbox on the schedule forms.
+type scheduleDay struct {
+	Value   int
+	Name    string
+	Checked bool
+}
+
+func getScheduleDays(days []int) []scheduleDay {
+	sds := []scheduleDay{}
+
+	for i, name := range weekdayNames {
+		sd := scheduleDay{Value: i, Name: name}
+
+		for _, d := range days {
+			if d == i {
+				sd.Checked = true
+			}
+		}
+
+		sds = append(sds, sd)
+	}
+
+	return sds
+}
+
+// SchedulesAddHandler controller.
+func (c Controller) SchedulesAddHandler(w http.ResponseWriter, r *http.Request) {
+	tpl, err := template.ParseFiles("www/schedules-add.tpl")
+	if err != nil {
+		httpErrorHandler(w, err.Error())
+
+		return
+	}
+
+	type data struct {
+		PageInfo PageInfo
+		Shows    []models.Show
+		Days     []scheduleDay
+	}
+
+	shows, err := c.md.GetShows()
+	if err != nil {
+		httpErrorHandler(w, err.Error())
+
+		return
+	}
+
+	dat := data{
+		PageInfo: PageInfo{Title: "Adding Schedule", SchedulesLinkEnabled: true},
+		Shows:    shows,
+		Days:     getScheduleDays(nil),
+	}
+
+	tplErr := tpl.ExecuteTemplate(w, "content", dat)
+	if tplErr != nil {
+		log.Error(tplErr)
+	}
+}
+
+func getRequestScheduleID(r *http.Request) int {
+	keys, ok := r.URL.Query()["scheduleID"]
+	if !ok || len(keys[0]) < 1 {
+		return 0
+	}
+
+	scheduleID, err := strconv.Atoi(keys[0])
+	if err != nil {
+		log.Error(err)
+
+		return 0
+	}
+
+	return scheduleID
+}
+
+// SchedulesEditHandler controller.
+func (c Controller) SchedulesEditHandler(w http.ResponseWriter, r *http.Request) {
+	scheduleID := getRequestScheduleID(r)
+	if scheduleID == 0 {
+		httpErrorHandler(w, "Url Param 'scheduleID' is missing")
+
+		return
+	}
+
+	tpl, err := template.ParseFiles("www/schedules-edit.tpl")
+	if err != nil {
+		httpErrorHandler(w, err.Error())
+
+		return
+	}
+
+	type data struct {
+		PageInfo PageInfo
+		Schedule models.Schedule
+		Shows    []models.Show
+		Days     []scheduleDay
+	}
+
+	schedule, err := c.md.GetSchedule(scheduleID)
+	if err != nil {
+		httpErrorHandler(w, err.Error())
+
+		return
+	}
+
+	shows, err := c.md.GetShows()
+	if err != nil {
+		httpErrorHandler(w, err.Error())
+
+		return
+	}
+
+	dat := data{
+		PageInfo: PageInfo{Title: "Editing Schedule", SchedulesLinkEnabled: true},
+		Schedule: schedule,
+		Shows:    shows,
+		Days:     getScheduleDays(schedule.Days),
+	}
+
+	tplErr := tpl.ExecuteTemplate(w, "content", dat)
+	if tplErr != nil {
+		log.Error(tplErr)
+	}
+}
+
 // MqttHandler function.
 func (c Controller) MqttHandler(w http.ResponseWriter, r *http.Request) {
 	if r.Method == http.MethodGet {
@@ -1031,9 +1292,10 @@ func (c Controller) MqttHandler(w http.ResponseWriter, r *http.Request) {
 	pi.MQTTLinkEnabled = true
 
 	type data struct {
-		PageInfo      PageInfo
-		MQTTConnected bool
-		MQTTHost      string
+		PageInfo         PageInfo
+		MQTTConnected    bool
+		MQTTHost         string
+		ConnectionEvents []models.ConnectionEvent
 	}
 
 	conf := getHaConfiguration()
@@ -1041,6 +1303,7 @@ func (c Controller) MqttHandler(w http.ResponseWriter, r *http.Request) {
 	d := data{PageInfo: pi}
 	d.MQTTConnected = c.mq.IsConnected()
 	d.MQTTHost = conf.MQTTHost
+	d.ConnectionEvents = c.mq.GetConnectionEvents()
 
 	tplErr := tpl.ExecuteTemplate(w, "base", d)
 	if tplErr != nil {
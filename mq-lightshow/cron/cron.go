@@ -0,0 +1,189 @@
+// Package cron parses the five field cron expressions used to schedule shows.
+package cron
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	errFields = errors.New("a cron expression needs five fields: minute hour day-of-month month day-of-week")
+	errValue  = errors.New("invalid cron value")
+	errRange  = errors.New("cron value out of range")
+	errStep   = errors.New("invalid cron step")
+)
+
+const fieldCount = 5
+
+// field describes the values allowed in one position of an expression.
+type field struct {
+	name  string
+	min   int
+	max   int
+	names []string // names for the values starting at min, e.g. jan for 1.
+}
+
+var fields = [fieldCount]field{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day-of-month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: []string{
+		"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec",
+	}},
+	// 7 is accepted as another name for Sunday.
+	{name: "day-of-week", min: 0, max: 7, names: []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}},
+}
+
+// macros are the shorthand expressions that can be used in place of the five fields.
+var macros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// Expression is a parsed cron expression.
+type Expression struct {
+	source string
+	sets   [fieldCount]uint64 // bit n is set when value n matches.
+	// when both day fields are restricted a time matches either of them, like the classic cron.
+	anyDay bool
+}
+
+// Parse reads an expression of the form "minute hour day-of-month month day-of-week".
+// Each field takes *, a value, a range (1-5), a list (1,3,5) and steps (*/15 or 8-18/2).
+// Months and days of the week can also be given by their three letter English names.
+func Parse(expr string) (Expression, error) {
+	e := Expression{source: strings.TrimSpace(expr)}
+
+	s := strings.ToLower(e.source)
+	if m, ok := macros[s]; ok {
+		s = m
+	}
+
+	parts := strings.Fields(s)
+	if len(parts) != fieldCount {
+		return Expression{}, fmt.Errorf("%w: %q", errFields, expr)
+	}
+
+	for i, part := range parts {
+		set, err := parseField(part, fields[i])
+		if err != nil {
+			return Expression{}, err
+		}
+
+		e.sets[i] = set
+	}
+
+	const dom, dow, sunday = 2, 4, 7
+
+	// fold the second Sunday onto the first.
+	if e.sets[dow]&(1<<sunday) != 0 {
+		e.sets[dow] |= 1
+	}
+
+	e.anyDay = !strings.HasPrefix(parts[dom], "*") && !strings.HasPrefix(parts[dow], "*")
+
+	return e, nil
+}
+
+func parseField(s string, f field) (uint64, error) {
+	var set uint64
+
+	for _, part := range strings.Split(s, ",") {
+		rng, step := part, 1
+
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n < 1 {
+				return 0, fmt.Errorf("%w in %v field: %q", errStep, f.name, part)
+			}
+
+			rng, step = part[:i], n
+		}
+
+		lo, hi := f.min, f.max
+
+		if rng != "*" {
+			bounds := strings.SplitN(rng, "-", 2)
+
+			var err error
+
+			lo, err = parseValue(bounds[0], f)
+			if err != nil {
+				return 0, err
+			}
+
+			hi = lo
+
+			switch {
+			case len(bounds) == 2:
+				hi, err = parseValue(bounds[1], f)
+				if err != nil {
+					return 0, err
+				}
+			case step > 1:
+				// 5/15 runs from 5 to the end of the field.
+				hi = f.max
+			}
+
+			if hi < lo {
+				return 0, fmt.Errorf("%w in %v field: %q", errRange, f.name, part)
+			}
+		}
+
+		for v := lo; v <= hi; v += step {
+			set |= 1 << uint(v)
+		}
+	}
+
+	return set, nil
+}
+
+func parseValue(s string, f field) (int, error) {
+	for i, name := range f.names {
+		if s == name {
+			return f.min + i, nil
+		}
+	}
+
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("%w in %v field: %q", errValue, f.name, s)
+	}
+
+	if v < f.min || v > f.max {
+		return 0, fmt.Errorf("%w in %v field: %v is not between %v and %v", errRange, f.name, v, f.min, f.max)
+	}
+
+	return v, nil
+}
+
+// Match reports whether the minute of t is one of the minutes of the expression.
+func (e Expression) Match(t time.Time) bool {
+	has := func(i, v int) bool {
+		return e.sets[i]&(1<<uint(v)) != 0
+	}
+
+	if !has(0, t.Minute()) || !has(1, t.Hour()) || !has(3, int(t.Month())) {
+		return false
+	}
+
+	dom, dow := has(2, t.Day()), has(4, int(t.Weekday()))
+	if e.anyDay {
+		return dom || dow
+	}
+
+	return dom && dow
+}
+
+// String returns the expression as it was given.
+func (e Expression) String() string {
+	return e.source
+}
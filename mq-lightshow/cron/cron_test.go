@@ -0,0 +1,81 @@
+package cron_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lovesway/hassio-addons/mq-lightshow/cron"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in  string
+		err bool
+	}{
+		{in: "* * * * *"},
+		{in: "30 18 * 11,12 mon-fri"},
+		{in: "*/15 8-18/2 1 jan sun"},
+		{in: "0 0 * * 7"},
+		{in: "@daily"},
+		{in: "* * * *", err: true},
+		{in: "60 * * * *", err: true},
+		{in: "* * 0 * *", err: true},
+		{in: "* * * 13 *", err: true},
+		{in: "5-1 * * * *", err: true},
+		{in: "*/0 * * * *", err: true},
+		{in: "* * * * funday", err: true},
+		{in: "", err: true},
+	}
+
+	for _, tt := range tests {
+		_, err := cron.Parse(tt.in)
+		if (err != nil) != tt.err {
+			t.Errorf("Parse(%q) error = %v, want error %v", tt.in, err, tt.err)
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	t.Parallel()
+
+	// Friday the 13th of December.
+	friday := time.Date(2024, time.December, 13, 18, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		expr string
+		t    time.Time
+		want bool
+	}{
+		{expr: "* * * * *", t: friday, want: true},
+		{expr: "30 18 * * *", t: friday, want: true},
+		{expr: "31 18 * * *", t: friday, want: false},
+		{expr: "*/15 * * * *", t: friday, want: true},
+		{expr: "10/20 * * * *", t: friday, want: true},
+		{expr: "*/20 * * * *", t: friday, want: false},
+		{expr: "30 18 * nov-dec fri", t: friday, want: true},
+		{expr: "30 18 * jan-nov *", t: friday, want: false},
+		{expr: "30 18 * * mon-thu", t: friday, want: false},
+		{expr: "30 18 24,25 12 *", t: friday, want: false},
+		// a restricted day of the month and day of the week match when either does.
+		{expr: "30 18 1 * fri", t: friday, want: true},
+		{expr: "30 18 13 * mon", t: friday, want: true},
+		{expr: "30 18 1 * mon", t: friday, want: false},
+		{expr: "0 0 * * 7", t: time.Date(2024, time.December, 15, 0, 0, 0, 0, time.UTC), want: true},
+		{expr: "@daily", t: time.Date(2024, time.December, 15, 0, 0, 0, 0, time.UTC), want: true},
+	}
+
+	for _, tt := range tests {
+		e, err := cron.Parse(tt.expr)
+		if err != nil {
+			t.Errorf("Parse(%q) error = %v", tt.expr, err)
+
+			continue
+		}
+
+		if got := e.Match(tt.t); got != tt.want {
+			t.Errorf("%q Match(%v) = %v, want %v", tt.expr, tt.t, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package database
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/lovesway/hassio-addons/mq-lightshow/models"
+)
+
+const effectSelect = "SELECT effect_id, name, type, devices, palette, speed, direction, duration FROM effects"
+
+// scanEffect to read an Effect selected with effectSelect.
+func (sl *Sqlite) scanEffect(row scanner) (models.Effect, error) {
+	e := models.Effect{}
+
+	var devices, palette string
+
+	err := row.Scan(&e.ID, &e.Name, &e.Type, &devices, &palette, &e.Speed, &e.Direction, &e.Duration)
+	if err != nil {
+		return e, err
+	}
+
+	e.Devices = []models.Device{}
+
+	for _, v := range strings.Fields(devices) {
+		deviceID, err := strconv.Atoi(v)
+		if err != nil {
+			log.Error(err)
+
+			continue
+		}
+
+		e.Devices = sl.appendDevice(e.Devices, deviceID)
+	}
+
+	e.Palette = strings.Fields(palette)
+
+	return e, nil
+}
+
+// joinDeviceIDs returns the IDs of devices separated by spaces, as they are stored.
+func joinDeviceIDs(devices []models.Device) string {
+	ids := make([]string, 0, len(devices))
+	for _, d := range devices {
+		ids = append(ids, strconv.Itoa(d.ID))
+	}
+
+	return strings.Join(ids, " ")
+}
+
+// GetEffects to return a slice of Effect structs.
+func (sl *Sqlite) GetEffects() ([]models.Effect, error) {
+	effects := []models.Effect{}
+
+	rows, err := sl.db.Query(effectSelect + " ORDER BY name")
+	if err != nil {
+		log.Error(err)
+
+		return effects, err
+	}
+
+	defer func() {
+		if err = rows.Err(); err != nil {
+			log.Errorf("Sqlite GetEffects: %v.", err)
+		}
+
+		err := rows.Close()
+		if err != nil {
+			log.Error(err)
+		}
+	}()
+
+	for rows.Next() {
+		e, err := sl.scanEffect(rows)
+		if err != nil {
+			log.Error(err)
+
+			return effects, err
+		}
+
+		effects = append(effects, e)
+	}
+
+	return effects, err
+}
+
+// GetEffect to return a single Effect struct.
+func (sl *Sqlite) GetEffect(effectID int) (models.Effect, error) {
+	sqlStmt := effectSelect + " where effect_id = ?"
+
+	e, err := sl.scanEffect(sl.db.QueryRow(sqlStmt, effectID))
+	if err != nil {
+		log.Errorf("%q: %s", err, sqlStmt)
+
+		return models.Effect{}, err
+	}
+
+	return e, err
+}
+
+// AddEffect to db.
+func (sl *Sqlite) AddEffect(e models.Effect) (int, error) {
+	sqlStmt := "INSERT INTO effects(name, type, devices, palette, speed, direction, duration) " +
+		"values(?, ?, ?, ?, ?, ?, ?)"
+
+	res, err := sl.db.Exec(sqlStmt, e.Name, e.Type, joinDeviceIDs(e.Devices), strings.Join(e.Palette, " "),
+		e.Speed, e.Direction, e.Duration)
+	if err != nil {
+		log.Errorf("%q: %s", err, sqlStmt)
+
+		return 0, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		log.Error(err)
+
+		return 0, err
+	}
+
+	return int(id), err
+}
+
+// SetEffect to update an Effect.
+func (sl *Sqlite) SetEffect(e models.Effect) error {
+	sqlStmt := "UPDATE effects set name=?, type=?, devices=?, palette=?, speed=?, direction=?, duration=? " +
+		"where effect_id=?"
+
+	_, err := sl.db.Exec(sqlStmt, e.Name, e.Type, joinDeviceIDs(e.Devices), strings.Join(e.Palette, " "),
+		e.Speed, e.Direction, e.Duration, e.ID)
+	if err != nil {
+		log.Errorf("%q: %s", err, sqlStmt)
+	}
+
+	return err
+}
+
+// DeleteEffect function.
+func (sl *Sqlite) DeleteEffect(effectID int) error {
+	sqlStmt := "DELETE from effects where effect_id=?"
+
+	_, err := sl.db.Exec(sqlStmt, effectID)
+	if err != nil {
+		log.Errorf("%q: %s", err, sqlStmt)
+	}
+
+	return err
+}
+
+// IsEffectUsed reports whether a cycle of any show runs an effect.
+func (sl *Sqlite) IsEffectUsed(effectID int) (bool, error) {
+	var count int
+
+	sqlStmt := "SELECT count(*) FROM shows_cycles where effect_id = ?"
+
+	err := sl.db.QueryRow(sqlStmt, effectID).Scan(&count)
+	if err != nil {
+		log.Errorf("%q: %s", err, sqlStmt)
+	}
+
+	return count > 0, err
+}
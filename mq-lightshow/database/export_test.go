@@ -0,0 +1,22 @@
+package database
+
+import (
+	"os"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestMain(m *testing.M) {
+	SetLogger(zap.NewNop().Sugar())
+	os.Exit(m.Run())
+}
+
+// Migrate applies the migrations that the database does not have yet.
+var Migrate = (*Sqlite).migrate
+
+// SchemaVersion returns the highest migration version applied to the database.
+var SchemaVersion = (*Sqlite).schemaVersion
+
+// LatestVersion is the schema version after all migrations.
+var LatestVersion = migrations[len(migrations)-1].version
@@ -0,0 +1,198 @@
+package database
+
+import (
+	"fmt"
+	"time"
+)
+
+// migration is a numbered set of schema changes. Once released a migration must never be
+// edited, schema changes are made by appending a new migration with the next version.
+type migration struct {
+	version     int
+	description string
+	statements  []string
+}
+
+// migrations are applied in order to bring any database up to the current schema.
+// The first migration matches the schema created before migrations existed, so it uses
+// IF NOT EXISTS to adopt those databases without touching their data.
+var migrations = []migration{
+	{
+		version:     1,
+		description: "initial schema",
+		statements: []string{
+			"CREATE TABLE IF NOT EXISTS devices (device_id INTEGER PRIMARY KEY, name TEXT, topic TEXT, type INTEGER);",
+			"CREATE TABLE IF NOT EXISTS shows (show_id INTEGER PRIMARY KEY, name TEXT, topic TEXT, repeat TEXT, " +
+				"global_delay INTEGER, global_speed INTEGER, global_parameter1 TEXT, global_parameter2 TEXT);",
+			"CREATE TABLE IF NOT EXISTS shows_cycles (cycle_id INTEGER PRIMARY KEY, show_id INTEGER, " +
+				"scene_id INTEGER, cycles INTEGER, end_delay INTEGER, loop_include TEXT, global_delay INTEGER, " +
+				"global_speed INTEGER, global_parameter1 TEXT, global_parameter2 TEXT, 'order' INTEGER);",
+			"CREATE TABLE IF NOT EXISTS scenes (scene_id INTEGER PRIMARY KEY, name TEXT, allowed_devices TEXT);",
+			"CREATE TABLE IF NOT EXISTS scenes_group (group_id INTEGER PRIMARY KEY, scene_id INTEGER, " +
+				"delay INTEGER, global_delay INTEGER, 'order' INTEGER);",
+			"CREATE TABLE IF NOT EXISTS scenes_action (action_id INTEGER PRIMARY KEY, group_id INTEGER, " +
+				"devices TEXT, command TEXT, parameter TEXT, global_parameter TEXT, 'order' INTEGER);",
+		},
+	},
+	{
+		version:     2,
+		description: "device full topic and hostname",
+		statements: []string{
+			"ALTER TABLE devices ADD COLUMN full_topic TEXT NOT NULL DEFAULT '';",
+			"ALTER TABLE devices ADD COLUMN hostname TEXT NOT NULL DEFAULT '';",
+		},
+	},
+	{
+		version:     3,
+		description: "device capabilities",
+		statements: []string{
+			"ALTER TABLE devices ADD COLUMN supports_dimmer INTEGER NOT NULL DEFAULT 0;",
+			"ALTER TABLE devices ADD COLUMN supports_color INTEGER NOT NULL DEFAULT 0;",
+			"ALTER TABLE devices ADD COLUMN supports_ct INTEGER NOT NULL DEFAULT 0;",
+		},
+	},
+	{
+		version:     4,
+		description: "color actions",
+		statements: []string{
+			"ALTER TABLE scenes_action ADD COLUMN type TEXT NOT NULL DEFAULT '';",
+			"ALTER TABLE scenes_action ADD COLUMN color TEXT NOT NULL DEFAULT '';",
+			"ALTER TABLE scenes_action ADD COLUMN ct INTEGER NOT NULL DEFAULT 0;",
+			"ALTER TABLE scenes_action ADD COLUMN brightness INTEGER NOT NULL DEFAULT 0;",
+			"ALTER TABLE scenes_action ADD COLUMN transition REAL NOT NULL DEFAULT 0;",
+		},
+	},
+	{
+		version:     5,
+		description: "group fades",
+		statements: []string{
+			"ALTER TABLE scenes_group ADD COLUMN fade_duration REAL NOT NULL DEFAULT 0;",
+			"ALTER TABLE scenes_group ADD COLUMN fade_rate INTEGER NOT NULL DEFAULT 0;",
+			"ALTER TABLE scenes_group ADD COLUMN fade_easing TEXT NOT NULL DEFAULT '';",
+		},
+	},
+	{
+		version:     6,
+		description: "effects",
+		statements: []string{
+			"CREATE TABLE effects (effect_id INTEGER PRIMARY KEY, name TEXT NOT NULL, type TEXT NOT NULL, " +
+				"devices TEXT NOT NULL, palette TEXT NOT NULL, speed REAL NOT NULL, direction TEXT NOT NULL, " +
+				"duration REAL NOT NULL);",
+			"ALTER TABLE shows_cycles ADD COLUMN effect_id INTEGER NOT NULL DEFAULT 0;",
+		},
+	},
+	{
+		version:     7,
+		description: "palettes",
+		statements: []string{
+			"CREATE TABLE palettes (palette_id INTEGER PRIMARY KEY, name TEXT NOT NULL, colors TEXT NOT NULL);",
+			"ALTER TABLE shows ADD COLUMN palette_id INTEGER NOT NULL DEFAULT 0;",
+			"ALTER TABLE scenes_action ADD COLUMN palette_color TEXT NOT NULL DEFAULT '';",
+		},
+	},
+	{
+		version:     8,
+		description: "zones",
+		statements: []string{
+			"ALTER TABLE devices ADD COLUMN zones TEXT NOT NULL DEFAULT '';",
+			"ALTER TABLE scenes ADD COLUMN allowed_zones TEXT NOT NULL DEFAULT '';",
+			"ALTER TABLE scenes_action ADD COLUMN zones TEXT NOT NULL DEFAULT '';",
+		},
+	},
+	{
+		version:     9,
+		description: "schedules",
+		statements: []string{
+			"CREATE TABLE schedules (schedule_id INTEGER PRIMARY KEY, name TEXT NOT NULL, show_id INTEGER NOT NULL, " +
+				"enabled INTEGER NOT NULL, cron TEXT NOT NULL, days TEXT NOT NULL, start_time TEXT NOT NULL, " +
+				"stop_time TEXT NOT NULL, start_date TEXT NOT NULL, end_date TEXT NOT NULL);",
+		},
+	},
+	{
+		version:     10,
+		description: "show priority",
+		statements: []string{
+			"ALTER TABLE shows ADD COLUMN priority INTEGER NOT NULL DEFAULT 0;",
+			"ALTER TABLE shows ADD COLUMN conflict_policy TEXT NOT NULL DEFAULT 'share';",
+		},
+	},
+}
+
+// schemaVersion returns the highest migration version applied to the database.
+func (sl *Sqlite) schemaVersion() (int, error) {
+	sqlStmt := "CREATE TABLE IF NOT EXISTS schema_migrations " +
+		"(version INTEGER PRIMARY KEY, description TEXT, applied_at TEXT);"
+
+	_, err := sl.db.Exec(sqlStmt)
+	if err != nil {
+		log.Errorf("%q: %s", err, sqlStmt)
+
+		return 0, err
+	}
+
+	var version int
+
+	sqlStmt = "SELECT COALESCE(MAX(version), 0) FROM schema_migrations"
+
+	err = sl.db.QueryRow(sqlStmt).Scan(&version)
+	if err != nil {
+		log.Errorf("%q: %s", err, sqlStmt)
+	}
+
+	return version, err
+}
+
+// migrate applies each migration newer than the current schema version in its own transaction.
+func (sl *Sqlite) migrate() error {
+	version, err := sl.schemaVersion()
+	if err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		if m.version <= version {
+			continue
+		}
+
+		log.Infof("applying database migration %v: %s", m.version, m.description)
+
+		if err := sl.applyMigration(m); err != nil {
+			return fmt.Errorf("migration %v: %w", m.version, err)
+		}
+	}
+
+	return nil
+}
+
+func (sl *Sqlite) applyMigration(m migration) error {
+	tx, err := sl.conn.Begin()
+	if err != nil {
+		return err
+	}
+
+	for _, sqlStmt := range m.statements {
+		if _, err := tx.Exec(sqlStmt); err != nil {
+			log.Errorf("%q: %s", err, sqlStmt)
+
+			if rbErr := tx.Rollback(); rbErr != nil {
+				log.Error(rbErr)
+			}
+
+			return err
+		}
+	}
+
+	sqlStmt := "INSERT INTO schema_migrations(version, description, applied_at) values(?, ?, ?)"
+
+	if _, err := tx.Exec(sqlStmt, m.version, m.description, time.Now().UTC().Format(time.RFC3339)); err != nil {
+		log.Errorf("%q: %s", err, sqlStmt)
+
+		if rbErr := tx.Rollback(); rbErr != nil {
+			log.Error(rbErr)
+		}
+
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,99 @@
+package database_test
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/lovesway/hassio-addons/mq-lightshow/database"
+	"github.com/lovesway/hassio-addons/mq-lightshow/devicetypes"
+)
+
+// createDatabase creates a database file with the given statements and returns its path.
+func createDatabase(t *testing.T, statements ...string) string {
+	t.Helper()
+
+	dbf := filepath.Join(t.TempDir(), "sqlite.db")
+
+	conn, err := sql.Open("sqlite3", dbf)
+	if err != nil {
+		t.Fatalf("sql.Open error = %v", err)
+	}
+
+	defer conn.Close()
+
+	for _, s := range statements {
+		if _, err := conn.Exec(s); err != nil {
+			t.Fatalf("%v: %v", s, err)
+		}
+	}
+
+	return dbf
+}
+
+func TestMigrateLegacyDatabase(t *testing.T) {
+	t.Parallel()
+
+	// the schema from before migrations existed, with a device and a show in it.
+	dbf := createDatabase(t,
+		"CREATE TABLE devices (device_id INTEGER PRIMARY KEY, name TEXT, topic TEXT, type INTEGER);",
+		"CREATE TABLE shows (show_id INTEGER PRIMARY KEY, name TEXT, topic TEXT, repeat TEXT, "+
+			"global_delay INTEGER, global_speed INTEGER, global_parameter1 TEXT, global_parameter2 TEXT);",
+		"INSERT INTO devices (device_id, name, topic, type) VALUES (1, 'lamp', 'lamp', 1);",
+		"INSERT INTO shows (show_id, name, topic, repeat, global_delay, global_speed, global_parameter1, "+
+			"global_parameter2) VALUES (1, 'party', 'party', 'false', 0, 0, '', '');",
+	)
+
+	sl := database.NewSqlite(devicetypes.NewDeviceTypes())
+	if err := sl.Open(dbf); err != nil {
+		t.Fatalf("Open error = %v", err)
+	}
+
+	defer sl.Disconnect()
+
+	if version, err := database.SchemaVersion(sl); err != nil || version != database.LatestVersion {
+		t.Errorf("SchemaVersion = %v, %v, want %v", version, err, database.LatestVersion)
+	}
+
+	device, err := sl.GetDevice(1)
+	if err != nil || device.Name != "lamp" || device.FullTopic != "" || len(device.Zones) != 0 {
+		t.Errorf("GetDevice(1) = %+v, %v, want the legacy lamp", device, err)
+	}
+
+	show, err := sl.GetShow(1)
+	if err != nil || show.Name != "party" || show.Priority != 0 || show.ConflictPolicy != "share" {
+		t.Errorf("GetShow(1) = %+v, %v, want the legacy show with the default conflict policy", show, err)
+	}
+
+	// a migrated database is left as it is.
+	if err := database.Migrate(sl); err != nil {
+		t.Errorf("Migrate of a migrated database error = %v", err)
+	}
+
+	if version, err := database.SchemaVersion(sl); err != nil || version != database.LatestVersion {
+		t.Errorf("SchemaVersion after a second Migrate = %v, %v, want %v", version, err, database.LatestVersion)
+	}
+}
+
+func TestOpenFailedMigration(t *testing.T) {
+	t.Parallel()
+
+	// migration 2 adds full_topic, which this devices table already has.
+	dbf := createDatabase(t,
+		"CREATE TABLE devices (device_id INTEGER PRIMARY KEY, name TEXT, topic TEXT, type INTEGER, full_topic TEXT);",
+	)
+
+	sl := database.NewSqlite(devicetypes.NewDeviceTypes())
+
+	err := sl.Open(dbf)
+	if err == nil {
+		t.Fatalf("Open error = nil, want the migration error")
+	}
+
+	defer sl.Disconnect()
+
+	// the failed migration is rolled back.
+	if version, err := database.SchemaVersion(sl); err != nil || version != 1 {
+		t.Errorf("SchemaVersion = %v, %v, want 1", version, err)
+	}
+}
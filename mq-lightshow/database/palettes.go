@@ -0,0 +1,134 @@
+package database
+
+import (
+	"strings"
+
+	"github.com/lovesway/hassio-addons/mq-lightshow/models"
+)
+
+const paletteSelect = "SELECT palette_id, name, colors FROM palettes"
+
+// scanPalette to read a Palette selected with paletteSelect.
+func scanPalette(row scanner) (models.Palette, error) {
+	p := models.Palette{}
+
+	var colors string
+
+	err := row.Scan(&p.ID, &p.Name, &colors)
+	if err != nil {
+		return p, err
+	}
+
+	p.Colors = strings.Fields(colors)
+
+	return p, nil
+}
+
+// GetPalettes to return a slice of Palette structs.
+func (sl *Sqlite) GetPalettes() ([]models.Palette, error) {
+	palettes := []models.Palette{}
+
+	rows, err := sl.db.Query(paletteSelect + " ORDER BY name")
+	if err != nil {
+		log.Error(err)
+
+		return palettes, err
+	}
+
+	defer func() {
+		if err = rows.Err(); err != nil {
+			log.Errorf("Sqlite GetPalettes: %v.", err)
+		}
+
+		err := rows.Close()
+		if err != nil {
+			log.Error(err)
+		}
+	}()
+
+	for rows.Next() {
+		p, err := scanPalette(rows)
+		if err != nil {
+			log.Error(err)
+
+			return palettes, err
+		}
+
+		palettes = append(palettes, p)
+	}
+
+	return palettes, err
+}
+
+// GetPalette to return a single Palette struct.
+func (sl *Sqlite) GetPalette(paletteID int) (models.Palette, error) {
+	sqlStmt := paletteSelect + " where palette_id = ?"
+
+	p, err := scanPalette(sl.db.QueryRow(sqlStmt, paletteID))
+	if err != nil {
+		log.Errorf("%q: %s", err, sqlStmt)
+
+		return models.Palette{}, err
+	}
+
+	return p, err
+}
+
+// AddPalette to db.
+func (sl *Sqlite) AddPalette(p models.Palette) (int, error) {
+	sqlStmt := "INSERT INTO palettes(name, colors) values(?, ?)"
+
+	res, err := sl.db.Exec(sqlStmt, p.Name, strings.Join(p.Colors, " "))
+	if err != nil {
+		log.Errorf("%q: %s", err, sqlStmt)
+
+		return 0, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		log.Error(err)
+
+		return 0, err
+	}
+
+	return int(id), err
+}
+
+// SetPalette to update a Palette.
+func (sl *Sqlite) SetPalette(p models.Palette) error {
+	sqlStmt := "UPDATE palettes set name=?, colors=? where palette_id=?"
+
+	_, err := sl.db.Exec(sqlStmt, p.Name, strings.Join(p.Colors, " "), p.ID)
+	if err != nil {
+		log.Errorf("%q: %s", err, sqlStmt)
+	}
+
+	return err
+}
+
+// DeletePalette function.
+func (sl *Sqlite) DeletePalette(paletteID int) error {
+	sqlStmt := "DELETE from palettes where palette_id=?"
+
+	_, err := sl.db.Exec(sqlStmt, paletteID)
+	if err != nil {
+		log.Errorf("%q: %s", err, sqlStmt)
+	}
+
+	return err
+}
+
+// IsPaletteUsed reports whether any show uses a palette.
+func (sl *Sqlite) IsPaletteUsed(paletteID int) (bool, error) {
+	var count int
+
+	sqlStmt := "SELECT count(*) FROM shows where palette_id = ?"
+
+	err := sl.db.QueryRow(sqlStmt, paletteID).Scan(&count)
+	if err != nil {
+		log.Errorf("%q: %s", err, sqlStmt)
+	}
+
+	return count > 0, err
+}
@@ -0,0 +1,145 @@
+package database
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/lovesway/hassio-addons/mq-lightshow/models"
+)
+
+const scheduleSelect = "SELECT schedule_id, schedules.name, schedules.show_id, ifnull(shows.name, ''), enabled, " +
+	"cron, days, start_time, stop_time, start_date, end_date FROM schedules " +
+	"LEFT JOIN shows ON shows.show_id = schedules.show_id"
+
+// scanSchedule to read a Schedule selected with scheduleSelect.
+func scanSchedule(row scanner) (models.Schedule, error) {
+	s := models.Schedule{Days: []int{}}
+
+	var days string
+
+	err := row.Scan(&s.ID, &s.Name, &s.ShowID, &s.ShowName, &s.Enabled,
+		&s.Cron, &days, &s.StartTime, &s.StopTime, &s.StartDate, &s.EndDate)
+	if err != nil {
+		return s, err
+	}
+
+	for _, d := range strings.Fields(days) {
+		day, err := strconv.Atoi(d)
+		if err != nil {
+			return s, err
+		}
+
+		s.Days = append(s.Days, day)
+	}
+
+	return s, nil
+}
+
+// joinDays to store the days of a Schedule in a list separated by spaces.
+func joinDays(days []int) string {
+	ds := []string{}
+	for _, d := range days {
+		ds = append(ds, strconv.Itoa(d))
+	}
+
+	return strings.Join(ds, " ")
+}
+
+// GetSchedules to return a slice of Schedule structs.
+func (sl *Sqlite) GetSchedules() ([]models.Schedule, error) {
+	schedules := []models.Schedule{}
+
+	rows, err := sl.db.Query(scheduleSelect + " ORDER BY schedules.name")
+	if err != nil {
+		log.Error(err)
+
+		return schedules, err
+	}
+
+	defer func() {
+		if err = rows.Err(); err != nil {
+			log.Errorf("Sqlite GetSchedules: %v.", err)
+		}
+
+		err := rows.Close()
+		if err != nil {
+			log.Error(err)
+		}
+	}()
+
+	for rows.Next() {
+		s, err := scanSchedule(rows)
+		if err != nil {
+			log.Error(err)
+
+			return schedules, err
+		}
+
+		schedules = append(schedules, s)
+	}
+
+	return schedules, err
+}
+
+// GetSchedule to return a single Schedule struct.
+func (sl *Sqlite) GetSchedule(scheduleID int) (models.Schedule, error) {
+	sqlStmt := scheduleSelect + " where schedule_id = ?"
+
+	s, err := scanSchedule(sl.db.QueryRow(sqlStmt, scheduleID))
+	if err != nil {
+		log.Errorf("%q: %s", err, sqlStmt)
+
+		return models.Schedule{}, err
+	}
+
+	return s, err
+}
+
+// AddSchedule to db.
+func (sl *Sqlite) AddSchedule(s models.Schedule) (int, error) {
+	sqlStmt := "INSERT INTO schedules(name, show_id, enabled, cron, days, start_time, stop_time, " +
+		"start_date, end_date) values(?, ?, ?, ?, ?, ?, ?, ?, ?)"
+
+	res, err := sl.db.Exec(sqlStmt, s.Name, s.ShowID, s.Enabled, s.Cron, joinDays(s.Days),
+		s.StartTime, s.StopTime, s.StartDate, s.EndDate)
+	if err != nil {
+		log.Errorf("%q: %s", err, sqlStmt)
+
+		return 0, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		log.Error(err)
+
+		return 0, err
+	}
+
+	return int(id), err
+}
+
+// SetSchedule to update a Schedule.
+func (sl *Sqlite) SetSchedule(s models.Schedule) error {
+	sqlStmt := "UPDATE schedules set name=?, show_id=?, enabled=?, cron=?, days=?, start_time=?, stop_time=?, " +
+		"start_date=?, end_date=? where schedule_id=?"
+
+	_, err := sl.db.Exec(sqlStmt, s.Name, s.ShowID, s.Enabled, s.Cron, joinDays(s.Days),
+		s.StartTime, s.StopTime, s.StartDate, s.EndDate, s.ID)
+	if err != nil {
+		log.Errorf("%q: %s", err, sqlStmt)
+	}
+
+	return err
+}
+
+// DeleteSchedule function.
+func (sl *Sqlite) DeleteSchedule(scheduleID int) error {
+	sqlStmt := "DELETE from schedules where schedule_id=?"
+
+	_, err := sl.db.Exec(sqlStmt, scheduleID)
+	if err != nil {
+		log.Errorf("%q: %s", err, sqlStmt)
+	}
+
+	return err
+}
@@ -3,7 +3,6 @@ package database
 
 import (
 	"database/sql"
-	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -17,24 +16,27 @@ import (
 
 // Sqlite struct to represent a class.
 type Sqlite struct {
-	db          *sql.DB
-	deviceTypes []models.DeviceType
+	db          querier // the connection, or the transaction of a Sqlite passed to Transaction.
+	conn        *sql.DB
+	deviceTypes *devicetypes.DeviceTypes
+}
+
+// querier is implemented by both sql.DB and sql.Tx, so the same queries can run in a transaction.
+type querier interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
 }
 
 // NewSqlite method to instantiate class/struct.
-func NewSqlite(dt devicetypes.DeviceTypes) *Sqlite {
+func NewSqlite(dt *devicetypes.DeviceTypes) *Sqlite {
 	database := &Sqlite{
-		deviceTypes: dt.GetDeviceTypes(),
+		deviceTypes: dt,
 	}
 
 	return database
 }
 
-// SetDeviceTypes to set deviceTypes in db instance.
-func (sl *Sqlite) SetDeviceTypes(t []models.DeviceType) {
-	sl.deviceTypes = t
-}
-
 // global log adapter to support zap sugar.
 var log *zap.SugaredLogger
 
@@ -48,7 +50,7 @@ func (sl *Sqlite) GetShows() ([]models.Show, error) {
 	shows := []models.Show{}
 
 	sqlStmt := "SELECT show_id, name, topic, repeat, " +
-		"global_delay, global_speed, global_parameter1, global_parameter2 FROM shows"
+		"global_delay, global_speed, global_parameter1, global_parameter2, palette_id, priority, conflict_policy FROM shows"
 
 	rows, err := sl.db.Query(sqlStmt)
 	if err != nil {
@@ -85,7 +87,14 @@ func (sl *Sqlite) GetShows() ([]models.Show, error) {
 
 		var globalParameter2 string
 
-		err = rows.Scan(&showID, &name, &topic, &repeat, &globalDelay, &globalSpeed, &globalParameter1, &globalParameter2)
+		var paletteID int
+
+		var priority int
+
+		var conflictPolicy string
+
+		err = rows.Scan(&showID, &name, &topic, &repeat, &globalDelay, &globalSpeed, &globalParameter1, &globalParameter2,
+			&paletteID, &priority, &conflictPolicy)
 		if err != nil {
 			log.Errorf("%q: %s", err, sqlStmt)
 
@@ -101,6 +110,9 @@ func (sl *Sqlite) GetShows() ([]models.Show, error) {
 			GlobalSpeed:      globalSpeed,
 			GlobalParameter1: globalParameter1,
 			GlobalParameter2: globalParameter2,
+			PaletteID:        paletteID,
+			Priority:         priority,
+			ConflictPolicy:   conflictPolicy,
 		}
 		shows = append(shows, ts)
 	}
@@ -110,19 +122,21 @@ func (sl *Sqlite) GetShows() ([]models.Show, error) {
 
 // GetShow to return a single Show struct.
 func (sl *Sqlite) GetShow(showID int) (models.Show, error) {
-	sqlStmt := fmt.Sprintf("SELECT name, topic, repeat, "+
-		"global_delay, global_speed, global_parameter1, global_parameter2 FROM shows where show_id = '%v'", showID)
+	sqlStmt := "SELECT name, topic, repeat, " +
+		"global_delay, global_speed, global_parameter1, global_parameter2, palette_id, priority, conflict_policy " +
+		"FROM shows where show_id = ?"
 
-	var name, topic, globalParameter1, globalParameter2 string
+	var name, topic, globalParameter1, globalParameter2, conflictPolicy string
 
 	var repeat bool
 
 	var globalDelay float32
 
-	var globalSpeed int
+	var globalSpeed, paletteID, priority int
 
-	err := sl.db.QueryRow(sqlStmt).Scan(
-		&name, &topic, &repeat, &globalDelay, &globalSpeed, &globalParameter1, &globalParameter2,
+	err := sl.db.QueryRow(sqlStmt, showID).Scan(
+		&name, &topic, &repeat, &globalDelay, &globalSpeed, &globalParameter1, &globalParameter2, &paletteID,
+		&priority, &conflictPolicy,
 	)
 	if err != nil {
 		log.Errorf("%q: %s", err, sqlStmt)
@@ -139,6 +153,9 @@ func (sl *Sqlite) GetShow(showID int) (models.Show, error) {
 		GlobalSpeed:      globalSpeed,
 		GlobalParameter1: globalParameter1,
 		GlobalParameter2: globalParameter2,
+		PaletteID:        paletteID,
+		Priority:         priority,
+		ConflictPolicy:   conflictPolicy,
 	}
 
 	return show, err
@@ -146,19 +163,21 @@ func (sl *Sqlite) GetShow(showID int) (models.Show, error) {
 
 // GetShowByTopic to return a single Show struct.
 func (sl *Sqlite) GetShowByTopic(topic string) (models.Show, error) {
-	sqlStmt := fmt.Sprintf("SELECT show_id, name, repeat, "+
-		"global_delay, global_speed, global_parameter1, global_parameter2 FROM shows where topic='%v'", topic)
+	sqlStmt := "SELECT show_id, name, repeat, " +
+		"global_delay, global_speed, global_parameter1, global_parameter2, palette_id, priority, conflict_policy " +
+		"FROM shows where topic = ?"
 
-	var showID, globalSpeed int
+	var showID, globalSpeed, paletteID, priority int
 
-	var name, globalParameter1, globalParameter2 string
+	var name, globalParameter1, globalParameter2, conflictPolicy string
 
 	var repeat bool
 
 	var globalDelay float32
 
-	err := sl.db.QueryRow(sqlStmt).Scan(
-		&showID, &name, &repeat, &globalDelay, &globalSpeed, &globalParameter1, &globalParameter2,
+	err := sl.db.QueryRow(sqlStmt, topic).Scan(
+		&showID, &name, &repeat, &globalDelay, &globalSpeed, &globalParameter1, &globalParameter2, &paletteID,
+		&priority, &conflictPolicy,
 	)
 	if err != nil {
 		log.Errorf("%q: %s", err, sqlStmt)
@@ -175,6 +194,9 @@ func (sl *Sqlite) GetShowByTopic(topic string) (models.Show, error) {
 		GlobalSpeed:      globalSpeed,
 		GlobalParameter1: globalParameter1,
 		GlobalParameter2: globalParameter2,
+		PaletteID:        paletteID,
+		Priority:         priority,
+		ConflictPolicy:   conflictPolicy,
 	}
 
 	return show, err
@@ -182,33 +204,37 @@ func (sl *Sqlite) GetShowByTopic(topic string) (models.Show, error) {
 
 // AddShow to db.
 func (sl *Sqlite) AddShow(s models.Show) (int, error) {
-	sqlStmt := fmt.Sprintf(
-		"INSERT INTO shows(name, topic, repeat, global_delay, global_speed, global_parameter1, global_parameter2)"+
-			" values('%s', '%s', '%t', '%v', '%v', '%s', '%s')",
-		s.Name, s.Topic, s.Repeat, s.GlobalDelay, s.GlobalSpeed, s.GlobalParameter1, s.GlobalParameter2,
-	)
+	sqlStmt := "INSERT INTO shows(name, topic, repeat, global_delay, global_speed, global_parameter1, " +
+		"global_parameter2, palette_id, priority, conflict_policy) values(?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
 
-	res, err := sl.db.Exec(sqlStmt)
+	res, err := sl.db.Exec(
+		sqlStmt, s.Name, s.Topic, s.Repeat, s.GlobalDelay, s.GlobalSpeed, s.GlobalParameter1, s.GlobalParameter2,
+		s.PaletteID, s.Priority, s.ConflictPolicy,
+	)
 	if err != nil {
 		log.Errorf("%q: %s", err, sqlStmt)
 
 		return 0, err
 	}
 
-	id, _ := res.LastInsertId()
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
 
 	return int(id), err
 }
 
 // SetShow to update a Show.
 func (sl *Sqlite) SetShow(s models.Show) error {
-	sqlStmt := fmt.Sprintf(
-		"UPDATE shows set name='%s', topic='%s', repeat='%t', "+
-			"global_delay='%v', global_speed='%v', global_parameter1='%s', global_parameter2='%s' where show_id='%v'",
-		s.Name, s.Topic, s.Repeat, s.GlobalDelay, s.GlobalSpeed, s.GlobalParameter1, s.GlobalParameter2, s.ID,
-	)
+	sqlStmt := "UPDATE shows set name=?, topic=?, repeat=?, " +
+		"global_delay=?, global_speed=?, global_parameter1=?, global_parameter2=?, palette_id=?, priority=?, " +
+		"conflict_policy=? where show_id=?"
 
-	_, err := sl.db.Exec(sqlStmt)
+	_, err := sl.db.Exec(
+		sqlStmt, s.Name, s.Topic, s.Repeat, s.GlobalDelay, s.GlobalSpeed, s.GlobalParameter1, s.GlobalParameter2,
+		s.PaletteID, s.Priority, s.ConflictPolicy, s.ID,
+	)
 	if err != nil {
 		log.Errorf("%q: %s", err, sqlStmt)
 	}
@@ -218,18 +244,27 @@ func (sl *Sqlite) SetShow(s models.Show) error {
 
 // DeleteShow function.
 func (sl *Sqlite) DeleteShow(showID int) error {
-	sqlStmt := fmt.Sprintf("DELETE from shows_cycles where show_id=%v", showID)
+	sqlStmt := "DELETE from schedules where show_id=?"
+
+	_, err := sl.db.Exec(sqlStmt, showID)
+	if err != nil {
+		log.Errorf("%q: %s", err, sqlStmt)
 
-	_, err := sl.db.Exec(sqlStmt)
+		return err
+	}
+
+	sqlStmt = "DELETE from shows_cycles where show_id=?"
+
+	_, err = sl.db.Exec(sqlStmt, showID)
 	if err != nil {
 		log.Errorf("%q: %s", err, sqlStmt)
 
 		return err
 	}
 
-	sqlStmt = fmt.Sprintf("DELETE from shows where show_id=%v", showID)
+	sqlStmt = "DELETE from shows where show_id=?"
 
-	_, err = sl.db.Exec(sqlStmt)
+	_, err = sl.db.Exec(sqlStmt, showID)
 	if err != nil {
 		log.Errorf("%q: %s", err, sqlStmt)
 	}
@@ -241,7 +276,7 @@ func (sl *Sqlite) DeleteShow(showID int) error {
 func (sl *Sqlite) GetShowCycles(showID int) ([]models.Cycle, error) {
 	cycles := []models.Cycle{}
 
-	rows, err := sl.db.Query("SELECT cycle_id, show_id, scene_id, cycles, end_delay, loop_include, "+
+	rows, err := sl.db.Query("SELECT cycle_id, show_id, scene_id, effect_id, cycles, end_delay, loop_include, "+
 		"global_delay, global_speed, global_parameter1, global_parameter2 FROM shows_cycles where show_id = ?", showID)
 	if err != nil {
 		log.Error(err)
@@ -261,7 +296,7 @@ func (sl *Sqlite) GetShowCycles(showID int) ([]models.Cycle, error) {
 	}()
 
 	for rows.Next() {
-		var cycleID, showID, sceneID, cyclesVal, globalSpeed int
+		var cycleID, showID, sceneID, effectID, cyclesVal, globalSpeed int
 
 		var endDelay, globalDelay float32
 
@@ -270,7 +305,7 @@ func (sl *Sqlite) GetShowCycles(showID int) ([]models.Cycle, error) {
 		var globalParameter1, globalParameter2 string
 
 		err = rows.Scan(
-			&cycleID, &showID, &sceneID, &cyclesVal, &endDelay, &loopInclude,
+			&cycleID, &showID, &sceneID, &effectID, &cyclesVal, &endDelay, &loopInclude,
 			&globalDelay, &globalSpeed, &globalParameter1, &globalParameter2,
 		)
 		if err != nil {
@@ -283,6 +318,7 @@ func (sl *Sqlite) GetShowCycles(showID int) ([]models.Cycle, error) {
 			ID:               cycleID,
 			ShowID:           showID,
 			SceneID:          sceneID,
+			EffectID:         effectID,
 			SceneCycles:      cyclesVal,
 			EndDelay:         endDelay,
 			LoopInclude:      loopInclude,
@@ -299,10 +335,10 @@ func (sl *Sqlite) GetShowCycles(showID int) ([]models.Cycle, error) {
 
 // GetShowCycle to return a single Show struct.
 func (sl *Sqlite) GetShowCycle(cycleID int) (models.Cycle, error) {
-	sqlStmt := fmt.Sprintf("SELECT show_id, scene_id, cycles, end_delay, loop_include, "+
-		"global_delay, global_speed, global_parameter1, global_parameter2 FROM shows_cycles where cycle_id = '%v'", cycleID)
+	sqlStmt := "SELECT show_id, scene_id, effect_id, cycles, end_delay, loop_include, " +
+		"global_delay, global_speed, global_parameter1, global_parameter2 FROM shows_cycles where cycle_id = ?"
 
-	var showID, sceneID, cycles, globalSpeed int
+	var showID, sceneID, effectID, cycles, globalSpeed int
 
 	var endDelay, globalDelay float32
 
@@ -310,8 +346,9 @@ func (sl *Sqlite) GetShowCycle(cycleID int) (models.Cycle, error) {
 
 	var globalParameter1, globalParameter2 string
 
-	err := sl.db.QueryRow(sqlStmt).Scan(
-		&showID, &sceneID, &cycles, &endDelay, &loopInclude, &globalDelay, &globalSpeed, &globalParameter1, &globalParameter2,
+	err := sl.db.QueryRow(sqlStmt, cycleID).Scan(
+		&showID, &sceneID, &effectID, &cycles, &endDelay, &loopInclude, &globalDelay, &globalSpeed,
+		&globalParameter1, &globalParameter2,
 	)
 	if err != nil {
 		log.Errorf("%q: %s", err, sqlStmt)
@@ -323,6 +360,7 @@ func (sl *Sqlite) GetShowCycle(cycleID int) (models.Cycle, error) {
 		ID:               cycleID,
 		ShowID:           showID,
 		SceneID:          sceneID,
+		EffectID:         effectID,
 		SceneCycles:      cycles,
 		EndDelay:         endDelay,
 		LoopInclude:      loopInclude,
@@ -337,14 +375,13 @@ func (sl *Sqlite) GetShowCycle(cycleID int) (models.Cycle, error) {
 
 // AddShowCycle to db.
 func (sl *Sqlite) AddShowCycle(c models.Cycle) (int, error) {
-	sqlStmt := fmt.Sprintf(
-		"INSERT INTO shows_cycles(show_id, scene_id, cycles, end_delay, loop_include, global_delay, global_speed, "+
-			"global_parameter1, global_parameter2) values('%v', '%v', '%v', '%v', '%t', '%v', '%v', '%s', '%s')",
-		c.ShowID, c.SceneID, c.SceneCycles, c.EndDelay, c.LoopInclude,
+	sqlStmt := "INSERT INTO shows_cycles(show_id, scene_id, effect_id, cycles, end_delay, loop_include, " +
+		"global_delay, global_speed, global_parameter1, global_parameter2) values(?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+
+	res, err := sl.db.Exec(
+		sqlStmt, c.ShowID, c.SceneID, c.EffectID, c.SceneCycles, c.EndDelay, c.LoopInclude,
 		c.GlobalDelay, c.GlobalSpeed, c.GlobalParameter1, c.GlobalParameter2,
 	)
-
-	res, err := sl.db.Exec(sqlStmt)
 	if err != nil {
 		log.Errorf("%q: %s", err, sqlStmt)
 
@@ -361,14 +398,14 @@ func (sl *Sqlite) AddShowCycle(c models.Cycle) (int, error) {
 
 // SetShowCycle to update a Cycle.
 func (sl *Sqlite) SetShowCycle(c models.Cycle) error {
-	sqlStmt := fmt.Sprintf("UPDATE shows_cycles set show_id='%v', scene_id='%v', "+
-		"cycles='%v', end_delay='%v', loop_include='%t', global_delay='%v', global_speed='%v', "+
-		"global_parameter1='%s', global_parameter2='%s' where cycle_id='%v'",
-		c.ShowID, c.SceneID, c.SceneCycles, c.EndDelay, c.LoopInclude, c.GlobalDelay,
+	sqlStmt := "UPDATE shows_cycles set show_id=?, scene_id=?, effect_id=?, " +
+		"cycles=?, end_delay=?, loop_include=?, global_delay=?, global_speed=?, " +
+		"global_parameter1=?, global_parameter2=? where cycle_id=?"
+
+	_, err := sl.db.Exec(
+		sqlStmt, c.ShowID, c.SceneID, c.EffectID, c.SceneCycles, c.EndDelay, c.LoopInclude, c.GlobalDelay,
 		c.GlobalSpeed, c.GlobalParameter1, c.GlobalParameter2, c.ID,
 	)
-
-	_, err := sl.db.Exec(sqlStmt)
 	if err != nil {
 		log.Errorf("%q: %s", err, sqlStmt)
 	}
@@ -378,9 +415,9 @@ func (sl *Sqlite) SetShowCycle(c models.Cycle) error {
 
 // DeleteShowCycle function.
 func (sl *Sqlite) DeleteShowCycle(cycleID int) error {
-	sqlStmt := fmt.Sprintf("DELETE from shows_cycles where cycle_id=%v", cycleID)
+	sqlStmt := "DELETE from shows_cycles where cycle_id=?"
 
-	_, err := sl.db.Exec(sqlStmt)
+	_, err := sl.db.Exec(sqlStmt, cycleID)
 	if err != nil {
 		log.Errorf("%q: %s", err, sqlStmt)
 	}
@@ -392,7 +429,7 @@ func (sl *Sqlite) DeleteShowCycle(cycleID int) error {
 func (sl *Sqlite) GetScenes() ([]models.Scene, error) {
 	s := []models.Scene{}
 
-	sqlStmt := "SELECT scene_id, name, allowed_devices FROM scenes"
+	sqlStmt := "SELECT scene_id, name, allowed_devices, allowed_zones FROM scenes"
 
 	rows, err := sl.db.Query(sqlStmt)
 	if err != nil {
@@ -415,9 +452,9 @@ func (sl *Sqlite) GetScenes() ([]models.Scene, error) {
 	for rows.Next() {
 		var sceneID int
 
-		var name, allowedDevicesString string
+		var name, allowedDevicesString, allowedZones string
 
-		err = rows.Scan(&sceneID, &name, &allowedDevicesString)
+		err = rows.Scan(&sceneID, &name, &allowedDevicesString, &allowedZones)
 		if err != nil {
 			log.Errorf("%q: %s", err, sqlStmt)
 
@@ -436,11 +473,16 @@ func (sl *Sqlite) GetScenes() ([]models.Scene, error) {
 					continue
 				}
 
-				allowedDevices = append(allowedDevices, sl.GetDevice(tID))
+				allowedDevices = sl.appendDevice(allowedDevices, tID)
 			}
 		}
 
-		s = append(s, models.Scene{ID: sceneID, Name: name, AllowedDevices: allowedDevices})
+		s = append(s, models.Scene{
+			ID:             sceneID,
+			Name:           name,
+			AllowedDevices: allowedDevices,
+			AllowedZones:   strings.Fields(allowedZones),
+		})
 	}
 
 	return s, err
@@ -448,11 +490,11 @@ func (sl *Sqlite) GetScenes() ([]models.Scene, error) {
 
 // GetScene to return a single Scene struct.
 func (sl *Sqlite) GetScene(sceneID int) (models.Scene, error) {
-	sqlStmt := fmt.Sprintf("SELECT name, allowed_devices FROM scenes where scene_id = '%v'", sceneID)
+	sqlStmt := "SELECT name, allowed_devices, allowed_zones FROM scenes where scene_id = ?"
 
-	var name, allowedDevicesString string
+	var name, allowedDevicesString, allowedZones string
 
-	err := sl.db.QueryRow(sqlStmt).Scan(&name, &allowedDevicesString)
+	err := sl.db.QueryRow(sqlStmt, sceneID).Scan(&name, &allowedDevicesString, &allowedZones)
 	if err != nil {
 		log.Errorf("%q: %s", err, sqlStmt)
 
@@ -474,13 +516,14 @@ func (sl *Sqlite) GetScene(sceneID int) (models.Scene, error) {
 			return models.Scene{}, err
 		}
 
-		allowedDevices = append(allowedDevices, sl.GetDevice(tID))
+		allowedDevices = sl.appendDevice(allowedDevices, tID)
 	}
 
 	scene := models.Scene{
 		ID:             sceneID,
 		Name:           name,
 		AllowedDevices: allowedDevices,
+		AllowedZones:   strings.Fields(allowedZones),
 	}
 
 	return scene, err
@@ -499,9 +542,9 @@ func (sl *Sqlite) AddScene(s models.Scene) (int, error) {
 		}
 	}
 
-	sqlStmt := fmt.Sprintf("INSERT INTO scenes(name, allowed_devices) values('%s', '%s')", s.Name, allowedDevices)
+	sqlStmt := "INSERT INTO scenes(name, allowed_devices, allowed_zones) values(?, ?, ?)"
 
-	res, err := sl.db.Exec(sqlStmt)
+	res, err := sl.db.Exec(sqlStmt, s.Name, allowedDevices, strings.Join(s.AllowedZones, " "))
 	if err != nil {
 		log.Errorf("%q: %s", err, sqlStmt)
 
@@ -529,12 +572,9 @@ func (sl *Sqlite) SetScene(s models.Scene) error {
 		}
 	}
 
-	sqlStmt := fmt.Sprintf(
-		"UPDATE scenes set name='%s', allowed_devices='%s' where scene_id='%v'",
-		s.Name, allowedDevices, s.ID,
-	)
+	sqlStmt := "UPDATE scenes set name=?, allowed_devices=?, allowed_zones=? where scene_id=?"
 
-	_, err := sl.db.Exec(sqlStmt)
+	_, err := sl.db.Exec(sqlStmt, s.Name, allowedDevices, strings.Join(s.AllowedZones, " "), s.ID)
 	if err != nil {
 		log.Errorf("%q: %s", err, sqlStmt)
 	}
@@ -563,9 +603,9 @@ func (sl *Sqlite) DeleteScene(sceneID int) error {
 		}
 	}
 
-	sqlStmt := fmt.Sprintf("DELETE from scenes where scene_id=%v", sceneID)
+	sqlStmt := "DELETE from scenes where scene_id=?"
 
-	_, err = sl.db.Exec(sqlStmt)
+	_, err = sl.db.Exec(sqlStmt, sceneID)
 	if err != nil {
 		log.Errorf("%q: %s", err, sqlStmt)
 	}
@@ -578,7 +618,8 @@ func (sl *Sqlite) GetGroups(sceneID int) ([]models.Group, error) {
 	s := []models.Group{}
 
 	rows, err := sl.db.Query(
-		"SELECT group_id, delay, global_delay, `order` FROM scenes_group where scene_id=? ORDER BY `order`",
+		"SELECT group_id, delay, global_delay, fade_duration, fade_rate, fade_easing, `order` "+
+			"FROM scenes_group where scene_id=? ORDER BY `order`",
 		sceneID,
 	)
 	if err != nil {
@@ -599,23 +640,28 @@ func (sl *Sqlite) GetGroups(sceneID int) ([]models.Group, error) {
 	}()
 
 	for rows.Next() {
-		var groupID, order int
+		var groupID, fadeRate, order int
 
-		var delay float32
+		var delay, fadeDuration float32
 
 		var globalDelay bool
 
-		err = rows.Scan(&groupID, &delay, &globalDelay, &order)
+		var fadeEasing string
+
+		err = rows.Scan(&groupID, &delay, &globalDelay, &fadeDuration, &fadeRate, &fadeEasing, &order)
 		if err != nil {
 			log.Error(err)
 		}
 
 		sr := models.Group{
-			ID:          groupID,
-			SceneID:     sceneID,
-			Delay:       delay,
-			GlobalDelay: globalDelay,
-			Order:       order,
+			ID:           groupID,
+			SceneID:      sceneID,
+			Delay:        delay,
+			GlobalDelay:  globalDelay,
+			FadeDuration: fadeDuration,
+			FadeRate:     fadeRate,
+			FadeEasing:   fadeEasing,
+			Order:        order,
 		}
 		s = append(s, sr)
 	}
@@ -625,18 +671,19 @@ func (sl *Sqlite) GetGroups(sceneID int) ([]models.Group, error) {
 
 // GetGroup to return a single Group struct.
 func (sl *Sqlite) GetGroup(groupID int) (models.Group, error) {
-	sqlStmt := fmt.Sprintf(
-		"SELECT scene_id, delay, global_delay, `order` FROM scenes_group where group_id = '%v'",
-		groupID,
-	)
+	sqlStmt := "SELECT scene_id, delay, global_delay, fade_duration, fade_rate, fade_easing, `order` " +
+		"FROM scenes_group where group_id = ?"
 
-	var sceneID, order int
+	var sceneID, fadeRate, order int
 
-	var delay float32
+	var delay, fadeDuration float32
 
 	var globalDelay bool
 
-	err := sl.db.QueryRow(sqlStmt).Scan(&sceneID, &delay, &globalDelay, &order)
+	var fadeEasing string
+
+	err := sl.db.QueryRow(sqlStmt, groupID).Scan(&sceneID, &delay, &globalDelay, &fadeDuration, &fadeRate,
+		&fadeEasing, &order)
 	if err != nil {
 		log.Errorf("%q: %s", err, sqlStmt)
 
@@ -644,11 +691,14 @@ func (sl *Sqlite) GetGroup(groupID int) (models.Group, error) {
 	}
 
 	group := models.Group{
-		ID:          groupID,
-		SceneID:     sceneID,
-		Delay:       delay,
-		GlobalDelay: globalDelay,
-		Order:       order,
+		ID:           groupID,
+		SceneID:      sceneID,
+		Delay:        delay,
+		GlobalDelay:  globalDelay,
+		FadeDuration: fadeDuration,
+		FadeRate:     fadeRate,
+		FadeEasing:   fadeEasing,
+		Order:        order,
 	}
 
 	return group, err
@@ -672,13 +722,10 @@ func (sl *Sqlite) GetGroupOrderNext(sceneID int) (int, error) {
 
 // AddGroup to add a group.
 func (sl *Sqlite) AddGroup(g models.Group) (int, error) {
-	sqlStmt := fmt.Sprintf(
-		"INSERT INTO scenes_group(scene_id, delay, global_delay, 'order') "+
-			"values('%v', '%v', '%v', '%v')",
-		g.SceneID, g.Delay, g.GlobalDelay, g.Order,
-	)
+	sqlStmt := "INSERT INTO scenes_group(scene_id, delay, global_delay, fade_duration, fade_rate, fade_easing, " +
+		"`order`) values(?, ?, ?, ?, ?, ?, ?)"
 
-	res, err := sl.db.Exec(sqlStmt)
+	res, err := sl.db.Exec(sqlStmt, g.SceneID, g.Delay, g.GlobalDelay, g.FadeDuration, g.FadeRate, g.FadeEasing, g.Order)
 	if err != nil {
 		log.Errorf("%q: %s", err, sqlStmt)
 
@@ -697,12 +744,10 @@ func (sl *Sqlite) AddGroup(g models.Group) (int, error) {
 
 // SetGroup to update a Group.
 func (sl *Sqlite) SetGroup(g models.Group) error {
-	sqlStmt := fmt.Sprintf(
-		"UPDATE scenes_group set delay='%v', global_delay='%v', `order`='%v' where group_id='%v'",
-		g.Delay, g.GlobalDelay, g.Order, g.ID,
-	)
+	sqlStmt := "UPDATE scenes_group set delay=?, global_delay=?, fade_duration=?, fade_rate=?, fade_easing=?, " +
+		"`order`=? where group_id=?"
 
-	_, err := sl.db.Exec(sqlStmt)
+	_, err := sl.db.Exec(sqlStmt, g.Delay, g.GlobalDelay, g.FadeDuration, g.FadeRate, g.FadeEasing, g.Order, g.ID)
 	if err != nil {
 		log.Errorf("%q: %s", err, sqlStmt)
 	}
@@ -765,10 +810,10 @@ func (sl *Sqlite) SortGroup(sceneID int, groupID int, sortID int) error {
 		i++
 	}
 
-	for _, gnu := range groupsNeedingUpdate {
-		sqlStmt := fmt.Sprintf("UPDATE scenes_group set `order`='%v' where group_id='%v'", gnu.Order, gnu.GroupID)
+	sqlStmt := "UPDATE scenes_group set `order`=? where group_id=?"
 
-		_, err := sl.db.Exec(sqlStmt)
+	for _, gnu := range groupsNeedingUpdate {
+		_, err := sl.db.Exec(sqlStmt, gnu.Order, gnu.GroupID)
 		if err != nil {
 			log.Errorf("%q: %s", err, sqlStmt)
 
@@ -776,9 +821,7 @@ func (sl *Sqlite) SortGroup(sceneID int, groupID int, sortID int) error {
 		}
 	}
 
-	sqlStmt := fmt.Sprintf("UPDATE scenes_group set `order`='%v' where group_id='%v'", sortID, groupID)
-
-	_, err = sl.db.Exec(sqlStmt)
+	_, err = sl.db.Exec(sqlStmt, sortID, groupID)
 	if err != nil {
 		log.Errorf("%q: %s", err, sqlStmt)
 	}
@@ -788,9 +831,9 @@ func (sl *Sqlite) SortGroup(sceneID int, groupID int, sortID int) error {
 
 // DeleteGroup function.
 func (sl *Sqlite) DeleteGroup(groupID int) error {
-	sqlStmt := fmt.Sprintf("DELETE from scenes_group where group_id=%v", groupID)
+	sqlStmt := "DELETE from scenes_group where group_id=?"
 
-	_, err := sl.db.Exec(sqlStmt)
+	_, err := sl.db.Exec(sqlStmt, groupID)
 	if err != nil {
 		log.Errorf("%q: %s", err, sqlStmt)
 	}
@@ -803,8 +846,8 @@ func (sl *Sqlite) GetActions(groupID int) ([]models.Action, error) {
 	s := []models.Action{}
 
 	rows, err := sl.db.Query(
-		"SELECT action_id, devices, command, parameter, global_parameter, `order` "+
-			"FROM scenes_action where group_id=? ORDER BY `order`",
+		"SELECT action_id, devices, zones, type, command, parameter, global_parameter, color, ct, "+
+			"brightness, transition, palette_color, `order` FROM scenes_action where group_id=? ORDER BY `order`",
 		groupID,
 	)
 	if err != nil {
@@ -825,11 +868,14 @@ func (sl *Sqlite) GetActions(groupID int) ([]models.Action, error) {
 	}()
 
 	for rows.Next() {
-		var actionID, order int
+		var actionID, ct, brightness, order int
 
-		var devicesString, command, parameter, globalParameter string
+		var devicesString, zones, actionType, command, parameter, globalParameter, color, paletteColor string
 
-		err = rows.Scan(&actionID, &devicesString, &command, &parameter, &globalParameter, &order)
+		var transition float64
+
+		err = rows.Scan(&actionID, &devicesString, &zones, &actionType, &command, &parameter, &globalParameter,
+			&color, &ct, &brightness, &transition, &paletteColor, &order)
 		if err != nil {
 			log.Error(err)
 
@@ -847,16 +893,23 @@ func (sl *Sqlite) GetActions(groupID int) ([]models.Action, error) {
 				continue
 			}
 
-			devices = append(devices, sl.GetDevice(tID))
+			devices = sl.appendDevice(devices, tID)
 		}
 
 		sa := models.Action{
 			ID:              actionID,
 			GroupID:         groupID,
 			Devices:         devices,
+			Zones:           strings.Fields(zones),
+			Type:            actionType,
 			Command:         command,
 			Parameter:       parameter,
 			GlobalParameter: globalParameter,
+			Color:           color,
+			CT:              ct,
+			Brightness:      brightness,
+			Transition:      transition,
+			PaletteColor:    paletteColor,
 			Order:           order,
 		}
 		s = append(s, sa)
@@ -867,17 +920,17 @@ func (sl *Sqlite) GetActions(groupID int) ([]models.Action, error) {
 
 // GetAction to return a single Action struct.
 func (sl *Sqlite) GetAction(actionID int) (models.Action, error) {
-	sqlStmt := fmt.Sprintf(
-		"SELECT group_id, devices, command, parameter, global_parameter, `order` "+
-			"FROM scenes_action where action_id = '%v'",
-		actionID,
-	)
+	sqlStmt := "SELECT group_id, devices, zones, type, command, parameter, global_parameter, color, ct, " +
+		"brightness, transition, palette_color, `order` FROM scenes_action where action_id = ?"
+
+	var groupID, ct, brightness, order int
 
-	var groupID, order int
+	var devicesString, zones, actionType, command, parameter, globalParameter, color, paletteColor string
 
-	var devicesString, command, parameter, globalParameter string
+	var transition float64
 
-	err := sl.db.QueryRow(sqlStmt).Scan(&groupID, &devicesString, &command, &parameter, &globalParameter, &order)
+	err := sl.db.QueryRow(sqlStmt, actionID).Scan(&groupID, &devicesString, &zones, &actionType, &command, &parameter,
+		&globalParameter, &color, &ct, &brightness, &transition, &paletteColor, &order)
 	if err != nil {
 		log.Errorf("%q: %s", err, sqlStmt)
 
@@ -895,16 +948,23 @@ func (sl *Sqlite) GetAction(actionID int) (models.Action, error) {
 			return models.Action{}, err
 		}
 
-		devices = append(devices, sl.GetDevice(tID))
+		devices = sl.appendDevice(devices, tID)
 	}
 
 	action := models.Action{
 		ID:              actionID,
 		GroupID:         groupID,
 		Devices:         devices,
+		Zones:           strings.Fields(zones),
+		Type:            actionType,
 		Command:         command,
 		Parameter:       parameter,
 		GlobalParameter: globalParameter,
+		Color:           color,
+		CT:              ct,
+		Brightness:      brightness,
+		Transition:      transition,
+		PaletteColor:    paletteColor,
 		Order:           order,
 	}
 
@@ -940,13 +1000,12 @@ func (sl *Sqlite) AddAction(a models.Action) (int, error) {
 		}
 	}
 
-	sqlStmt := fmt.Sprintf(
-		"INSERT INTO scenes_action(group_id, devices, command, parameter, global_parameter, 'order') "+
-			"values('%v', '%s', '%s', '%s', '%s', '%v')",
-		a.GroupID, devices, a.Command, a.Parameter, a.GlobalParameter, a.Order,
-	)
+	sqlStmt := "INSERT INTO scenes_action(group_id, devices, zones, type, command, parameter, " +
+		"global_parameter, color, ct, brightness, transition, palette_color, `order`) " +
+		"values(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
 
-	res, err := sl.db.Exec(sqlStmt)
+	res, err := sl.db.Exec(sqlStmt, a.GroupID, devices, strings.Join(a.Zones, " "), a.Type, a.Command, a.Parameter, a.GlobalParameter,
+		a.Color, a.CT, a.Brightness, a.Transition, a.PaletteColor, a.Order)
 	if err != nil {
 		log.Errorf("%q: %s", err, sqlStmt)
 
@@ -976,13 +1035,11 @@ func (sl *Sqlite) SetAction(a models.Action) error {
 		}
 	}
 
-	sqlStmt := fmt.Sprintf(
-		"UPDATE scenes_action set devices='%s', command='%s', parameter='%s', "+
-			"global_parameter='%s', `order`='%v' where action_id='%v'",
-		devices, a.Command, a.Parameter, a.GlobalParameter, a.Order, a.ID,
-	)
+	sqlStmt := "UPDATE scenes_action set devices=?, zones=?, type=?, command=?, parameter=?, global_parameter=?, " +
+		"color=?, ct=?, brightness=?, transition=?, palette_color=?, `order`=? where action_id=?"
 
-	_, err := sl.db.Exec(sqlStmt)
+	_, err := sl.db.Exec(sqlStmt, devices, strings.Join(a.Zones, " "), a.Type, a.Command, a.Parameter, a.GlobalParameter,
+		a.Color, a.CT, a.Brightness, a.Transition, a.PaletteColor, a.Order, a.ID)
 	if err != nil {
 		log.Errorf("%q: %s", err, sqlStmt)
 	}
@@ -1044,10 +1101,10 @@ func (sl *Sqlite) SortAction(groupID int, actionID int, sortID int) error {
 		i++
 	}
 
-	for _, anu := range actionsNeedingUpdate {
-		sqlStmt := fmt.Sprintf("UPDATE scenes_action set `order`='%v' where action_id='%v'", anu.Order, anu.ActionID)
+	sqlStmt := "UPDATE scenes_action set `order`=? where action_id=?"
 
-		_, err := sl.db.Exec(sqlStmt)
+	for _, anu := range actionsNeedingUpdate {
+		_, err := sl.db.Exec(sqlStmt, anu.Order, anu.ActionID)
 		if err != nil {
 			log.Errorf("%q: %s", err, sqlStmt)
 
@@ -1055,9 +1112,7 @@ func (sl *Sqlite) SortAction(groupID int, actionID int, sortID int) error {
 		}
 	}
 
-	sqlStmt := fmt.Sprintf("UPDATE scenes_action set `order`='%v' where action_id='%v'", sortID, actionID)
-
-	_, err = sl.db.Exec(sqlStmt)
+	_, err = sl.db.Exec(sqlStmt, sortID, actionID)
 	if err != nil {
 		log.Errorf("%q: %s", err, sqlStmt)
 	}
@@ -1067,9 +1122,9 @@ func (sl *Sqlite) SortAction(groupID int, actionID int, sortID int) error {
 
 // DeleteAction function.
 func (sl *Sqlite) DeleteAction(actionID int) error {
-	sqlStmt := fmt.Sprintf("DELETE from scenes_action where action_id=%v", actionID)
+	sqlStmt := "DELETE from scenes_action where action_id=?"
 
-	_, err := sl.db.Exec(sqlStmt)
+	_, err := sl.db.Exec(sqlStmt, actionID)
 	if err != nil {
 		log.Errorf("%q: %s", err, sqlStmt)
 	}
@@ -1078,66 +1133,105 @@ func (sl *Sqlite) DeleteAction(actionID int) error {
 }
 
 // AddDevice to add a new device.
-func (sl *Sqlite) AddDevice(d models.Device) (insertID int) {
-	sqlStmt := fmt.Sprintf("INSERT INTO devices(name, topic, type) values('%s', '%s', %v)", d.Name, d.Topic, d.Type.ID)
+func (sl *Sqlite) AddDevice(d models.Device) (int, error) {
+	sqlStmt := "INSERT INTO devices(name, topic, type, full_topic, hostname, " +
+		"supports_dimmer, supports_color, supports_ct, zones) values(?, ?, ?, ?, ?, ?, ?, ?, ?)"
 
-	res, err := sl.db.Exec(sqlStmt)
+	res, err := sl.db.Exec(sqlStmt, d.Name, d.Topic, d.Type.ID, d.FullTopic, d.Hostname,
+		d.SupportsDimmer, d.SupportsColor, d.SupportsCT, strings.Join(d.Zones, " "))
 	if err != nil {
 		log.Errorf("%q: %s", err, sqlStmt)
+
+		return 0, err
 	}
 
-	id, _ := res.LastInsertId()
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
 
-	return int(id)
+	return int(id), err
 }
 
 // SetDevice to update a device.
-func (sl *Sqlite) SetDevice(d models.Device) {
-	sqlStmt := fmt.Sprintf(
-		"UPDATE devices set name='%s', topic='%s', type='%v' where device_id='%v'",
-		d.Name, d.Topic, d.Type.ID, d.ID,
-	)
+func (sl *Sqlite) SetDevice(d models.Device) error {
+	sqlStmt := "UPDATE devices set name=?, topic=?, type=?, full_topic=?, hostname=?, " +
+		"supports_dimmer=?, supports_color=?, supports_ct=?, zones=? where device_id=?"
 
-	if _, err := sl.db.Exec(sqlStmt); err != nil {
+	_, err := sl.db.Exec(sqlStmt, d.Name, d.Topic, d.Type.ID, d.FullTopic, d.Hostname,
+		d.SupportsDimmer, d.SupportsColor, d.SupportsCT, strings.Join(d.Zones, " "), d.ID)
+	if err != nil {
 		log.Errorf("%q: %s", err, sqlStmt)
 	}
+
+	return err
 }
 
 // DeleteDevice function.
-func (sl *Sqlite) DeleteDevice(deviceID int) {
-	sqlStmt := fmt.Sprintf("DELETE from devices where device_id=%v", deviceID)
+func (sl *Sqlite) DeleteDevice(deviceID int) error {
+	sqlStmt := "DELETE from devices where device_id=?"
 
-	if _, err := sl.db.Exec(sqlStmt); err != nil {
+	_, err := sl.db.Exec(sqlStmt, deviceID)
+	if err != nil {
 		log.Errorf("%q: %s", err, sqlStmt)
 	}
+
+	return err
 }
 
 // GetDevice to return a single Device struct.
-func (sl *Sqlite) GetDevice(deviceID int) models.Device {
-	d := models.Device{}
-	sqlStmt := fmt.Sprintf("SELECT name, topic, type FROM devices where device_id = '%v'", deviceID)
+func (sl *Sqlite) GetDevice(deviceID int) (models.Device, error) {
+	sqlStmt := deviceSelect + " where device_id = ?"
 
-	var name, topic string
+	d, err := sl.scanDevice(sl.db.QueryRow(sqlStmt, deviceID))
+	if err != nil {
+		log.Errorf("%q: %s", err, sqlStmt)
 
-	var typeID int
+		return models.Device{}, err
+	}
 
-	err := sl.db.QueryRow(sqlStmt).Scan(&name, &topic, &typeID)
+	return d, err
+}
+
+// appendDevice appends the device with the given ID, devices that no longer exist are skipped.
+func (sl *Sqlite) appendDevice(devices []models.Device, deviceID int) []models.Device {
+	d, err := sl.GetDevice(deviceID)
 	if err != nil {
-		log.Errorf("%q: %s", err, sqlStmt)
+		return devices
+	}
 
-		return d
+	return append(devices, d)
+}
+
+const deviceSelect = "SELECT device_id, name, topic, type, full_topic, hostname, " +
+	"supports_dimmer, supports_color, supports_ct, zones FROM devices"
+
+// scanner is satisfied by both *sql.Row and *sql.Rows.
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanDevice to read a Device selected with deviceSelect.
+func (sl *Sqlite) scanDevice(row scanner) (models.Device, error) {
+	d := models.Device{}
+
+	var typeID int
+
+	var zones string
+
+	err := row.Scan(&d.ID, &d.Name, &d.Topic, &typeID, &d.FullTopic, &d.Hostname,
+		&d.SupportsDimmer, &d.SupportsColor, &d.SupportsCT, &zones)
+	if err != nil {
+		return d, err
 	}
 
-	d.ID = deviceID
-	d.Name = name
-	d.Topic = topic
+	d.Zones = strings.Fields(zones)
+
 	d.Type = sl.GetDeviceType(typeID)
 
-	return d
+	return d, nil
 }
 
-const deviceSelect = "SELECT device_id, name, topic, type FROM devices"
-
 // GetDevices to return a slice of Device structs.
 func (sl *Sqlite) GetDevices() []models.Device {
 	d := []models.Device{}
@@ -1163,18 +1257,14 @@ func (sl *Sqlite) GetDevices() []models.Device {
 	}()
 
 	for rows.Next() {
-		var deviceID, typeID int
-
-		var name, topic string
-
-		err = rows.Scan(&deviceID, &name, &topic, &typeID)
+		device, err := sl.scanDevice(rows)
 		if err != nil {
 			log.Errorf("%q: %s", err, sqlStmt)
 
 			return d
 		}
 
-		d = append(d, models.Device{ID: deviceID, Name: name, Topic: topic, Type: sl.GetDeviceType(typeID)})
+		d = append(d, device)
 	}
 
 	return d
@@ -1212,30 +1302,22 @@ func (sl *Sqlite) GetDevicesWithActionSelected(actionID int) []models.Device {
 	}()
 
 	for rows.Next() {
-		var deviceID, typeID int
+		var device models.Device
 
-		var name, topic string
-
-		err = rows.Scan(&deviceID, &name, &topic, &typeID)
+		device, err = sl.scanDevice(rows)
 		if err != nil {
 			log.Errorf("%q: %s", err, sqlStmt)
 
 			return d
 		}
 
-		var selected bool
-		selected = false
-
 		for _, dev := range a.Devices {
-			if dev.ID == deviceID {
-				selected = true
+			if dev.ID == device.ID {
+				device.Selected = true
 			}
 		}
 
-		d = append(
-			d,
-			models.Device{ID: deviceID, Name: name, Topic: topic, Type: sl.GetDeviceType(typeID), Selected: selected},
-		)
+		d = append(d, device)
 	}
 
 	return d
@@ -1295,30 +1377,22 @@ func (sl *Sqlite) GetDevicesWithSceneSelected(sceneID int) ([]models.Device, err
 	}()
 
 	for rows.Next() {
-		var deviceID, typeID int
-
-		var name, topic string
+		var device models.Device
 
-		err = rows.Scan(&deviceID, &name, &topic, &typeID)
+		device, err = sl.scanDevice(rows)
 		if err != nil {
 			log.Errorf("%q: %s", err, sqlStmt)
 
 			return d, err
 		}
 
-		var selected bool
-		selected = false
-
 		for _, dev := range s.AllowedDevices {
-			if dev.ID == deviceID {
-				selected = true
+			if dev.ID == device.ID {
+				device.Selected = true
 			}
 		}
 
-		d = append(
-			d,
-			models.Device{ID: deviceID, Name: name, Topic: topic, Type: sl.GetDeviceType(typeID), Selected: selected},
-		)
+		d = append(d, device)
 	}
 
 	return d, err
@@ -1326,128 +1400,76 @@ func (sl *Sqlite) GetDevicesWithSceneSelected(sceneID int) ([]models.Device, err
 
 // GetDeviceTypes to return a slice of DeviceType structs.
 func (sl *Sqlite) GetDeviceTypes() []models.DeviceType {
-	return sl.deviceTypes
+	return sl.deviceTypes.GetDeviceTypes()
 }
 
-// GetDeviceType to return a single DeviceType struct.
+// GetDeviceType to return a single DeviceType struct, the zero DeviceType if it does not exist.
 func (sl *Sqlite) GetDeviceType(deviceID int) models.DeviceType {
-	for _, d := range sl.deviceTypes {
-		if d.ID == deviceID {
-			return models.DeviceType{
-				ID:       d.ID,
-				Name:     d.Name,
-				Commands: d.Commands,
-			}
-		}
-	}
+	dt, _ := sl.deviceTypes.GetDeviceType(deviceID)
 
-	return models.DeviceType{}
+	return dt
 }
 
 // Connect creates database connection.
-func (sl *Sqlite) connect() {
-	dbf := "data/sqlite.db"
-
+func (sl *Sqlite) connect(dbf string) error {
 	var err error
 
-	sl.db, err = sql.Open("sqlite3", dbf)
+	sl.conn, err = sql.Open("sqlite3", dbf)
 	if err != nil {
-		log.Error(err)
+		return err
 	}
 
+	sl.db = sl.conn
+
 	log.Infof("sqlite connected to %s", dbf)
+
+	return nil
 }
 
 // Disconnect database connection.
 func (sl *Sqlite) Disconnect() {
 	log.Info("disconnecting")
 
-	if err := sl.db.Close(); err != nil {
+	if err := sl.conn.Close(); err != nil {
 		log.Error(err)
 	}
 }
 
-func (sl *Sqlite) bootstrap() {
-	// initialize database if needed.
-	sqlStmt := "SELECT name FROM sqlite_master WHERE type='table' AND name='devices';"
-	row := sl.db.QueryRow(sqlStmt)
-
-	var name string
-
-	err := row.Scan(&name)
-	if err != nil {
-		if !errors.Is(err, sql.ErrNoRows) {
-			log.Infof("%v table does not exist, initializing database", name)
-		}
-	} else {
-		log.Infof("%v table already exists, skipping database init", name)
-
-		return
-	}
-
-	// create required tables.
-	sqlStmt = "CREATE TABLE devices (device_id INTEGER PRIMARY KEY, name TEXT, topic TEXT, type INTEGER);"
-
-	_, err = sl.db.Exec(sqlStmt)
-	if err != nil {
-		log.Errorf("%q: %s", err, sqlStmt)
-
-		return
-	}
-
-	sqlStmt = "CREATE TABLE shows (show_id INTEGER PRIMARY KEY, name TEXT, topic TEXT, repeat TEXT, " +
-		"global_delay INTEGER, global_speed INTEGER, global_parameter1 TEXT, global_parameter2 TEXT);"
-
-	_, err = sl.db.Exec(sqlStmt)
+// Transaction runs fn with a Sqlite that runs all its queries in one transaction. The
+// transaction is committed when fn returns nil and rolled back when it returns an error.
+func (sl *Sqlite) Transaction(fn func(tx *Sqlite) error) error {
+	tx, err := sl.conn.Begin()
 	if err != nil {
-		log.Errorf("%q: %s", err, sqlStmt)
-
-		return
-	}
-
-	sqlStmt = "CREATE TABLE shows_cycles (cycle_id INTEGER PRIMARY KEY, show_id INTEGER, scene_id INTEGER, " +
-		"cycles INTEGER, end_delay INTEGER, loop_include TEXT, global_delay INTEGER, global_speed INTEGER, " +
-		"global_parameter1 TEXT, global_parameter2 TEXT, 'order' INTEGER);"
-
-	_, err = sl.db.Exec(sqlStmt)
-	if err != nil {
-		log.Errorf("%q: %s", err, sqlStmt)
-
-		return
+		return err
 	}
 
-	sqlStmt = "CREATE TABLE scenes (scene_id INTEGER PRIMARY KEY, name TEXT, allowed_devices TEXT);"
-
-	_, err = sl.db.Exec(sqlStmt)
+	err = fn(&Sqlite{db: tx, conn: sl.conn, deviceTypes: sl.deviceTypes})
 	if err != nil {
-		log.Errorf("%q: %s", err, sqlStmt)
+		if rbErr := tx.Rollback(); rbErr != nil {
+			log.Error(rbErr)
+		}
 
-		return
+		return err
 	}
 
-	sqlStmt = "CREATE TABLE scenes_group (group_id INTEGER PRIMARY KEY, scene_id INTEGER, " +
-		"delay INTEGER, global_delay INTEGER, 'order' INTEGER);"
+	return tx.Commit()
+}
 
-	_, err = sl.db.Exec(sqlStmt)
-	if err != nil {
-		log.Errorf("%q: %s", err, sqlStmt)
+// InitializeClient is a replacement for init() because we need to set log variable first.
+func (sl *Sqlite) InitializeClient() error {
+	return sl.Open("data/sqlite.db")
+}
 
-		return
+// Open connects to the database file dbf and migrates it to the current schema. The add-on
+// must not run on a database that could not be migrated, so the error is returned.
+func (sl *Sqlite) Open(dbf string) error {
+	if err := sl.connect(dbf); err != nil {
+		return err
 	}
 
-	sqlStmt = "CREATE TABLE scenes_action (action_id INTEGER PRIMARY KEY, group_id INTEGER, devices TEXT, " +
-		"command TEXT, parameter TEXT, global_parameter TEXT, 'order' INTEGER);"
-
-	_, err = sl.db.Exec(sqlStmt)
-	if err != nil {
-		log.Errorf("%q: %s", err, sqlStmt)
-
-		return
+	if err := sl.migrate(); err != nil {
+		return fmt.Errorf("database migration failed: %w", err)
 	}
-}
 
-// InitializeClient is a replacement for init() because we need to set log variable first.
-func (sl *Sqlite) InitializeClient() {
-	sl.connect()
-	sl.bootstrap()
+	return nil
 }
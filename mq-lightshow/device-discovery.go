@@ -0,0 +1,199 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lovesway/hassio-addons/mq-lightshow/devicetypes"
+	"github.com/lovesway/hassio-addons/mq-lightshow/models"
+)
+
+const (
+	tasmotaDiscoveryTopic = "tasmota/discovery/+/config"
+	tasmotaLWTOnline      = "Online"
+)
+
+var errDeviceNotDiscovered = errors.New("device has not been discovered")
+
+// Tasmota light subtypes (lt_st) as announced in the discovery config.
+const (
+	lightSubtypeDimmer = 1
+	lightSubtypeCT     = 2
+	lightSubtypeRGB    = 3
+	lightSubtypeRGBW   = 4
+	lightSubtypeRGBCW  = 5
+)
+
+// tasmotaDiscovery is the part of the tasmota/discovery/<mac>/config payload we use.
+type tasmotaDiscovery struct {
+	IP            string    `json:"ip"`
+	DeviceName    string    `json:"dn"`
+	FriendlyNames []*string `json:"fn"`
+	Hostname      string    `json:"hn"`
+	MAC           string    `json:"mac"`
+	Module        string    `json:"md"`
+	Topic         string    `json:"t"`
+	FullTopic     string    `json:"ft"`
+	LightSubtype  int       `json:"lt_st"`
+	Version       string    `json:"sw"`
+}
+
+// lwtTopic returns the subscription for the LWT of all devices using the global FullTopic.
+func (mqc *MQController) lwtTopic() string {
+	return devicetypes.FullTopic(mqc.fullTopic, devicetypes.PrefixTele, "+", "+") + "/LWT"
+}
+
+// SubscribeDeviceAnnouncements to learn about devices from their discovery config and LWT.
+func (mqc *MQController) SubscribeDeviceAnnouncements() {
+	mqc.Subscribe(tasmotaDiscoveryTopic)
+	mqc.Subscribe(mqc.lwtTopic())
+}
+
+// handleDeviceAnnouncement records devices announcing themselves. It returns false if the
+// message is not a device announcement.
+func (mqc *MQController) handleDeviceAnnouncement(topic string, payload []byte) bool {
+	if strings.HasPrefix(topic, "tasmota/discovery/") && strings.HasSuffix(topic, "/config") {
+		mqc.handleTasmotaDiscovery(topic, payload)
+
+		return true
+	}
+
+	if !strings.HasSuffix(topic, "/LWT") {
+		return false
+	}
+
+	deviceTopic, ok := devicetypes.MatchTopic(mqc.fullTopic, devicetypes.PrefixTele, topic)
+	if !ok {
+		return false
+	}
+
+	mqc.discoveredMu.Lock()
+	defer mqc.discoveredMu.Unlock()
+
+	d, ok := mqc.discovered[deviceTopic]
+	if !ok {
+		d = models.DiscoveredDevice{Name: deviceTopic, Topic: deviceTopic}
+	}
+
+	d.Online = string(payload) == tasmotaLWTOnline
+	d.LastSeen = time.Now()
+	mqc.discovered[deviceTopic] = d
+
+	return true
+}
+
+func (mqc *MQController) handleTasmotaDiscovery(topic string, payload []byte) {
+	// an empty retained config means the device was removed.
+	if len(payload) == 0 {
+		return
+	}
+
+	var td tasmotaDiscovery
+
+	err := json.Unmarshal(payload, &td)
+	if err != nil {
+		log.Errorf("mqtt error: cannot parse discovery config on %v: %v", topic, err)
+
+		return
+	}
+
+	if td.Topic == "" {
+		return
+	}
+
+	d := models.DiscoveredDevice{
+		MAC:       td.MAC,
+		Name:      td.DeviceName,
+		Topic:     td.Topic,
+		FullTopic: td.FullTopic,
+		Hostname:  td.Hostname,
+		IP:        td.IP,
+		Module:    td.Module,
+		Version:   td.Version,
+		LastSeen:  time.Now(),
+	}
+
+	if len(td.FriendlyNames) > 0 && td.FriendlyNames[0] != nil && *td.FriendlyNames[0] != "" {
+		d.Name = *td.FriendlyNames[0]
+	}
+
+	switch td.LightSubtype {
+	case lightSubtypeDimmer:
+		d.SupportsDimmer = true
+	case lightSubtypeCT:
+		d.SupportsDimmer, d.SupportsCT = true, true
+	case lightSubtypeRGB, lightSubtypeRGBW:
+		d.SupportsDimmer, d.SupportsColor = true, true
+	case lightSubtypeRGBCW:
+		d.SupportsDimmer, d.SupportsColor, d.SupportsCT = true, true, true
+	}
+
+	mqc.discoveredMu.Lock()
+	defer mqc.discoveredMu.Unlock()
+
+	// the LWT may have been seen first.
+	if previous, ok := mqc.discovered[td.Topic]; ok {
+		d.Online = previous.Online
+	}
+
+	mqc.discovered[td.Topic] = d
+}
+
+// GetDiscoveredDevices returns the announced devices that have not been added as a device yet.
+func (mqc *MQController) GetDiscoveredDevices() []models.DiscoveredDevice {
+	known := map[string]bool{}
+	for _, d := range mqc.md.GetDevices() {
+		known[d.Topic] = true
+	}
+
+	mqc.discoveredMu.Lock()
+	defer mqc.discoveredMu.Unlock()
+
+	dds := []models.DiscoveredDevice{}
+
+	for topic, d := range mqc.discovered {
+		if !known[topic] {
+			dds = append(dds, d)
+		}
+	}
+
+	sort.Slice(dds, func(i, j int) bool { return dds[i].Name < dds[j].Name })
+
+	return dds
+}
+
+// GetDiscoveredDevice returns an announced device by its topic.
+func (mqc *MQController) GetDiscoveredDevice(topic string) (models.DiscoveredDevice, error) {
+	mqc.discoveredMu.Lock()
+	defer mqc.discoveredMu.Unlock()
+
+	d, ok := mqc.discovered[topic]
+	if !ok {
+		return d, fmt.Errorf("%w: %v", errDeviceNotDiscovered, topic)
+	}
+
+	return d, nil
+}
+
+// discoveredToDevice fills in a Device from what a device announced. The FullTopic is only kept
+// when it differs from the global one, so that changing the global option still applies.
+func (mqc *MQController) discoveredToDevice(dd models.DiscoveredDevice) models.Device {
+	d := models.Device{
+		Name:           dd.Name,
+		Topic:          dd.Topic,
+		Hostname:       dd.Hostname,
+		SupportsDimmer: dd.SupportsDimmer,
+		SupportsColor:  dd.SupportsColor,
+		SupportsCT:     dd.SupportsCT,
+	}
+
+	if dd.FullTopic != "" && dd.FullTopic != mqc.fullTopic {
+		d.FullTopic = dd.FullTopic
+	}
+
+	return d
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"time"
+
+	"github.com/lovesway/hassio-addons/mq-lightshow/devicetypes"
+	"github.com/lovesway/hassio-addons/mq-lightshow/models"
+)
+
+// deviceHealth is what has been heard from a device since the add-on started.
+type deviceHealth struct {
+	online   bool
+	offline  bool // the device reported itself offline through its LWT.
+	lastSeen time.Time
+	state    devicetypes.DeviceState // the last light state the device reported.
+	stateAt  time.Time
+}
+
+// healthTopic is a topic a device reports its availability or state on.
+type healthTopic struct {
+	deviceID     int
+	availability bool // the payload says whether the device is online, otherwise any message means it is.
+	driver       devicetypes.Driver
+}
+
+// SubscribeDevices subscribes to the availability (the LWT for Tasmota) and state topics of all
+// devices to track whether they are online. Topics of devices that were removed or changed are
+// unsubscribed.
+func (mqc *MQController) SubscribeDevices() {
+	if !mqc.IsConnected() {
+		return
+	}
+
+	topics := map[string]healthTopic{}
+
+	for _, d := range mqc.md.GetDevices() {
+		driver := mqc.dt.Driver(d.Type.ID)
+
+		availability, state := driver.HealthTopics(mqc.withFullTopic(d))
+		if availability != "" {
+			topics[availability] = healthTopic{deviceID: d.ID, availability: true, driver: driver}
+		}
+
+		if state != "" {
+			topics[state] = healthTopic{deviceID: d.ID, driver: driver}
+		}
+
+		// devices that can report their light state answer state queries on a topic of their own.
+		if sd, ok := driver.(devicetypes.StateDriver); ok {
+			_, result := sd.StateQuery(mqc.withFullTopic(d))
+			topics[result] = healthTopic{deviceID: d.ID, driver: driver}
+		}
+	}
+
+	mqc.healthMu.Lock()
+	previous := mqc.healthTopics
+	mqc.healthTopics = topics
+	mqc.healthMu.Unlock()
+
+	for topic := range previous {
+		if _, ok := topics[topic]; !ok {
+			mqc.Unsubscribe(topic)
+		}
+	}
+
+	for topic := range topics {
+		mqc.Subscribe(topic)
+	}
+}
+
+// handleDeviceHealth records availability and state messages of known devices. It returns false if the
+// message is not from a known device.
+func (mqc *MQController) handleDeviceHealth(topic string, payload []byte) bool {
+	mqc.healthMu.Lock()
+	defer mqc.healthMu.Unlock()
+
+	ht, ok := mqc.healthTopics[topic]
+	if !ok {
+		return false
+	}
+
+	h := mqc.health[ht.deviceID]
+	h.lastSeen = time.Now()
+	h.online = !ht.availability || ht.driver.Online(payload)
+	h.offline = !h.online
+
+	if sd, ok := ht.driver.(devicetypes.StateDriver); ok && !ht.availability {
+		if state, ok := sd.State(payload); ok {
+			h.state = state
+			h.stateAt = h.lastSeen
+		}
+	}
+
+	mqc.health[ht.deviceID] = h
+
+	return true
+}
+
+// WithHealth returns devices with Online and LastSeen filled in.
+func (mqc *MQController) WithHealth(devices []models.Device) []models.Device {
+	mqc.healthMu.Lock()
+	defer mqc.healthMu.Unlock()
+
+	ds := make([]models.Device, 0, len(devices))
+
+	for _, d := range devices {
+		h := mqc.health[d.ID]
+		d.Online = h.online
+		d.LastSeen = h.lastSeen
+		ds = append(ds, d)
+	}
+
+	return ds
+}
+
+// IsDeviceOffline reports whether a device said it is offline. Devices that have not reported
+// anything are not considered offline, as not all devices publish their availability.
+func (mqc *MQController) IsDeviceOffline(deviceID int) bool {
+	mqc.healthMu.Lock()
+	defer mqc.healthMu.Unlock()
+
+	return mqc.health[deviceID].offline
+}
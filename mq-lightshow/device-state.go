@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lovesway/hassio-addons/mq-lightshow/devicetypes"
+	"github.com/lovesway/hassio-addons/mq-lightshow/models"
+)
+
+const (
+	// stateWait is how long devices get to answer a state query before a show starts.
+	stateWait = 2 * time.Second
+	// statePoll is how often answers to a state query are checked for.
+	statePoll = 50 * time.Millisecond
+)
+
+// SnapshotLights asks devices for their light state and waits for the answers, for at most
+// stateWait. Devices that do not answer in time keep the last state they reported, and devices
+// that never reported a state are left out.
+func (mqc *MQController) SnapshotLights(ctx context.Context, devices []models.Device) map[int]devicetypes.DeviceState {
+	asked := time.Now()
+	waiting := map[int]bool{}
+
+	for _, d := range devices {
+		sd, ok := mqc.dt.Driver(d.Type.ID).(devicetypes.StateDriver)
+		if !ok || !mqc.IsConnected() {
+			continue
+		}
+
+		query, _ := sd.StateQuery(mqc.withFullTopic(d))
+
+		_token := mqc.mc.Publish(query.Topic, mqc.qos, false, query.Payload)
+		_token.Wait()
+
+		if _token.Error() != nil {
+			log.Errorf("cannot query the state of %v: %v", d.Name, _token.Error())
+
+			continue
+		}
+
+		waiting[d.ID] = true
+	}
+
+	ticker := time.NewTicker(statePoll)
+	defer ticker.Stop()
+
+	timeout := time.NewTimer(stateWait)
+	defer timeout.Stop()
+
+	for len(waiting) > 0 {
+		select {
+		case <-ctx.Done():
+			return mqc.lightStates(devices)
+		case <-timeout.C:
+			return mqc.lightStates(devices)
+		case <-ticker.C:
+		}
+
+		mqc.healthMu.Lock()
+		for id := range waiting {
+			if mqc.health[id].stateAt.After(asked) {
+				delete(waiting, id)
+			}
+		}
+		mqc.healthMu.Unlock()
+	}
+
+	return mqc.lightStates(devices)
+}
+
+// lightStates returns the last light state reported by each of devices that reported one.
+func (mqc *MQController) lightStates(devices []models.Device) map[int]devicetypes.DeviceState {
+	mqc.healthMu.Lock()
+	defer mqc.healthMu.Unlock()
+
+	states := map[int]devicetypes.DeviceState{}
+
+	for _, d := range devices {
+		if h := mqc.health[d.ID]; !h.stateAt.IsZero() {
+			states[d.ID] = h.state
+		}
+	}
+
+	return states
+}
+
+// RestoreLights sets devices back to the states taken with SnapshotLights.
+func (mqc *MQController) RestoreLights(devices []models.Device, states map[int]devicetypes.DeviceState) {
+	for _, d := range devices {
+		state, ok := states[d.ID]
+		if !ok {
+			continue
+		}
+
+		if err := mqc.restoreLight(d, state); err != nil {
+			log.Error(err.Error())
+		}
+	}
+}
+
+func (mqc *MQController) restoreLight(device models.Device, state devicetypes.DeviceState) error {
+	if !mqc.IsConnected() {
+		return fmt.Errorf("%w: cannot restore %v", errMQTTNotConnected, device.Topic)
+	}
+
+	sd, ok := mqc.dt.Driver(device.Type.ID).(devicetypes.StateDriver)
+	if !ok {
+		return nil
+	}
+
+	msgs, err := sd.Restore(mqc.withFullTopic(device), state)
+	if err != nil {
+		return fmt.Errorf("cannot restore %v: %w", device.Name, err)
+	}
+
+	for _, msg := range msgs {
+		_token := mqc.mc.Publish(msg.Topic, mqc.qos, false, msg.Payload)
+		_token.Wait()
+
+		if _token.Error() != nil {
+			return _token.Error()
+		}
+	}
+
+	return nil
+}
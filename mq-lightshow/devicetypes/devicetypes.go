@@ -2,35 +2,167 @@
 package devicetypes
 
 import (
+	"sync"
+
 	"github.com/lovesway/hassio-addons/mq-lightshow/models"
 )
 
-// DeviceTypes represents the controller for the API.
+// Parameter ranges of the built in commands.
+const (
+	maxHue            = 360
+	maxSpeed          = 40
+	maxWakeupDuration = 3000
+)
+
+// IDs of the built in device types, they are stored with each device.
+const (
+	TypeTasmota     = 1
+	TypeWLED        = 2
+	TypeZigbee2MQTT = 3
+	TypeHAJSON      = 4
+)
+
+// DeviceTypes represents the controller for the API. It holds the built in device types and
+// those defined by the user, which can be changed while running.
 type DeviceTypes struct {
+	mu          sync.RWMutex
 	deviceTypes []models.DeviceType
+	drivers     map[int]Driver
+	file        string // where user defined device types are saved, empty if they are not.
 }
 
 // NewDeviceTypes provides an instance of DeviceTypes.
-func NewDeviceTypes() DeviceTypes {
+func NewDeviceTypes() *DeviceTypes {
 	dts := []models.DeviceType{}
 
 	// Manually set up our device types which will seldom change.
 	dt := models.DeviceType{
-		ID:       1,
+		ID:       TypeTasmota,
 		Name:     "Tasmota",
+		BuiltIn:  true,
 		Commands: getTasmotaCommands(),
 	}
+	dts = append(dts, dt)
+
+	dt = models.DeviceType{
+		ID:       TypeWLED,
+		Name:     "WLED",
+		BuiltIn:  true,
+		Commands: getLightCommands(),
+	}
+	dts = append(dts, dt)
+
+	dt = models.DeviceType{
+		ID:       TypeZigbee2MQTT,
+		Name:     "Zigbee2MQTT",
+		BuiltIn:  true,
+		Commands: getLightCommands(),
+	}
+	dts = append(dts, dt)
 
+	dt = models.DeviceType{
+		ID:       TypeHAJSON,
+		Name:     "Home Assistant JSON Light",
+		BuiltIn:  true,
+		Commands: getLightCommands(),
+	}
 	dts = append(dts, dt)
 
-	return DeviceTypes{
+	drivers := map[int]Driver{
+		TypeTasmota: tasmotaDriver{},
+		TypeWLED:    wledDriver{},
+		TypeZigbee2MQTT: jsonLightDriver{
+			name:          "Zigbee2MQTT",
+			setSuffix:     "/set",
+			maxBrightness: maxZigbee,
+			canToggle:     true,
+			availability:  "/availability",
+			stateOnTopic:  true,
+		},
+		TypeHAJSON: jsonLightDriver{
+			name:          "Home Assistant JSON Light",
+			maxBrightness: maxByte,
+		},
+	}
+
+	return &DeviceTypes{
 		deviceTypes: dts,
+		drivers:     drivers,
 	}
 }
 
 // GetDeviceTypes will return our deviceTypes.
-func (d DeviceTypes) GetDeviceTypes() []models.DeviceType {
-	return d.deviceTypes
+func (d *DeviceTypes) GetDeviceTypes() []models.DeviceType {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	return append([]models.DeviceType{}, d.deviceTypes...)
+}
+
+// GetDeviceType returns a device type by its ID.
+func (d *DeviceTypes) GetDeviceType(typeID int) (models.DeviceType, bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	return findDeviceType(d.deviceTypes, typeID)
+}
+
+// findDeviceType returns the device type with the given ID.
+func findDeviceType(deviceTypes []models.DeviceType, typeID int) (models.DeviceType, bool) {
+	for _, dt := range deviceTypes {
+		if dt.ID == typeID {
+			return dt, true
+		}
+	}
+
+	return models.DeviceType{}, false
+}
+
+// Driver returns the driver of a device type. Devices of an unknown type are treated as
+// Tasmota devices, as that was the only type before drivers were added.
+func (d *DeviceTypes) Driver(typeID int) Driver {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if driver, ok := d.drivers[typeID]; ok {
+		return driver
+	}
+
+	return tasmotaDriver{}
+}
+
+// getLightCommands returns the commands all drivers translate for their devices.
+func getLightCommands() []models.Command {
+	return []models.Command{
+		{
+			Name:        CommandPower,
+			Description: "Power - ON, OFF or TOGGLE (TOGGLE is not supported by Home Assistant JSON lights)",
+			Type:        ParameterToggle,
+		},
+		{
+			Name:        CommandColor,
+			Description: "Color1 - Set color, values can be r,g,b or #hex",
+			Type:        ParameterColor,
+		},
+		{
+			Name:        CommandCT,
+			Description: "CT - Set color temperature from 153 (cold) to 500 (warm)",
+			Type:        ParameterInt,
+			Min:         minCT,
+			Max:         maxCT,
+		},
+		{
+			Name:        CommandDimmer,
+			Description: "Dimmer - Set dimmer value from 0 to 100(%)",
+			Type:        ParameterInt,
+			Max:         maxDimmer,
+		},
+		{
+			Name:        CommandHsbColor,
+			Description: "HsbColor - hue,sat,bri = set color by hue (0..360), saturation and brightness (0..100)",
+			Type:        ParameterHSB,
+		},
+	}
 }
 
 func getTasmotaCommands() []models.Command {
@@ -38,66 +170,86 @@ func getTasmotaCommands() []models.Command {
 	c := models.Command{
 		Name:        "Power",
 		Description: "Power - Toggle, On, Off",
+		Type:        ParameterToggle,
 	}
 	commands = append(commands, c)
 
 	c = models.Command{
 		Name:        "Color1",
 		Description: "Color1 - Set color, values can be r,g,b or #hex",
+		Type:        ParameterColor,
 	}
 	commands = append(commands, c)
 
 	c = models.Command{
 		Name:        "Color2",
 		Description: "Color2 - Set color adjusted to current Dimmer value",
+		Type:        ParameterColor,
 	}
 	commands = append(commands, c)
 
 	c = models.Command{
 		Name:        "CT",
 		Description: "CT - Set color temperature from 153 (cold) to 500 (warm) for CT lights",
+		Type:        ParameterInt,
+		Min:         minCT,
+		Max:         maxCT,
 	}
 	commands = append(commands, c)
 
 	c = models.Command{
 		Name:        "Dimmer",
 		Description: "Dimmer - Set dimmer value from 0 to 100(%)",
+		Type:        ParameterInt,
+		Max:         maxDimmer,
 	}
 	commands = append(commands, c)
 
 	c = models.Command{
 		Name:        "Fade",
 		Description: "Fade - 0 = do not use fade (default), 1 = use fade",
+		Type:        ParameterEnum,
+		Values:      []string{"0", "1"},
 	}
 	commands = append(commands, c)
 
 	c = models.Command{
 		Name:        "Speed",
 		Description: "Speed - Set fade speed from fast 1 to very slow 40 (The Speed value represents the time in 0.5s)",
+		Type:        ParameterInt,
+		Min:         1,
+		Max:         maxSpeed,
 	}
 	commands = append(commands, c)
 
 	c = models.Command{
 		Name:        "HsbColor",
 		Description: "HsbColor - hue,sat,bri = set color by hue, saturation and brightness",
+		Type:        ParameterHSB,
 	}
 	commands = append(commands, c)
 
 	c = models.Command{
 		Name:        "HsbColor1",
 		Description: "HsbColor1 - 0..360 = set hue",
+		Type:        ParameterInt,
+		Max:         maxHue,
 	}
 	commands = append(commands, c)
 
 	c = models.Command{
 		Name:        "HsbColor2",
 		Description: "HsbColor2 - 0..100 = set saturation",
+		Type:        ParameterInt,
+		Max:         maxDimmer,
 	}
 	commands = append(commands, c)
 
 	c = models.Command{
 		Name:        "HsbColor3",
 		Description: "HsbColor3 - 0..100 = set brightness",
+		Type:        ParameterInt,
+		Max:         maxDimmer,
 	}
 	commands = append(commands, c)
 
@@ -105,6 +257,8 @@ func getTasmotaCommands() []models.Command {
 		Name: "Scheme",
 		Description: "Scheme - 0 = single color, 1 = start wake up, 2 = cycle up colors, " +
 			"3 = cycle down colors, 4 = random cycle colors",
+		Type:   ParameterEnum,
+		Values: []string{"0", "1", "2", "3", "4"},
 	}
 	commands = append(commands, c)
 
@@ -112,18 +266,26 @@ func getTasmotaCommands() []models.Command {
 		Name: "Wakeup",
 		Description: "Wakeup - Start wake up from OFF to stored Dimmer value " +
 			"(0..100 = Start wake up from OFF to provided value)",
+		Type: ParameterInt,
+		Max:  maxDimmer,
 	}
 	commands = append(commands, c)
 
 	c = models.Command{
 		Name:        "WakeupDuration",
 		Description: "WakeupDuration - 1..3000 = set wake up duration in seconds",
+		Type:        ParameterInt,
+		Min:         1,
+		Max:         maxWakeupDuration,
 	}
 	commands = append(commands, c)
 
 	c = models.Command{
 		Name:        "White",
 		Description: "White - 1..100 = set white channel brightness in single white channel lights (single W or RGBW lights)",
+		Type:        ParameterInt,
+		Min:         1,
+		Max:         maxDimmer,
 	}
 	commands = append(commands, c)
 
@@ -0,0 +1,138 @@
+package devicetypes
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lovesway/hassio-addons/mq-lightshow/colors"
+	"github.com/lovesway/hassio-addons/mq-lightshow/models"
+)
+
+var (
+	errUnsupportedCommand = errors.New("command is not supported by this device type")
+	errInvalidParameter   = errors.New("invalid parameter")
+)
+
+// The commands drivers other than Tasmota understand. They use the Tasmota names and parameters
+// so that one action can be sent to a mix of device types.
+const (
+	CommandPower    = "Power"
+	CommandDimmer   = "Dimmer"
+	CommandColor    = "Color1"
+	CommandCT       = "CT"
+	CommandHsbColor = "HsbColor"
+)
+
+// Driver translates an action into the message a type of device understands.
+type Driver interface {
+	// Message returns the topic and payload to publish for a command. The FullTopic of the
+	// device is already set, to the global pattern when the device does not have its own.
+	Message(device models.Device, command string, parameter string) (string, string, error)
+	// HealthTopics returns the topic the device reports its availability on and a topic it
+	// publishes its state on, either may be empty.
+	HealthTopics(device models.Device) (string, string)
+	// Online reports whether an availability payload means the device is online.
+	Online(payload []byte) bool
+	// Light returns the messages that set the device to the state of a color action.
+	Light(device models.Device, state LightState) ([]Message, error)
+}
+
+// power normalizes the Power parameter to ON, OFF or TOGGLE.
+func power(parameter string) (string, error) {
+	switch strings.ToUpper(strings.TrimSpace(parameter)) {
+	case "ON", "1":
+		return "ON", nil
+	case "OFF", "0":
+		return "OFF", nil
+	case "TOGGLE", "2":
+		return "TOGGLE", nil
+	}
+
+	return "", fmt.Errorf("%w: power %q", errInvalidParameter, parameter)
+}
+
+// intInRange parses a parameter such as Dimmer or CT.
+func intInRange(parameter string, min int, max int) (int, error) {
+	v, err := strconv.Atoi(strings.TrimSpace(parameter))
+	if err != nil || v < min || v > max {
+		return 0, fmt.Errorf("%w: %q is not a number from %v to %v", errInvalidParameter, parameter, min, max)
+	}
+
+	return v, nil
+}
+
+// integer parses a number parameter that has no range.
+func integer(parameter string) (int, error) {
+	v, err := strconv.Atoi(strings.TrimSpace(parameter))
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q is not a number", errInvalidParameter, parameter)
+	}
+
+	return v, nil
+}
+
+// rgb parses a Color1 parameter, either r,g,b or #RRGGBB.
+func rgb(parameter string) (colors.RGB, error) {
+	c, err := colors.ParseRGB(parameter)
+	if err != nil {
+		return c, fmt.Errorf("%w: color %q", errInvalidParameter, parameter)
+	}
+
+	return c, nil
+}
+
+// hsb parses a HsbColor parameter, hue 0..360, saturation and brightness 0..100.
+func hsb(parameter string) (colors.HSB, error) {
+	c, err := colors.ParseHSB(parameter)
+	if err != nil {
+		return c, fmt.Errorf("%w: hsb color %q", errInvalidParameter, parameter)
+	}
+
+	return c, nil
+}
+
+// scale converts a percentage to a range such as 0..255.
+func scale(percent int, max int) int {
+	const hundred = 100
+
+	return (percent*max + hundred/2) / hundred
+}
+
+// jsonPayload marshals a payload built by a driver.
+func jsonPayload(v interface{}) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+func unsupported(typeName string, command string) error {
+	return fmt.Errorf("%w: %v does not support %v", errUnsupportedCommand, typeName, command)
+}
+
+// onlinePayload reports whether an availability payload equals online. Newer versions of
+// some integrations send {"state":"online"} instead of the plain payload.
+func onlinePayload(payload []byte, online string) bool {
+	if string(payload) == online {
+		return true
+	}
+
+	var a struct {
+		State string `json:"state"`
+	}
+
+	return json.Unmarshal(payload, &a) == nil && a.State == online
+}
+
+// hueColor returns the color of a hue and saturation at full brightness, brightness is sent
+// separately so that it maps to the brightness of the light.
+func hueColor(c colors.HSB) [3]int {
+	full := colors.HSB{H: c.H, S: c.S, B: maxDimmer}.RGB()
+
+	return [3]int{full.R, full.G, full.B}
+}
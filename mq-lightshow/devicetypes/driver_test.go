@@ -0,0 +1,296 @@
+package devicetypes_test
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/lovesway/hassio-addons/mq-lightshow/colors"
+	"github.com/lovesway/hassio-addons/mq-lightshow/devicetypes"
+	"github.com/lovesway/hassio-addons/mq-lightshow/models"
+)
+
+// newTemplateType adds a user defined device type that sends JSON to shellies/<topic>/<command>.
+func newTemplateType(t *testing.T, dt *devicetypes.DeviceTypes) models.DeviceType {
+	t.Helper()
+
+	err := dt.LoadFile(filepath.Join(t.TempDir(), "devicetypes.json"))
+	if err != nil {
+		t.Fatalf("LoadFile error = %v", err)
+	}
+
+	template, err := dt.AddDeviceType(models.DeviceType{
+		Name:            "Shelly",
+		TopicTemplate:   "shellies/%topic%/%command%",
+		PayloadTemplate: `{"%command%":"%parameter%"}`,
+		Commands: []models.Command{
+			{Name: devicetypes.CommandPower},
+			{Name: devicetypes.CommandDimmer, Max: 100},
+			{Name: devicetypes.CommandHsbColor, PayloadTemplate: "hsb=%parameter%"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("AddDeviceType error = %v", err)
+	}
+
+	return template
+}
+
+func TestMessage(t *testing.T) {
+	t.Parallel()
+
+	dt := devicetypes.NewDeviceTypes()
+	template := newTemplateType(t, dt)
+
+	tasmota := models.Device{Topic: "lamp", Type: models.DeviceType{ID: devicetypes.TypeTasmota}}
+	tasmotaPrefix := models.Device{
+		Topic:     "lamp",
+		FullTopic: "%prefix%/%topic%/",
+		Type:      models.DeviceType{ID: devicetypes.TypeTasmota},
+	}
+	wled := models.Device{Topic: "wled/kitchen", Type: models.DeviceType{ID: devicetypes.TypeWLED}}
+	zigbee := models.Device{Topic: "zigbee2mqtt/lamp", Type: models.DeviceType{ID: devicetypes.TypeZigbee2MQTT}}
+	haJSON := models.Device{Topic: "light/lamp/set", Type: models.DeviceType{ID: devicetypes.TypeHAJSON}}
+	shelly := models.Device{Topic: "bulb", Type: template}
+
+	tests := []struct {
+		device      models.Device
+		command     string
+		parameter   string
+		wantTopic   string
+		wantPayload string
+		err         bool
+	}{
+		{device: tasmota, command: "Power", parameter: "ON", wantTopic: "lamp/cmnd/Power", wantPayload: "ON"},
+		{device: tasmotaPrefix, command: "Dimmer", parameter: "50", wantTopic: "cmnd/lamp/Dimmer", wantPayload: "50"},
+		{device: wled, command: "Power", parameter: "ON", wantTopic: "wled/kitchen/api", wantPayload: `{"on":true}`},
+		{device: wled, command: "Power", parameter: "2", wantTopic: "wled/kitchen/api", wantPayload: `{"on":"t"}`},
+		{
+			device: wled, command: "Dimmer", parameter: "50",
+			wantTopic: "wled/kitchen/api", wantPayload: `{"bri":128,"on":true}`,
+		},
+		{
+			device: wled, command: "Color1", parameter: "#FF8000",
+			wantTopic: "wled/kitchen/api", wantPayload: `{"on":true,"seg":{"col":[[255,128,0]]}}`,
+		},
+		{
+			device: wled, command: "CT", parameter: "250",
+			wantTopic: "wled/kitchen/api", wantPayload: `{"on":true,"seg":{"cct":4000}}`,
+		},
+		{
+			device: wled, command: "HsbColor", parameter: "0,100,50",
+			wantTopic: "wled/kitchen/api", wantPayload: `{"bri":128,"on":true,"seg":{"col":[[255,0,0]]}}`,
+		},
+		{device: wled, command: "Dimmer", parameter: "101", err: true},
+		{device: wled, command: "Fade", parameter: "1", err: true},
+		{
+			device: zigbee, command: "Power", parameter: "TOGGLE",
+			wantTopic: "zigbee2mqtt/lamp/set", wantPayload: `{"state":"TOGGLE"}`,
+		},
+		{
+			device: zigbee, command: "Dimmer", parameter: "100",
+			wantTopic: "zigbee2mqtt/lamp/set", wantPayload: `{"brightness":254,"state":"ON"}`,
+		},
+		{
+			device: zigbee, command: "Dimmer", parameter: "0",
+			wantTopic: "zigbee2mqtt/lamp/set", wantPayload: `{"brightness":0,"state":"OFF"}`,
+		},
+		{
+			device: zigbee, command: "Color1", parameter: "255,128,0",
+			wantTopic: "zigbee2mqtt/lamp/set", wantPayload: `{"color":{"b":0,"g":128,"r":255},"state":"ON"}`,
+		},
+		{
+			device: zigbee, command: "HsbColor", parameter: "120,50,0",
+			wantTopic: "zigbee2mqtt/lamp/set", wantPayload: `{"brightness":0,"color":{"h":120,"s":50},"state":"OFF"}`,
+		},
+		{device: zigbee, command: "Color1", parameter: "orange", err: true},
+		{
+			device: haJSON, command: "Dimmer", parameter: "100",
+			wantTopic: "light/lamp/set", wantPayload: `{"brightness":255,"state":"ON"}`,
+		},
+		{
+			device: haJSON, command: "CT", parameter: "250",
+			wantTopic: "light/lamp/set", wantPayload: `{"color_temp":250,"state":"ON"}`,
+		},
+		{device: haJSON, command: "Power", parameter: "TOGGLE", err: true},
+		{device: haJSON, command: "CT", parameter: "100", err: true},
+		{device: shelly, command: "Power", parameter: "ON", wantTopic: "shellies/bulb/Power", wantPayload: `{"Power":"ON"}`},
+		{
+			device: shelly, command: "HsbColor", parameter: "0,100,50",
+			wantTopic: "shellies/bulb/HsbColor", wantPayload: "hsb=0,100,50",
+		},
+		{device: shelly, command: "Dimmer", parameter: "101", err: true},
+		{device: shelly, command: "Color1", parameter: "#FF0000", err: true},
+	}
+
+	for _, tt := range tests {
+		topic, payload, err := dt.Driver(tt.device.Type.ID).Message(tt.device, tt.command, tt.parameter)
+		if (err != nil) != tt.err {
+			t.Errorf("%v %v(%q) error = %v, want error %v", tt.device.Topic, tt.command, tt.parameter, err, tt.err)
+
+			continue
+		}
+
+		if tt.err {
+			continue
+		}
+
+		if topic != tt.wantTopic || payload != tt.wantPayload {
+			t.Errorf("%v %v(%q) = %v %v, want %v %v",
+				tt.device.Topic, tt.command, tt.parameter, topic, payload, tt.wantTopic, tt.wantPayload)
+		}
+	}
+}
+
+func TestHealthTopics(t *testing.T) {
+	t.Parallel()
+
+	dt := devicetypes.NewDeviceTypes()
+
+	tests := []struct {
+		device           models.Device
+		wantAvailability string
+		wantState        string
+	}{
+		{
+			device:           models.Device{Topic: "lamp", Type: models.DeviceType{ID: devicetypes.TypeTasmota}},
+			wantAvailability: "lamp/tele/LWT",
+			wantState:        "lamp/tele/STATE",
+		},
+		{
+			device:           models.Device{Topic: "wled/kitchen", Type: models.DeviceType{ID: devicetypes.TypeWLED}},
+			wantAvailability: "wled/kitchen/status",
+			wantState:        "wled/kitchen/g",
+		},
+		{
+			device:           models.Device{Topic: "zigbee2mqtt/lamp", Type: models.DeviceType{ID: devicetypes.TypeZigbee2MQTT}},
+			wantAvailability: "zigbee2mqtt/lamp/availability",
+			wantState:        "zigbee2mqtt/lamp",
+		},
+		{
+			device: models.Device{Topic: "light/lamp/set", Type: models.DeviceType{ID: devicetypes.TypeHAJSON}},
+		},
+	}
+
+	for _, tt := range tests {
+		availability, state := dt.Driver(tt.device.Type.ID).HealthTopics(tt.device)
+		if availability != tt.wantAvailability || state != tt.wantState {
+			t.Errorf("%v HealthTopics = %q, %q, want %q, %q",
+				tt.device.Topic, availability, state, tt.wantAvailability, tt.wantState)
+		}
+	}
+}
+
+func TestOnline(t *testing.T) {
+	t.Parallel()
+
+	dt := devicetypes.NewDeviceTypes()
+
+	tests := []struct {
+		typeID  int
+		payload string
+		want    bool
+	}{
+		{typeID: devicetypes.TypeTasmota, payload: "Online", want: true},
+		{typeID: devicetypes.TypeTasmota, payload: "Offline", want: false},
+		{typeID: devicetypes.TypeWLED, payload: "online", want: true},
+		{typeID: devicetypes.TypeWLED, payload: "offline", want: false},
+		{typeID: devicetypes.TypeZigbee2MQTT, payload: `{"state":"online"}`, want: true},
+		{typeID: devicetypes.TypeZigbee2MQTT, payload: `{"state":"offline"}`, want: false},
+	}
+
+	for _, tt := range tests {
+		if got := dt.Driver(tt.typeID).Online([]byte(tt.payload)); got != tt.want {
+			t.Errorf("type %v Online(%q) = %v, want %v", tt.typeID, tt.payload, got, tt.want)
+		}
+	}
+}
+
+func TestLight(t *testing.T) {
+	t.Parallel()
+
+	dt := devicetypes.NewDeviceTypes()
+	template := newTemplateType(t, dt)
+
+	red := devicetypes.LightState{
+		Color:      colors.RGB{R: 255},
+		HasColor:   true,
+		Brightness: 50,
+		Transition: 1.5,
+	}
+	warm := devicetypes.LightState{CT: 600}
+
+	tests := []struct {
+		device models.Device
+		state  devicetypes.LightState
+		want   []devicetypes.Message
+	}{
+		{
+			device: models.Device{Topic: "lamp", Type: models.DeviceType{ID: devicetypes.TypeTasmota}},
+			state:  red,
+			want: []devicetypes.Message{
+				{Topic: "lamp/cmnd/Fade", Payload: "1"},
+				{Topic: "lamp/cmnd/Speed", Payload: "3"},
+				{Topic: "lamp/cmnd/HsbColor", Payload: "0,100,50"},
+			},
+		},
+		{
+			// a light without color gets the nearest white, without a transition there is no Fade.
+			device: models.Device{
+				Topic:          "lamp",
+				SupportsDimmer: true,
+				SupportsCT:     true,
+				Type:           models.DeviceType{ID: devicetypes.TypeTasmota},
+			},
+			state: warm,
+			want: []devicetypes.Message{
+				{Topic: "lamp/cmnd/CT", Payload: "500"},
+			},
+		},
+		{
+			device: models.Device{Topic: "wled/kitchen", Type: models.DeviceType{ID: devicetypes.TypeWLED}},
+			state:  red,
+			want: []devicetypes.Message{
+				{Topic: "wled/kitchen/api", Payload: `{"bri":128,"on":true,"seg":{"col":[[255,0,0]]},"tt":15}`},
+			},
+		},
+		{
+			device: models.Device{Topic: "zigbee2mqtt/lamp", Type: models.DeviceType{ID: devicetypes.TypeZigbee2MQTT}},
+			state:  red,
+			want: []devicetypes.Message{
+				{
+					Topic:   "zigbee2mqtt/lamp/set",
+					Payload: `{"brightness":127,"color":{"b":0,"g":0,"r":255},"state":"ON","transition":1.5}`,
+				},
+			},
+		},
+		{
+			device: models.Device{Topic: "light/lamp/set", Type: models.DeviceType{ID: devicetypes.TypeHAJSON}},
+			state:  warm,
+			want: []devicetypes.Message{
+				{Topic: "light/lamp/set", Payload: `{"color_temp":500,"state":"ON"}`},
+			},
+		},
+		{
+			// the template type has no Fade and Speed commands, so they are left out.
+			device: models.Device{Topic: "bulb", Type: template},
+			state:  red,
+			want: []devicetypes.Message{
+				{Topic: "shellies/bulb/HsbColor", Payload: "hsb=0,100,50"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		got, err := dt.Driver(tt.device.Type.ID).Light(tt.device, tt.state)
+		if err != nil {
+			t.Errorf("%v Light(%+v) error = %v", tt.device.Topic, tt.state, err)
+
+			continue
+		}
+
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%v Light(%+v) = %v, want %v", tt.device.Topic, tt.state, got, tt.want)
+		}
+	}
+}
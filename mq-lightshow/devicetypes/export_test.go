@@ -0,0 +1,4 @@
+package devicetypes
+
+// ErrDeviceTypesNoFile is returned when device types are changed without a file to save them to.
+var ErrDeviceTypesNoFile = errDeviceTypesNoFile
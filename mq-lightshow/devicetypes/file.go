@@ -0,0 +1,239 @@
+package devicetypes
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lovesway/hassio-addons/mq-lightshow/models"
+)
+
+// FirstUserTypeID is the lowest ID of a user defined device type, lower IDs are for built in types.
+const FirstUserTypeID = 100
+
+var (
+	errDeviceTypeNotFound = errors.New("device type not found")
+	errDeviceTypeBuiltIn  = errors.New("built in device types cannot be changed")
+	errDeviceTypeInvalid  = errors.New("invalid device type")
+	errDeviceTypesNoFile  = errors.New("device types cannot be saved, the device types file could not be loaded")
+	errDeviceTypesFormat  = errors.New("device types can only be loaded from a .json file")
+)
+
+// LoadFile adds the user defined device types in a JSON file, a list of device types. Changes
+// made through the API are saved to the same file. A missing file is not an error, it is
+// created when the first device type is added. Files in other formats, such as YAML, are
+// rejected.
+func (d *DeviceTypes) LoadFile(path string) error {
+	if !strings.EqualFold(filepath.Ext(path), ".json") {
+		return fmt.Errorf("%w: %v", errDeviceTypesFormat, path)
+	}
+
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		d.mu.Lock()
+		d.file = path
+		d.mu.Unlock()
+
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	var dts []models.DeviceType
+
+	err = json.Unmarshal(b, &dts)
+	if err != nil {
+		return fmt.Errorf("cannot parse %v: %w", path, err)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	// the whole file is checked before any of it is used. A file with an error adds no device
+	// types and is not set as the file to save to, so it is not overwritten.
+	loaded := append([]models.DeviceType{}, d.deviceTypes...)
+
+	for _, dt := range dts {
+		dt.BuiltIn = false
+
+		if _, ok := findDeviceType(loaded, dt.ID); ok {
+			return fmt.Errorf("%v: %w: the ID %v of %q is already used", path, errDeviceTypeInvalid, dt.ID, dt.Name)
+		}
+
+		err = validate(dt, loaded)
+		if err != nil {
+			return fmt.Errorf("%v: %w", path, err)
+		}
+
+		loaded = append(loaded, dt)
+	}
+
+	for _, dt := range loaded[len(d.deviceTypes):] {
+		d.drivers[dt.ID] = templateDriver{deviceType: dt}
+	}
+
+	d.deviceTypes = loaded
+	d.file = path
+
+	return nil
+}
+
+// AddDeviceType adds a user defined device type and returns it with its new ID.
+func (d *DeviceTypes) AddDeviceType(dt models.DeviceType) (models.DeviceType, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.file == "" {
+		return dt, errDeviceTypesNoFile
+	}
+
+	dt.ID = FirstUserTypeID
+	for _, t := range d.deviceTypes {
+		if t.ID >= dt.ID {
+			dt.ID = t.ID + 1
+		}
+	}
+
+	dt.BuiltIn = false
+
+	err := validate(dt, d.deviceTypes)
+	if err != nil {
+		return dt, err
+	}
+
+	d.deviceTypes = append(d.deviceTypes, dt)
+	d.drivers[dt.ID] = templateDriver{deviceType: dt}
+
+	return dt, d.save()
+}
+
+// SetDeviceType updates a user defined device type.
+func (d *DeviceTypes) SetDeviceType(dt models.DeviceType) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.file == "" {
+		return errDeviceTypesNoFile
+	}
+
+	i, err := d.userTypeIndex(dt.ID)
+	if err != nil {
+		return err
+	}
+
+	dt.BuiltIn = false
+
+	err = validate(dt, d.deviceTypes)
+	if err != nil {
+		return err
+	}
+
+	d.deviceTypes[i] = dt
+	d.drivers[dt.ID] = templateDriver{deviceType: dt}
+
+	return d.save()
+}
+
+// DeleteDeviceType removes a user defined device type.
+func (d *DeviceTypes) DeleteDeviceType(typeID int) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.file == "" {
+		return errDeviceTypesNoFile
+	}
+
+	i, err := d.userTypeIndex(typeID)
+	if err != nil {
+		return err
+	}
+
+	d.deviceTypes = append(d.deviceTypes[:i], d.deviceTypes[i+1:]...)
+	delete(d.drivers, typeID)
+
+	return d.save()
+}
+
+func (d *DeviceTypes) userTypeIndex(typeID int) (int, error) {
+	for i, dt := range d.deviceTypes {
+		if dt.ID != typeID {
+			continue
+		}
+
+		if dt.BuiltIn {
+			return i, fmt.Errorf("%w: %v", errDeviceTypeBuiltIn, dt.Name)
+		}
+
+		return i, nil
+	}
+
+	return 0, fmt.Errorf("%w: %v", errDeviceTypeNotFound, typeID)
+}
+
+// validate checks a user defined device type against the other device types. A device type
+// with the same ID is the one that dt replaces.
+func validate(dt models.DeviceType, deviceTypes []models.DeviceType) error {
+	if dt.ID < FirstUserTypeID {
+		return fmt.Errorf("%w: the ID of %q must be %v or higher", errDeviceTypeInvalid, dt.Name, FirstUserTypeID)
+	}
+
+	if strings.TrimSpace(dt.Name) == "" {
+		return fmt.Errorf("%w: the name is required", errDeviceTypeInvalid)
+	}
+
+	for _, t := range deviceTypes {
+		if t.ID == dt.ID {
+			continue
+		}
+
+		if strings.EqualFold(t.Name, dt.Name) {
+			return fmt.Errorf("%w: the name %q is already used", errDeviceTypeInvalid, dt.Name)
+		}
+	}
+
+	if len(dt.Commands) == 0 {
+		return fmt.Errorf("%w: %q has no commands", errDeviceTypeInvalid, dt.Name)
+	}
+
+	names := map[string]bool{}
+
+	for _, c := range dt.Commands {
+		if c.Name == "" || names[c.Name] {
+			return fmt.Errorf("%w: %q has an empty or duplicate command name", errDeviceTypeInvalid, dt.Name)
+		}
+
+		err := validateSchema(c)
+		if err != nil {
+			return err
+		}
+
+		names[c.Name] = true
+	}
+
+	return nil
+}
+
+// save writes the user defined device types to the file, d.mu must be held.
+func (d *DeviceTypes) save() error {
+	dts := []models.DeviceType{}
+
+	for _, dt := range d.deviceTypes {
+		if !dt.BuiltIn {
+			dts = append(dts, dt)
+		}
+	}
+
+	b, err := json.MarshalIndent(dts, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	const fileMode = 0o600
+
+	return os.WriteFile(d.file, b, fileMode)
+}
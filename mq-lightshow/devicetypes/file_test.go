@@ -0,0 +1,85 @@
+package devicetypes_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/lovesway/hassio-addons/mq-lightshow/devicetypes"
+	"github.com/lovesway/hassio-addons/mq-lightshow/models"
+)
+
+func TestLoadFile(t *testing.T) {
+	t.Parallel()
+
+	const (
+		shelly = `{"ID": 100, "Name": "Shelly", "Commands": [{"Name": "Power"}]}`
+		sonoff = `{"ID": 101, "Name": "Sonoff", "Commands": [{"Name": "Power"}]}`
+	)
+
+	tests := []struct {
+		name    string
+		content string
+		want    []int // the IDs of the user defined device types after loading.
+		err     bool
+	}{
+		{name: "valid", content: "[" + shelly + "," + sonoff + "]", want: []int{100, 101}},
+		{name: "empty", content: "[]", want: []int{}},
+		{
+			name:    "duplicate ID",
+			content: "[" + shelly + `,{"ID": 100, "Name": "Other", "Commands": [{"Name": "Power"}]}]`,
+			want:    []int{},
+			err:     true,
+		},
+		// the valid device type before the invalid one is not loaded either.
+		{
+			name:    "partly invalid",
+			content: "[" + shelly + `,{"ID": 101, "Name": "NoCommands"}]`,
+			want:    []int{},
+			err:     true,
+		},
+		{
+			name:    "built in ID",
+			content: `[{"ID": 1, "Name": "Mine", "Commands": [{"Name": "Power"}]}]`,
+			want:    []int{},
+			err:     true,
+		},
+		{name: "not JSON", content: "- ID: 100", want: []int{}, err: true},
+	}
+
+	for _, tt := range tests {
+		path := filepath.Join(t.TempDir(), "devicetypes.json")
+
+		err := os.WriteFile(path, []byte(tt.content), 0o600)
+		if err != nil {
+			t.Fatalf("WriteFile error = %v", err)
+		}
+
+		dt := devicetypes.NewDeviceTypes()
+
+		err = dt.LoadFile(path)
+		if (err != nil) != tt.err {
+			t.Errorf("%v: LoadFile error = %v, want error %v", tt.name, err, tt.err)
+		}
+
+		got := []int{}
+
+		for _, d := range dt.GetDeviceTypes() {
+			if !d.BuiltIn {
+				got = append(got, d.ID)
+			}
+		}
+
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%v: loaded device types %v, want %v", tt.name, got, tt.want)
+		}
+
+		// a file that could not be loaded is not overwritten by device types added later.
+		_, err = dt.AddDeviceType(models.DeviceType{Name: "Added", Commands: []models.Command{{Name: "Power"}}})
+		if tt.err != errors.Is(err, devicetypes.ErrDeviceTypesNoFile) {
+			t.Errorf("%v: AddDeviceType error = %v", tt.name, err)
+		}
+	}
+}
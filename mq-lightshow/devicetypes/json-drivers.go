@@ -0,0 +1,233 @@
+package devicetypes
+
+import (
+	"math"
+
+	"github.com/lovesway/hassio-addons/mq-lightshow/colors"
+	"github.com/lovesway/hassio-addons/mq-lightshow/models"
+)
+
+const (
+	maxByte        = 255 // brightness range of WLED and Home Assistant.
+	maxZigbee      = 254 // brightness range of Zigbee lights.
+	minCT, maxCT   = 153, 500
+	maxDimmer      = 100
+	payloadOnline  = "online"
+	stateOnPayload = "ON"
+)
+
+// wledDriver sends to the JSON API of WLED on <topic>/api, the topic being the device topic
+// set in WLED (e.g. wled/kitchen).
+type wledDriver struct{}
+
+func (wledDriver) Message(device models.Device, command string, parameter string) (string, string, error) {
+	var msg map[string]interface{}
+
+	switch command {
+	case CommandPower:
+		p, err := power(parameter)
+		if err != nil {
+			return "", "", err
+		}
+
+		// WLED toggles with "t".
+		on := map[string]interface{}{"ON": true, "OFF": false, "TOGGLE": "t"}[p]
+		msg = map[string]interface{}{"on": on}
+	case CommandDimmer:
+		d, err := intInRange(parameter, 0, maxDimmer)
+		if err != nil {
+			return "", "", err
+		}
+
+		msg = map[string]interface{}{"on": d > 0, "bri": scale(d, maxByte)}
+	case CommandColor:
+		c, err := rgb(parameter)
+		if err != nil {
+			return "", "", err
+		}
+
+		msg = map[string]interface{}{"on": true, "seg": map[string]interface{}{"col": [][3]int{{c.R, c.G, c.B}}}}
+	case CommandCT:
+		ct, err := intInRange(parameter, minCT, maxCT)
+		if err != nil {
+			return "", "", err
+		}
+
+		msg = map[string]interface{}{"on": true, "seg": map[string]interface{}{"cct": colors.MiredsToKelvin(ct)}}
+	case CommandHsbColor:
+		c, err := hsb(parameter)
+		if err != nil {
+			return "", "", err
+		}
+
+		msg = map[string]interface{}{
+			"on":  c.B > 0,
+			"bri": scale(int(c.B), maxByte),
+			"seg": map[string]interface{}{"col": [][3]int{hueColor(c)}},
+		}
+	default:
+		return "", "", unsupported("WLED", command)
+	}
+
+	payload, err := jsonPayload(msg)
+
+	return device.Topic + "/api", payload, err
+}
+
+// Light sets the first segment, WLED takes the transition in tenths of a second.
+func (wledDriver) Light(device models.Device, state LightState) ([]Message, error) {
+	msg := map[string]interface{}{"on": true}
+	seg := map[string]interface{}{}
+
+	if state.HasColor {
+		seg["col"] = [][3]int{{state.Color.R, state.Color.G, state.Color.B}}
+	} else if state.CT > 0 {
+		seg["cct"] = colors.MiredsToKelvin(colors.ClampMireds(state.CT))
+	}
+
+	if len(seg) > 0 {
+		msg["seg"] = seg
+	}
+
+	if state.Brightness > 0 {
+		msg["bri"] = scale(state.Brightness, maxByte)
+	}
+
+	const tenths = 10
+
+	msg["tt"] = int(math.Round(state.Transition * tenths))
+
+	payload, err := jsonPayload(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	return []Message{{Topic: device.Topic + "/api", Payload: payload}}, nil
+}
+
+func (wledDriver) HealthTopics(device models.Device) (string, string) {
+	return device.Topic + "/status", device.Topic + "/g"
+}
+
+func (wledDriver) Online(payload []byte) bool {
+	return onlinePayload(payload, payloadOnline)
+}
+
+// jsonLightDriver sends the JSON payload shared by Zigbee2MQTT (<topic>/set) and Home Assistant
+// MQTT JSON schema lights (the command topic).
+type jsonLightDriver struct {
+	name          string
+	setSuffix     string // appended to the device topic to get the command topic.
+	maxBrightness int
+	canToggle     bool
+	availability  string // appended to the device topic, empty if there is none.
+	stateOnTopic  bool   // the state is published on the device topic itself.
+}
+
+func (j jsonLightDriver) Message(device models.Device, command string, parameter string) (string, string, error) {
+	var msg map[string]interface{}
+
+	switch command {
+	case CommandPower:
+		p, err := power(parameter)
+		if err != nil {
+			return "", "", err
+		}
+
+		if p == "TOGGLE" && !j.canToggle {
+			return "", "", unsupported(j.name, command+" TOGGLE")
+		}
+
+		msg = map[string]interface{}{"state": p}
+	case CommandDimmer:
+		d, err := intInRange(parameter, 0, maxDimmer)
+		if err != nil {
+			return "", "", err
+		}
+
+		state := stateOnPayload
+		if d == 0 {
+			state = "OFF"
+		}
+
+		msg = map[string]interface{}{"state": state, "brightness": scale(d, j.maxBrightness)}
+	case CommandColor:
+		c, err := rgb(parameter)
+		if err != nil {
+			return "", "", err
+		}
+
+		msg = map[string]interface{}{"state": stateOnPayload, "color": map[string]int{"r": c.R, "g": c.G, "b": c.B}}
+	case CommandCT:
+		ct, err := intInRange(parameter, minCT, maxCT)
+		if err != nil {
+			return "", "", err
+		}
+
+		msg = map[string]interface{}{"state": stateOnPayload, "color_temp": ct}
+	case CommandHsbColor:
+		c, err := hsb(parameter)
+		if err != nil {
+			return "", "", err
+		}
+
+		msg = map[string]interface{}{
+			"state":      stateOnPayload,
+			"brightness": scale(int(c.B), j.maxBrightness),
+			"color":      map[string]int{"h": int(c.H), "s": int(c.S)},
+		}
+		if c.B == 0 {
+			msg["state"] = "OFF"
+		}
+	default:
+		return "", "", unsupported(j.name, command)
+	}
+
+	payload, err := jsonPayload(msg)
+
+	return device.Topic + j.setSuffix, payload, err
+}
+
+// Light sends the whole state in one payload, the transition is in seconds.
+func (j jsonLightDriver) Light(device models.Device, state LightState) ([]Message, error) {
+	msg := map[string]interface{}{"state": stateOnPayload}
+
+	if state.HasColor {
+		msg["color"] = map[string]int{"r": state.Color.R, "g": state.Color.G, "b": state.Color.B}
+	} else if state.CT > 0 {
+		msg["color_temp"] = colors.ClampMireds(state.CT)
+	}
+
+	if state.Brightness > 0 {
+		msg["brightness"] = scale(state.Brightness, j.maxBrightness)
+	}
+
+	if state.Transition > 0 {
+		msg["transition"] = state.Transition
+	}
+
+	payload, err := jsonPayload(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	return []Message{{Topic: device.Topic + j.setSuffix, Payload: payload}}, nil
+}
+
+func (j jsonLightDriver) HealthTopics(device models.Device) (string, string) {
+	var availability, state string
+
+	if j.availability != "" {
+		availability = device.Topic + j.availability
+	}
+
+	if j.stateOnTopic {
+		state = device.Topic
+	}
+
+	return availability, state
+}
+
+func (j jsonLightDriver) Online(payload []byte) bool {
+	return onlinePayload(payload, payloadOnline)
+}
@@ -0,0 +1,114 @@
+package devicetypes
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/lovesway/hassio-addons/mq-lightshow/colors"
+	"github.com/lovesway/hassio-addons/mq-lightshow/models"
+)
+
+// Tasmota commands used to fade to the state of a color action.
+const (
+	commandFade  = "Fade"
+	commandSpeed = "Speed"
+)
+
+// LightState is what a color action sets a light to, zero values are left unchanged.
+type LightState struct {
+	Color      colors.RGB
+	HasColor   bool
+	CT         int     // color temperature in mireds.
+	Brightness int     // 1 to 100(%).
+	Transition float64 // seconds.
+}
+
+// Message is a payload to publish on a topic.
+type Message struct {
+	Topic   string
+	Payload string
+}
+
+// NewLightState returns the state a color action sets its lights to.
+func NewLightState(action models.Action) (LightState, error) {
+	state := LightState{
+		CT:         action.CT,
+		Brightness: action.Brightness,
+		Transition: action.Transition,
+	}
+
+	if action.Color != "" {
+		c, err := rgb(action.Color)
+		if err != nil {
+			return state, err
+		}
+
+		state.Color = c
+		state.HasColor = true
+	}
+
+	return state, nil
+}
+
+// command is a Tasmota command and its parameter.
+type command struct {
+	name      string
+	parameter string
+}
+
+// lightCommands returns the Tasmota commands that set a light to a state. A color is sent as
+// HsbColor so the brightness can go with it, lights without color get the nearest white and
+// lights without white channels get the color of the white. Devices that have no capabilities
+// set are assumed to support everything.
+func lightCommands(device models.Device, state LightState) []command {
+	all := !device.SupportsDimmer && !device.SupportsColor && !device.SupportsCT
+	color := all || device.SupportsColor
+	ct := all || device.SupportsCT
+	brightness := state.Brightness
+
+	cmds := []command{}
+
+	switch {
+	case state.HasColor && color:
+		c := state.Color.HSB()
+		if brightness > 0 {
+			c.B = float64(brightness)
+			brightness = 0
+		}
+
+		cmds = append(cmds, command{CommandHsbColor, c.String()})
+	case state.HasColor && ct:
+		cmds = append(cmds, command{CommandCT, strconv.Itoa(state.Color.Mireds())})
+	case state.CT > 0 && ct:
+		cmds = append(cmds, command{CommandCT, strconv.Itoa(colors.ClampMireds(state.CT))})
+	case state.CT > 0 && color:
+		cmds = append(cmds, command{CommandColor, colors.MiredsToRGB(state.CT).String()})
+	}
+
+	if brightness > 0 {
+		cmds = append(cmds, command{CommandDimmer, strconv.Itoa(brightness)})
+	}
+
+	if len(cmds) == 0 {
+		return cmds
+	}
+
+	// without a transition the Fade setting of the device is left as it is.
+	if state.Transition <= 0 {
+		return cmds
+	}
+
+	// Speed is the time of the fade in half seconds.
+	const halfSeconds = 2
+
+	speed := int(math.Round(state.Transition * halfSeconds))
+	if speed < 1 {
+		speed = 1
+	}
+
+	if speed > maxSpeed {
+		speed = maxSpeed
+	}
+
+	return append([]command{{commandFade, "1"}, {commandSpeed, strconv.Itoa(speed)}}, cmds...)
+}
@@ -0,0 +1,96 @@
+package devicetypes
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lovesway/hassio-addons/mq-lightshow/models"
+)
+
+// The types of command parameters. A command without a type takes any text, or a number when
+// it has a Min or Max.
+const (
+	ParameterInt    = "int"    // a number, from Min to Max unless both are 0.
+	ParameterEnum   = "enum"   // one of Values.
+	ParameterColor  = "color"  // r,g,b or #RRGGBB.
+	ParameterHSB    = "hsb"    // hue,saturation,brightness.
+	ParameterToggle = "toggle" // ON, OFF or TOGGLE (or 1, 0 and 2).
+)
+
+// ValidateParameter checks a parameter against the parameter schema of a command. An empty
+// parameter is allowed, Tasmota answers it with the current value.
+func ValidateParameter(c models.Command, parameter string) error {
+	if parameter == "" {
+		return nil
+	}
+
+	var err error
+
+	switch ParameterType(c) {
+	case ParameterInt:
+		if c.Min == 0 && c.Max == 0 {
+			_, err = integer(parameter)
+		} else {
+			_, err = intInRange(parameter, c.Min, c.Max)
+		}
+	case ParameterEnum:
+		for _, v := range c.Values {
+			if strings.EqualFold(v, parameter) {
+				return nil
+			}
+		}
+
+		err = fmt.Errorf("%w: %q is not one of %v", errInvalidParameter, parameter, strings.Join(c.Values, ", "))
+	case ParameterColor:
+		_, err = rgb(parameter)
+	case ParameterHSB:
+		_, err = hsb(parameter)
+	case ParameterToggle:
+		_, err = power(parameter)
+	}
+
+	if err != nil {
+		return fmt.Errorf("%v: %w", c.Name, err)
+	}
+
+	return nil
+}
+
+// FindCommand returns the command of a device type with the given name.
+func FindCommand(dt models.DeviceType, name string) (models.Command, bool) {
+	for _, c := range dt.Commands {
+		if c.Name == name {
+			return c, true
+		}
+	}
+
+	return models.Command{}, false
+}
+
+// ParameterType returns the type of the parameter of a command, see ValidateParameter.
+func ParameterType(c models.Command) string {
+	if c.Type == "" && (c.Min != 0 || c.Max != 0) {
+		return ParameterInt
+	}
+
+	return c.Type
+}
+
+// validateSchema checks the parameter schema of a user defined command.
+func validateSchema(c models.Command) error {
+	switch c.Type {
+	case "", ParameterInt, ParameterColor, ParameterHSB, ParameterToggle:
+	case ParameterEnum:
+		if len(c.Values) == 0 {
+			return fmt.Errorf("%w: %v has no Values", errDeviceTypeInvalid, c.Name)
+		}
+	default:
+		return fmt.Errorf("%w: unknown parameter type %q of %v", errDeviceTypeInvalid, c.Type, c.Name)
+	}
+
+	if c.Min > c.Max {
+		return fmt.Errorf("%w: Min of %v is greater than Max", errDeviceTypeInvalid, c.Name)
+	}
+
+	return nil
+}
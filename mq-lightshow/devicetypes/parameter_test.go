@@ -0,0 +1,61 @@
+package devicetypes_test
+
+import (
+	"testing"
+
+	"github.com/lovesway/hassio-addons/mq-lightshow/devicetypes"
+	"github.com/lovesway/hassio-addons/mq-lightshow/models"
+)
+
+func TestValidateParameter(t *testing.T) {
+	t.Parallel()
+
+	dimmer := models.Command{Name: "Dimmer", Type: devicetypes.ParameterInt, Max: 100}
+	counter := models.Command{Name: "Counter", Type: devicetypes.ParameterInt}
+	ranged := models.Command{Name: "Speed", Min: 1, Max: 40}
+	scheme := models.Command{Name: "Scheme", Type: devicetypes.ParameterEnum, Values: []string{"fire", "rainbow"}}
+	color := models.Command{Name: "Color1", Type: devicetypes.ParameterColor}
+	hsb := models.Command{Name: "HsbColor", Type: devicetypes.ParameterHSB}
+	power := models.Command{Name: "Power", Type: devicetypes.ParameterToggle}
+	text := models.Command{Name: "Text"}
+
+	tests := []struct {
+		command   models.Command
+		parameter string
+		err       bool
+	}{
+		{command: dimmer, parameter: "50"},
+		{command: dimmer, parameter: " 100 "},
+		{command: dimmer, parameter: "101", err: true},
+		{command: dimmer, parameter: "-1", err: true},
+		{command: dimmer, parameter: "half", err: true},
+		// an int without a range takes any number.
+		{command: counter, parameter: "-5000"},
+		{command: counter, parameter: "123456"},
+		{command: counter, parameter: "1.5", err: true},
+		// a command without a type is an int when it has a range.
+		{command: ranged, parameter: "40"},
+		{command: ranged, parameter: "0", err: true},
+		{command: scheme, parameter: "Rainbow"},
+		{command: scheme, parameter: "strobe", err: true},
+		{command: color, parameter: "#FF8000"},
+		{command: color, parameter: "255,128,0"},
+		{command: color, parameter: "orange", err: true},
+		{command: hsb, parameter: "120,50,100"},
+		{command: hsb, parameter: "120,50", err: true},
+		{command: power, parameter: "toggle"},
+		{command: power, parameter: "1"},
+		{command: power, parameter: "maybe", err: true},
+		{command: text, parameter: "anything"},
+		// an empty parameter asks for the current value.
+		{command: dimmer, parameter: ""},
+		{command: color, parameter: ""},
+	}
+
+	for _, tt := range tests {
+		err := devicetypes.ValidateParameter(tt.command, tt.parameter)
+		if (err != nil) != tt.err {
+			t.Errorf("ValidateParameter(%v, %q) error = %v, want error %v", tt.command.Name, tt.parameter, err, tt.err)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package devicetypes
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/lovesway/hassio-addons/mq-lightshow/colors"
+	"github.com/lovesway/hassio-addons/mq-lightshow/models"
+)
+
+// commandState makes a Tasmota device publish its state on its RESULT topic.
+const commandState = "State"
+
+// DeviceState is the light state a device reported, so it can be set back later.
+type DeviceState struct {
+	Power    string // ON or OFF.
+	Dimmer   int    // 0 to 100, -1 when not reported.
+	Color    colors.HSB
+	HasColor bool // the light was showing a color rather than a white.
+	CT       int  // color temperature in mireds, 0 when not reported.
+	Fade     string
+	Speed    int
+}
+
+// StateDriver is implemented by drivers of devices that can report their light state, to
+// snapshot lights before a show and restore them afterwards.
+type StateDriver interface {
+	// StateQuery returns the message that asks the device for its state and the topic the
+	// device answers on.
+	StateQuery(device models.Device) (Message, string)
+	// State reads a state report. It returns false when the payload has no light state.
+	State(payload []byte) (DeviceState, bool)
+	// Restore returns the messages that set the device back to a state.
+	Restore(device models.Device, state DeviceState) ([]Message, error)
+}
+
+// tasmotaState is the part of a Tasmota STATE or RESULT payload that describes the light.
+type tasmotaState struct {
+	Power    string `json:"POWER"`
+	Power1   string `json:"POWER1"`
+	Dimmer   *int
+	Color    string
+	HSBColor string
+	CT       int
+	Fade     string
+	Speed    int
+}
+
+func (d tasmotaDriver) StateQuery(device models.Device) (Message, string) {
+	topic, payload, _ := d.Message(device, commandState, "")
+	stat := FullTopic(device.FullTopic, PrefixStat, device.Topic, device.Hostname)
+
+	return Message{Topic: topic, Payload: payload}, stat + "/RESULT"
+}
+
+func (tasmotaDriver) State(payload []byte) (DeviceState, bool) {
+	var ts tasmotaState
+
+	if err := json.Unmarshal(payload, &ts); err != nil {
+		return DeviceState{}, false
+	}
+
+	state := DeviceState{Power: ts.Power, Dimmer: -1, CT: ts.CT, Fade: ts.Fade, Speed: ts.Speed}
+	if state.Power == "" {
+		state.Power = ts.Power1
+	}
+
+	// results of commands that do not change the light, such as Fade, have no power state.
+	if state.Power == "" {
+		return DeviceState{}, false
+	}
+
+	if ts.Dimmer != nil {
+		state.Dimmer = *ts.Dimmer
+	}
+
+	// a light showing white has its color channels off, keep the color temperature for those.
+	if c, err := colors.ParseHSB(ts.HSBColor); err == nil && tasmotaColorOn(ts.Color) {
+		state.Color = c
+		state.HasColor = true
+	}
+
+	return state, true
+}
+
+// tasmotaColorOn reports whether any of the red, green and blue channels of a Tasmota Color
+// is on. Color is hex (FF8000, with white channels after it) or decimal (255,128,0).
+func tasmotaColorOn(color string) bool {
+	const rgbChannels = 3
+
+	if strings.Contains(color, ",") {
+		channels := strings.Split(color, ",")
+		if len(channels) < rgbChannels {
+			return false
+		}
+
+		for _, ch := range channels[:rgbChannels] {
+			if v, err := strconv.Atoi(strings.TrimSpace(ch)); err == nil && v > 0 {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	const hexDigits = rgbChannels * 2
+
+	if len(color) < hexDigits {
+		return false
+	}
+
+	v, err := strconv.ParseUint(color[:hexDigits], 16, 32)
+
+	return err == nil && v > 0
+}
+
+func (d tasmotaDriver) Restore(device models.Device, state DeviceState) ([]Message, error) {
+	// restore without fading, then put the fade settings back.
+	cmds := []command{{commandFade, "0"}}
+
+	switch {
+	case state.HasColor:
+		cmds = append(cmds, command{CommandHsbColor, state.Color.String()})
+	case state.CT > 0:
+		cmds = append(cmds, command{CommandCT, strconv.Itoa(state.CT)})
+	}
+
+	if state.Dimmer >= 0 {
+		cmds = append(cmds, command{CommandDimmer, strconv.Itoa(state.Dimmer)})
+	}
+
+	cmds = append(cmds, command{CommandPower, state.Power})
+
+	if state.Speed > 0 {
+		cmds = append(cmds, command{commandSpeed, strconv.Itoa(state.Speed)})
+	}
+
+	if state.Fade != "" {
+		cmds = append(cmds, command{commandFade, state.Fade})
+	}
+
+	msgs := []Message{}
+
+	for _, c := range cmds {
+		topic, payload, err := d.Message(device, c.name, c.parameter)
+		if err != nil {
+			return nil, err
+		}
+
+		msgs = append(msgs, Message{Topic: topic, Payload: payload})
+	}
+
+	return msgs, nil
+}
@@ -0,0 +1,41 @@
+package devicetypes
+
+import (
+	"github.com/lovesway/hassio-addons/mq-lightshow/models"
+)
+
+const tasmotaLWTOnline = "Online"
+
+// tasmotaDriver sends the command as is to cmnd/<topic>/<Command>.
+type tasmotaDriver struct{}
+
+func (tasmotaDriver) Message(device models.Device, command string, parameter string) (string, string, error) {
+	topic := FullTopic(device.FullTopic, PrefixCommand, device.Topic, device.Hostname)
+
+	return topic + "/" + command, parameter, nil
+}
+
+func (tasmotaDriver) HealthTopics(device models.Device) (string, string) {
+	tele := FullTopic(device.FullTopic, PrefixTele, device.Topic, device.Hostname)
+
+	return tele + "/LWT", tele + "/STATE"
+}
+
+func (tasmotaDriver) Online(payload []byte) bool {
+	return string(payload) == tasmotaLWTOnline
+}
+
+func (d tasmotaDriver) Light(device models.Device, state LightState) ([]Message, error) {
+	msgs := []Message{}
+
+	for _, c := range lightCommands(device, state) {
+		topic, payload, err := d.Message(device, c.name, c.parameter)
+		if err != nil {
+			return nil, err
+		}
+
+		msgs = append(msgs, Message{Topic: topic, Payload: payload})
+	}
+
+	return msgs, nil
+}
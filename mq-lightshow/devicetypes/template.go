@@ -0,0 +1,89 @@
+package devicetypes
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/lovesway/hassio-addons/mq-lightshow/models"
+)
+
+// templateDriver sends the commands of a user defined device type using its topic and payload
+// templates.
+type templateDriver struct {
+	deviceType models.DeviceType
+}
+
+func (t templateDriver) Message(device models.Device, command string, parameter string) (string, string, error) {
+	var cmd *models.Command
+
+	for i := range t.deviceType.Commands {
+		if t.deviceType.Commands[i].Name == command {
+			cmd = &t.deviceType.Commands[i]
+
+			break
+		}
+	}
+
+	if cmd == nil {
+		return "", "", unsupported(t.deviceType.Name, command)
+	}
+
+	err := ValidateParameter(*cmd, parameter)
+	if err != nil {
+		return "", "", err
+	}
+
+	topic := FullTopic(device.FullTopic, PrefixCommand, device.Topic, device.Hostname) + "/" + command
+	if t.deviceType.TopicTemplate != "" {
+		r := strings.NewReplacer("%topic%", device.Topic, "%hostname%", device.Hostname, "%command%", command)
+		topic = r.Replace(t.deviceType.TopicTemplate)
+	}
+
+	payload := t.deviceType.PayloadTemplate
+	if cmd.PayloadTemplate != "" {
+		payload = cmd.PayloadTemplate
+	}
+
+	if payload == "" {
+		return topic, parameter, nil
+	}
+
+	r := strings.NewReplacer("%parameter%", parameter, "%command%", command)
+
+	return topic, r.Replace(payload), nil
+}
+
+// HealthTopics of Tasmota are used when the commands go to the Tasmota command topics, other
+// devices are not tracked.
+func (t templateDriver) HealthTopics(device models.Device) (string, string) {
+	if t.deviceType.TopicTemplate != "" {
+		return "", ""
+	}
+
+	return tasmotaDriver{}.HealthTopics(device)
+}
+
+func (t templateDriver) Online(payload []byte) bool {
+	return tasmotaDriver{}.Online(payload)
+}
+
+// Light sends the Tasmota commands of a color action, the fade is left out when the device
+// type does not have the Fade and Speed commands.
+func (t templateDriver) Light(device models.Device, state LightState) ([]Message, error) {
+	msgs := []Message{}
+
+	for _, c := range lightCommands(device, state) {
+		topic, payload, err := t.Message(device, c.name, c.parameter)
+		if errors.Is(err, errUnsupportedCommand) && (c.name == commandFade || c.name == commandSpeed) {
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		msgs = append(msgs, Message{Topic: topic, Payload: payload})
+	}
+
+	return msgs, nil
+}
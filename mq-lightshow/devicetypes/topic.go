@@ -0,0 +1,63 @@
+package devicetypes
+
+import "strings"
+
+const (
+	// DefaultFullTopic is the layout used before FullTopic became configurable. Tasmota itself
+	// defaults to "%prefix%/%topic%/".
+	DefaultFullTopic = "%topic%/%prefix%/"
+
+	// PrefixCommand is the prefix of topics commands are sent to.
+	PrefixCommand = "cmnd"
+	// PrefixStat is the prefix of topics command results are published on.
+	PrefixStat = "stat"
+	// PrefixTele is the prefix of topics telemetry and the LWT are published on.
+	PrefixTele = "tele"
+)
+
+// FullTopic expands a Tasmota FullTopic pattern such as "%prefix%/%topic%/" into the topic a
+// message is published on, without the trailing command or message name.
+func FullTopic(pattern string, prefix string, topic string, hostname string) string {
+	if pattern == "" {
+		pattern = DefaultFullTopic
+	}
+
+	r := strings.NewReplacer("%prefix%", prefix, "%topic%", topic, "%hostname%", hostname)
+
+	return strings.TrimSuffix(r.Replace(pattern), "/")
+}
+
+// MatchTopic is the reverse of FullTopic. It returns the %topic% of a device when fullTopic was
+// built from pattern with the given prefix, followed by a single message name such as LWT.
+func MatchTopic(pattern string, prefix string, fullTopic string) (string, bool) {
+	if pattern == "" {
+		pattern = DefaultFullTopic
+	}
+
+	parts := strings.Split(strings.TrimSuffix(pattern, "/"), "/")
+	segments := strings.Split(fullTopic, "/")
+
+	if len(segments) != len(parts)+1 {
+		return "", false
+	}
+
+	var topic string
+
+	for i, part := range parts {
+		switch part {
+		case "%prefix%":
+			if segments[i] != prefix {
+				return "", false
+			}
+		case "%topic%":
+			topic = segments[i]
+		case "%hostname%":
+		default:
+			if segments[i] != part {
+				return "", false
+			}
+		}
+	}
+
+	return topic, topic != ""
+}
@@ -0,0 +1,66 @@
+package devicetypes_test
+
+import (
+	"testing"
+
+	"github.com/lovesway/hassio-addons/mq-lightshow/devicetypes"
+)
+
+func TestFullTopic(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		pattern  string
+		prefix   string
+		topic    string
+		hostname string
+		want     string
+	}{
+		{pattern: "", prefix: "cmnd", topic: "lamp", want: "lamp/cmnd"},
+		{pattern: devicetypes.DefaultFullTopic, prefix: "tele", topic: "lamp", want: "lamp/tele"},
+		{pattern: "%prefix%/%topic%/", prefix: "cmnd", topic: "lamp", want: "cmnd/lamp"},
+		{pattern: "%prefix%/%topic%", prefix: "stat", topic: "lamp", want: "stat/lamp"},
+		{
+			pattern: "home/%hostname%/%prefix%/", prefix: "cmnd", topic: "lamp", hostname: "tasmota-1A2B",
+			want: "home/tasmota-1A2B/cmnd",
+		},
+		{pattern: "tasmota/%topic%/%prefix%/", prefix: "tele", topic: "kitchen/lamp", want: "tasmota/kitchen/lamp/tele"},
+	}
+
+	for _, tt := range tests {
+		got := devicetypes.FullTopic(tt.pattern, tt.prefix, tt.topic, tt.hostname)
+		if got != tt.want {
+			t.Errorf("FullTopic(%q, %q, %q, %q) = %q, want %q", tt.pattern, tt.prefix, tt.topic, tt.hostname, got, tt.want)
+		}
+	}
+}
+
+func TestMatchTopic(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		pattern   string
+		prefix    string
+		fullTopic string
+		want      string
+		ok        bool
+	}{
+		{pattern: "", prefix: "tele", fullTopic: "lamp/tele/LWT", want: "lamp", ok: true},
+		{pattern: "%prefix%/%topic%/", prefix: "tele", fullTopic: "tele/lamp/STATE", want: "lamp", ok: true},
+		{pattern: "tasmota/%topic%/%prefix%/", prefix: "tele", fullTopic: "tasmota/lamp/tele/LWT", want: "lamp", ok: true},
+		{pattern: "%prefix%/%hostname%/%topic%/", prefix: "tele", fullTopic: "tele/host/lamp/LWT", want: "lamp", ok: true},
+		{pattern: "%prefix%/%topic%/", prefix: "tele", fullTopic: "stat/lamp/LWT"},
+		{pattern: "%prefix%/%topic%/", prefix: "tele", fullTopic: "tele/lamp"},
+		{pattern: "%prefix%/%topic%/", prefix: "tele", fullTopic: "tele/kitchen/lamp/LWT"},
+		{pattern: "tasmota/%topic%/%prefix%/", prefix: "tele", fullTopic: "other/lamp/tele/LWT"},
+		{pattern: "%prefix%/%hostname%/", prefix: "tele", fullTopic: "tele/host/LWT"},
+	}
+
+	for _, tt := range tests {
+		got, ok := devicetypes.MatchTopic(tt.pattern, tt.prefix, tt.fullTopic)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("MatchTopic(%q, %q, %q) = %q, %v, want %q, %v",
+				tt.pattern, tt.prefix, tt.fullTopic, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lovesway/hassio-addons/mq-lightshow/models"
+)
+
+const (
+	discoveryPrefix   = "homeassistant"
+	sceneCommandTopic = "mqlightshow/scene/+/cmnd"
+	sceneRunPayload   = "RUN"
+)
+
+// haDevice groups all discovered entities under one device in Home Assistant.
+type haDevice struct {
+	Identifiers  []string `json:"identifiers"`
+	Name         string   `json:"name"`
+	Manufacturer string   `json:"manufacturer"`
+	Model        string   `json:"model"`
+	SWVersion    string   `json:"sw_version"`
+}
+
+// haDiscovery is the config payload of a Home Assistant MQTT discovery message.
+type haDiscovery struct {
+	Name                string   `json:"name"`
+	UniqueID            string   `json:"unique_id"`
+	CommandTopic        string   `json:"command_topic"`
+	StateTopic          string   `json:"state_topic,omitempty"`
+	PayloadOn           string   `json:"payload_on,omitempty"`
+	PayloadOff          string   `json:"payload_off,omitempty"`
+	PayloadPress        string   `json:"payload_press,omitempty"`
+	AvailabilityTopic   string   `json:"availability_topic"`
+	PayloadAvailable    string   `json:"payload_available"`
+	PayloadNotAvailable string   `json:"payload_not_available"`
+	Device              haDevice `json:"device"`
+}
+
+func (mqc *MQController) newHaDiscovery(name string, uniqueID string, commandTopic string) haDiscovery {
+	return haDiscovery{
+		Name:                name,
+		UniqueID:            uniqueID,
+		CommandTopic:        commandTopic,
+		AvailabilityTopic:   mqc.statusTopic,
+		PayloadAvailable:    payloadOnline,
+		PayloadNotAvailable: payloadOffline,
+		Device: haDevice{
+			Identifiers:  []string{"mqlightshow"},
+			Name:         "MQ Light Show",
+			Manufacturer: "Love's Way",
+			Model:        "MQ Light Show Add-on",
+			SWVersion:    version,
+		},
+	}
+}
+
+func showDiscoveryTopic(showID int) string {
+	return fmt.Sprintf("%s/switch/mqlightshow/show_%v/config", discoveryPrefix, showID)
+}
+
+func sceneDiscoveryTopic(sceneID int) string {
+	return fmt.Sprintf("%s/button/mqlightshow/scene_%v/config", discoveryPrefix, sceneID)
+}
+
+// PublishDiscovery announces all shows and scenes to Home Assistant.
+func (mqc *MQController) PublishDiscovery() {
+	shows, err := mqc.md.GetShows()
+	if err != nil {
+		log.Errorf("error publishing discovery: %v", err.Error())
+
+		return
+	}
+
+	for _, show := range shows {
+		mqc.RegisterShow(show)
+	}
+
+	scenes, err := mqc.md.GetScenes()
+	if err != nil {
+		log.Errorf("error publishing discovery: %v", err.Error())
+
+		return
+	}
+
+	for _, scene := range scenes {
+		mqc.PublishSceneDiscovery(scene)
+	}
+}
+
+// RegisterShow subscribes to the command topic of a show and announces it to Home Assistant as a switch.
+// A show without a topic cannot be controlled over mqtt, so any previous announcement is removed.
+func (mqc *MQController) RegisterShow(show models.Show) {
+	if !mqc.IsConnected() {
+		return
+	}
+
+	if show.Topic == "" {
+		mqc.publishDiscovery(showDiscoveryTopic(show.ID), nil)
+
+		return
+	}
+
+	mqc.Subscribe(fmt.Sprintf("mqlightshow/show/%v/cmnd/#", show.Topic))
+
+	d := mqc.newHaDiscovery(show.Name, fmt.Sprintf("mqlightshow_show_%v", show.ID),
+		fmt.Sprintf("mqlightshow/show/%v/cmnd", show.Topic))
+	d.StateTopic = fmt.Sprintf("mqlightshow/show/%v/stat", show.Topic)
+	d.PayloadOn = "ON"
+	d.PayloadOff = "OFF"
+
+	mqc.publishDiscovery(showDiscoveryTopic(show.ID), d)
+}
+
+// UnregisterShow unsubscribes from the command topic of a show and removes it from Home Assistant.
+func (mqc *MQController) UnregisterShow(show models.Show) {
+	if !mqc.IsConnected() {
+		return
+	}
+
+	if show.Topic != "" {
+		mqc.Unsubscribe(fmt.Sprintf("mqlightshow/show/%v/cmnd/#", show.Topic))
+	}
+
+	mqc.publishDiscovery(showDiscoveryTopic(show.ID), nil)
+}
+
+// PublishSceneDiscovery announces a scene to Home Assistant as a button.
+func (mqc *MQController) PublishSceneDiscovery(scene models.Scene) {
+	if !mqc.IsConnected() {
+		return
+	}
+
+	d := mqc.newHaDiscovery(scene.Name, fmt.Sprintf("mqlightshow_scene_%v", scene.ID),
+		fmt.Sprintf("mqlightshow/scene/%v/cmnd", scene.ID))
+	d.PayloadPress = sceneRunPayload
+
+	mqc.publishDiscovery(sceneDiscoveryTopic(scene.ID), d)
+}
+
+// RemoveSceneDiscovery removes a scene from Home Assistant.
+func (mqc *MQController) RemoveSceneDiscovery(sceneID int) {
+	if !mqc.IsConnected() {
+		return
+	}
+
+	mqc.publishDiscovery(sceneDiscoveryTopic(sceneID), nil)
+}
+
+// publishDiscovery sends a retained discovery config. A nil config sends the empty payload
+// that tells Home Assistant to remove the entity.
+func (mqc *MQController) publishDiscovery(topic string, config interface{}) {
+	var payload []byte
+
+	if config != nil {
+		var err error
+
+		payload, err = json.Marshal(config)
+		if err != nil {
+			log.Error(err.Error())
+
+			return
+		}
+	}
+
+	_token := mqc.mc.Publish(topic, mqc.qos, true, payload)
+	_token.Wait()
+}
+
+// handleSceneCommand runs a scene when its button is pressed in Home Assistant.
+// It returns false if the message is not a scene command.
+func (mqc *MQController) handleSceneCommand(topic string, cmd string) bool {
+	topicSplit := strings.Split(topic, "/")
+
+	const four = 4
+	if len(topicSplit) != four || topicSplit[0] != "mqlightshow" || topicSplit[1] != "scene" || topicSplit[3] != "cmnd" {
+		return false
+	}
+
+	if cmd != sceneRunPayload {
+		return true
+	}
+
+	sceneID, err := strconv.Atoi(topicSplit[2])
+	if err != nil {
+		log.Errorf("mqtt error: invalid scene id in topic: %v", topic)
+
+		return true
+	}
+
+	go ex.ExecuteSceneByID(sceneID)
+
+	return true
+}
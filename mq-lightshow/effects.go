@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/lovesway/hassio-addons/mq-lightshow/colors"
+	"github.com/lovesway/hassio-addons/mq-lightshow/models"
+)
+
+const (
+	defaultEffectSpeed = 2  // steps per second.
+	maxEffectSpeed     = 20 // steps per second, so that an effect cannot flood the broker.
+	rainbowColors      = 12 // colors of the palette of effects without one.
+	breatheSteps       = 20 // steps of a breath in and out.
+)
+
+// effectGenerator returns the colors of the devices of an effect at a step, keyed by the index of
+// the device.
+type effectGenerator func(step int, devices int, palette []colors.HSB, rnd *rand.Rand) map[int]colors.HSB
+
+// getEffectGenerator returns the generator of a type of effect.
+func getEffectGenerator(effectType string) (effectGenerator, bool) {
+	switch effectType {
+	case models.EffectRainbowChase:
+		return rainbowChase, true
+	case models.EffectSparkle:
+		return sparkle, true
+	case models.EffectBreathe:
+		return breathe, true
+	case models.EffectStrobe:
+		return strobe, true
+	}
+
+	return nil, false
+}
+
+// rainbowChase moves the palette along the devices one device per step.
+func rainbowChase(step int, devices int, palette []colors.HSB, _ *rand.Rand) map[int]colors.HSB {
+	frame := map[int]colors.HSB{}
+	for i := 0; i < devices; i++ {
+		frame[i] = palette[(i+step)%len(palette)]
+	}
+
+	return frame
+}
+
+// sparkle lights a random device in a random color of the palette each step, the others are dark.
+func sparkle(_ int, devices int, palette []colors.HSB, rnd *rand.Rand) map[int]colors.HSB {
+	frame := map[int]colors.HSB{}
+	for i := 0; i < devices; i++ {
+		frame[i] = colors.HSB{H: palette[0].H, S: palette[0].S}
+	}
+
+	frame[rnd.Intn(devices)] = palette[rnd.Intn(len(palette))]
+
+	return frame
+}
+
+// breathe fades all devices in and out together, changing to the next color of the palette
+// with each breath.
+func breathe(step int, devices int, palette []colors.HSB, _ *rand.Rand) map[int]colors.HSB {
+	const half, maxBrightness = 0.5, 100.0
+
+	phase := float64(step%breatheSteps) / breatheSteps
+
+	c := palette[(step/breatheSteps)%len(palette)]
+	c.B = math.Max(1, (half-half*math.Cos(2*math.Pi*phase))*maxBrightness)
+
+	frame := map[int]colors.HSB{}
+	for i := 0; i < devices; i++ {
+		frame[i] = c
+	}
+
+	return frame
+}
+
+// strobe flashes all devices every other step, changing to the next color of the palette with
+// each flash.
+func strobe(step int, devices int, palette []colors.HSB, _ *rand.Rand) map[int]colors.HSB {
+	const flashSteps = 2
+
+	c := palette[(step/flashSteps)%len(palette)]
+	if step%flashSteps == 1 {
+		c.B = 0
+	}
+
+	frame := map[int]colors.HSB{}
+	for i := 0; i < devices; i++ {
+		frame[i] = c
+	}
+
+	return frame
+}
+
+// effectPalette returns the colors of the palette of an effect, or a rainbow when it has none.
+func effectPalette(effect models.Effect) []colors.HSB {
+	palette := []colors.HSB{}
+
+	for _, p := range effect.Palette {
+		c, err := colors.ParseRGB(p)
+		if err != nil {
+			log.Errorf("Effect %v: %v", effect.Name, err)
+
+			continue
+		}
+
+		palette = append(palette, c.HSB())
+	}
+
+	if len(palette) > 0 {
+		return palette
+	}
+
+	const maxHue, maxPercent = 360, 100
+
+	for i := 0; i < rainbowColors; i++ {
+		palette = append(palette, colors.HSB{H: float64(i * maxHue / rainbowColors), S: maxPercent, B: maxPercent})
+	}
+
+	return palette
+}
+
+// runEffect runs an effect for its duration. Only the colors that changed since the last step
+// are sent. The reverse direction reverses the order of the devices and of the palette.
+func (e Executor) runEffect(ctx context.Context, rs *RunningShow, effect models.Effect) {
+	generate, ok := getEffectGenerator(effect.Type)
+	if !ok {
+		log.Errorf("Effect %v has an unknown type: %v", effect.Name, effect.Type)
+
+		return
+	}
+
+	devices := append([]models.Device{}, effect.Devices...)
+	if len(devices) == 0 {
+		return
+	}
+
+	for _, d := range devices {
+		if _, ok := colorCommand(d); !ok {
+			log.Warnf("Effect %v: %v cannot set a color, it is skipped", effect.Name, d.Name)
+		}
+	}
+
+	palette := effectPalette(effect)
+
+	if effect.Direction == models.EffectReverse {
+		for i, j := 0, len(devices)-1; i < j; i, j = i+1, j-1 {
+			devices[i], devices[j] = devices[j], devices[i]
+		}
+
+		for i, j := 0, len(palette)-1; i < j; i, j = i+1, j-1 {
+			palette[i], palette[j] = palette[j], palette[i]
+		}
+	}
+
+	speed := effect.Speed
+	if speed <= 0 {
+		speed = defaultEffectSpeed
+	}
+
+	steps := int(math.Ceil(float64(effect.Duration * speed)))
+	if steps < 1 {
+		steps = 1
+	}
+
+	rnd := rand.New(rand.NewSource(time.Now().UnixNano()))
+	sent := map[int]string{}
+
+	for step := 0; step < steps; step++ {
+		rs.waitIfPaused(ctx)
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		for i, c := range generate(step, len(devices), palette, rnd) {
+			if sent[i] == c.String() {
+				continue
+			}
+
+			sent[i] = c.String()
+			e.sendColor(devices[i], c)
+		}
+
+		e.waitForSeconds(ctx, 1/speed)
+	}
+}
@@ -0,0 +1,169 @@
+package main_test
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+
+	main "github.com/lovesway/hassio-addons/mq-lightshow"
+	"github.com/lovesway/hassio-addons/mq-lightshow/colors"
+	"github.com/lovesway/hassio-addons/mq-lightshow/models"
+)
+
+func TestEffectGenerators(t *testing.T) {
+	t.Parallel()
+
+	red := colors.HSB{H: 0, S: 100, B: 100}
+	green := colors.HSB{H: 120, S: 100, B: 100}
+	blue := colors.HSB{H: 240, S: 100, B: 100}
+	palette := []colors.HSB{red, green, blue}
+
+	// dim returns a color at a brightness.
+	dim := func(c colors.HSB, b float64) colors.HSB {
+		c.B = b
+
+		return c
+	}
+
+	// all returns the frame of all three devices in one color.
+	all := func(c colors.HSB) map[int]colors.HSB {
+		return map[int]colors.HSB{0: c, 1: c, 2: c}
+	}
+
+	tests := []struct {
+		effectType string
+		step       int
+		want       map[int]colors.HSB
+	}{
+		{effectType: models.EffectRainbowChase, step: 0, want: map[int]colors.HSB{0: red, 1: green, 2: blue}},
+		{effectType: models.EffectRainbowChase, step: 1, want: map[int]colors.HSB{0: green, 1: blue, 2: red}},
+		{effectType: models.EffectRainbowChase, step: 5, want: map[int]colors.HSB{0: blue, 1: red, 2: green}},
+		{effectType: models.EffectStrobe, step: 0, want: all(red)},
+		{effectType: models.EffectStrobe, step: 1, want: all(dim(red, 0))},
+		{effectType: models.EffectStrobe, step: 2, want: all(green)},
+		// a breath takes 20 steps, it is brightest half way.
+		{effectType: models.EffectBreathe, step: 0, want: all(dim(red, 1))},
+		{effectType: models.EffectBreathe, step: 10, want: all(red)},
+		{effectType: models.EffectBreathe, step: 20, want: all(dim(green, 1))},
+	}
+
+	for _, tt := range tests {
+		got, ok := main.EffectFrame(tt.effectType, tt.step, len(palette), palette, nil)
+		if !ok {
+			t.Errorf("%v has no generator", tt.effectType)
+
+			continue
+		}
+
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%v at step %v = %v, want %v", tt.effectType, tt.step, got, tt.want)
+		}
+	}
+
+	if _, ok := main.EffectFrame("fireworks", 0, 1, palette, nil); ok {
+		t.Errorf("an unknown effect type has a generator")
+	}
+}
+
+func TestSparkle(t *testing.T) {
+	t.Parallel()
+
+	const devices, steps = 5, 50
+
+	palette := []colors.HSB{{H: 0, S: 100, B: 100}, {H: 120, S: 100, B: 100}}
+	rnd := rand.New(rand.NewSource(1))
+
+	for step := 0; step < steps; step++ {
+		frame, _ := main.EffectFrame(models.EffectSparkle, step, devices, palette, rnd)
+		if len(frame) != devices {
+			t.Fatalf("step %v: %v colors, want %v", step, len(frame), devices)
+		}
+
+		lit := 0
+
+		for _, c := range frame {
+			if c.B > 0 {
+				lit++
+
+				if c != palette[0] && c != palette[1] {
+					t.Errorf("step %v: %+v is not in the palette", step, c)
+				}
+			}
+		}
+
+		if lit != 1 {
+			t.Errorf("step %v: %v devices are lit, want 1", step, lit)
+		}
+	}
+}
+
+func TestEffectPalette(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		palette []string
+		want    []colors.HSB
+	}{
+		{
+			palette: []string{"#FF0000", "0,0,255"},
+			want:    []colors.HSB{{H: 0, S: 100, B: 100}, {H: 240, S: 100, B: 100}},
+		},
+		// colors that do not parse are left out.
+		{
+			palette: []string{"red", "#00FF00"},
+			want:    []colors.HSB{{H: 120, S: 100, B: 100}},
+		},
+	}
+
+	for _, tt := range tests {
+		got := main.EffectPalette(models.Effect{Name: "effect", Palette: tt.palette})
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("effectPalette(%v) = %v, want %v", tt.palette, got, tt.want)
+		}
+	}
+
+	const rainbowColors = 12
+
+	rainbow := main.EffectPalette(models.Effect{Name: "effect"})
+	if len(rainbow) != rainbowColors || rainbow[0].H != 0 || rainbow[1].H != 30 {
+		t.Errorf("effectPalette without colors = %v, want a rainbow of %v colors", rainbow, rainbowColors)
+	}
+}
+
+func TestValidateEffect(t *testing.T) {
+	t.Parallel()
+
+	valid := models.Effect{
+		Name:     "effect",
+		Type:     models.EffectRainbowChase,
+		Devices:  []models.Device{{ID: 1}},
+		Speed:    2,
+		Duration: 10,
+	}
+
+	tests := []struct {
+		name   string
+		change func(e *models.Effect)
+		field  string // the field of the error, empty when the effect is valid.
+	}{
+		{name: "valid", change: func(e *models.Effect) {}},
+		{name: "reverse", change: func(e *models.Effect) { e.Direction = models.EffectReverse }},
+		{name: "default speed", change: func(e *models.Effect) { e.Speed = 0 }},
+		{name: "no name", change: func(e *models.Effect) { e.Name = "" }, field: "Name"},
+		{name: "unknown type", change: func(e *models.Effect) { e.Type = "fireworks" }, field: "Type"},
+		{name: "unknown direction", change: func(e *models.Effect) { e.Direction = "up" }, field: "Direction"},
+		{name: "no devices", change: func(e *models.Effect) { e.Devices = nil }, field: "DeviceIDs"},
+		{name: "negative speed", change: func(e *models.Effect) { e.Speed = -1 }, field: "Speed"},
+		{name: "no duration", change: func(e *models.Effect) { e.Duration = 0 }, field: "Duration"},
+	}
+
+	for _, tt := range tests {
+		effect := valid
+		tt.change(&effect)
+
+		err := main.ValidateEffect(effect)
+		if (err != nil) != (tt.field != "") || main.ErrorField(err) != tt.field {
+			t.Errorf("%v: validateEffect error = %v, want an error about %q", tt.name, err, tt.field)
+		}
+	}
+}
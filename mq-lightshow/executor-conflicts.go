@@ -0,0 +1,143 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lovesway/hassio-addons/mq-lightshow/models"
+)
+
+var (
+	errShowConflict   = errors.New("show uses devices of running shows")
+	errConflictPolicy = errors.New("conflict policy must be share, reject or preempt")
+)
+
+// What starting a show does about running shows that use some of its devices.
+const (
+	conflictPolicyShare   = "share"   // run alongside them.
+	conflictPolicyReject  = "reject"  // do not start.
+	conflictPolicyPreempt = "preempt" // stop them when they have a lower priority, else do not start.
+)
+
+// Resolutions of a conflict, as reported in models.ShowConflict.
+const (
+	conflictShared    = "shared"
+	conflictPreempted = "preempted"
+	conflictRejected  = "rejected"
+)
+
+// checkConflictPolicy validates the conflict policy of a show. Shows without one share devices.
+func checkConflictPolicy(policy string) error {
+	switch policy {
+	case "", conflictPolicyShare, conflictPolicyReject, conflictPolicyPreempt:
+		return nil
+	default:
+		return fmt.Errorf("%w: %q", errConflictPolicy, policy)
+	}
+}
+
+// showConflicts returns the shows, other than show itself, that are running and use any of the
+// devices of show, ordered by show ID.
+func (e Executor) showConflicts(show models.Show) ([]models.ShowConflict, map[int]*RunningShow) {
+	wanted := map[int]bool{}
+
+	for _, d := range showDevices(show) {
+		wanted[d.ID] = true
+	}
+
+	conflicts := []models.ShowConflict{}
+	running := map[int]*RunningShow{}
+
+	for _, rs := range e.runner.Running() {
+		if rs.ShowID == show.ID {
+			continue
+		}
+
+		devices := []string{}
+
+		for _, d := range showDevices(rs.Show) {
+			if wanted[d.ID] {
+				devices = append(devices, d.Name)
+			}
+		}
+
+		if len(devices) == 0 {
+			continue
+		}
+
+		conflicts = append(conflicts, models.ShowConflict{
+			ShowID:   rs.ShowID,
+			Name:     rs.Show.Name,
+			Priority: rs.Show.Priority,
+			Devices:  devices,
+		})
+		running[rs.ShowID] = rs
+	}
+
+	sort.Slice(conflicts, func(i, j int) bool {
+		return conflicts[i].ShowID < conflicts[j].ShowID
+	})
+
+	return conflicts, running
+}
+
+// resolveConflicts applies the conflict policy of a show that is about to start. It returns the
+// conflicts with how each was resolved, and errShowConflict when the show must not start. Only
+// the conflicts that keep the show from starting are returned with the error.
+func (e Executor) resolveConflicts(show models.Show) ([]models.ShowConflict, error) {
+	conflicts, running := e.showConflicts(show)
+	if len(conflicts) == 0 {
+		return conflicts, nil
+	}
+
+	blocking := []models.ShowConflict{}
+
+	for _, c := range conflicts {
+		if show.ConflictPolicy == conflictPolicyReject ||
+			(show.ConflictPolicy == conflictPolicyPreempt && c.Priority >= show.Priority) {
+			c.Resolution = conflictRejected
+			blocking = append(blocking, c)
+		}
+	}
+
+	if len(blocking) > 0 {
+		names := make([]string, 0, len(blocking))
+
+		for _, c := range blocking {
+			names = append(names, c.Name)
+		}
+
+		return blocking, fmt.Errorf("%w: %v", errShowConflict, strings.Join(names, ", "))
+	}
+
+	for i, c := range conflicts {
+		if show.ConflictPolicy != conflictPolicyPreempt {
+			conflicts[i].Resolution = conflictShared
+
+			log.Warnf("Show %v: sharing %v with show %v", show.Name, strings.Join(c.Devices, ", "), c.Name)
+
+			continue
+		}
+
+		conflicts[i].Resolution = conflictPreempted
+
+		log.Infof("Show %v: preempting show %v", show.Name, c.Name)
+
+		if err := e.StopShow(c.ShowID); err != nil {
+			log.Error(err.Error())
+
+			continue
+		}
+
+		// let the stopped show restore its lights before this show takes their state.
+		select {
+		case <-running[c.ShowID].done:
+		case <-time.After(stopWait):
+		}
+	}
+
+	return conflicts, nil
+}
@@ -0,0 +1,123 @@
+package main_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	main "github.com/lovesway/hassio-addons/mq-lightshow"
+	"github.com/lovesway/hassio-addons/mq-lightshow/models"
+)
+
+// conflictShow returns a show with one action on the devices with the given IDs.
+func conflictShow(id int, priority int, policy string, deviceIDs ...int) models.Show {
+	devices := []models.Device{}
+
+	for _, d := range deviceIDs {
+		devices = append(devices, models.Device{ID: d, Name: fmt.Sprintf("d%v", d)})
+	}
+
+	return models.Show{
+		ID:             id,
+		Name:           fmt.Sprintf("show%v", id),
+		Priority:       priority,
+		ConflictPolicy: policy,
+		Cycles: []models.Cycle{{
+			Scene: models.Scene{Groups: []models.Group{{
+				Actions: []models.Action{{Devices: devices}},
+			}}},
+		}},
+	}
+}
+
+func TestCheckConflictPolicy(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in  string
+		err bool
+	}{
+		{in: ""},
+		{in: "share"},
+		{in: "reject"},
+		{in: "preempt"},
+		{in: "Share", err: true},
+		{in: "stop", err: true},
+	}
+
+	for _, tt := range tests {
+		err := main.CheckConflictPolicy(tt.in)
+		if (err != nil) != tt.err {
+			t.Errorf("CheckConflictPolicy(%q) error = %v, want error %v", tt.in, err, tt.err)
+		}
+	}
+}
+
+func TestResolveConflicts(t *testing.T) {
+	t.Parallel()
+
+	e := main.NewTestExecutor()
+
+	// show1 has a high priority and show2 a low one.
+	for _, show := range []models.Show{conflictShow(1, 5, "share", 1, 2), conflictShow(2, 1, "share", 3)} {
+		if _, ok := e.Runner().Start(show); !ok {
+			t.Fatalf("Start(%v) = false, want true", show.Name)
+		}
+	}
+
+	shared1 := models.ShowConflict{ShowID: 1, Name: "show1", Priority: 5, Devices: []string{"d2"}}
+	shared2 := models.ShowConflict{ShowID: 2, Name: "show2", Priority: 1, Devices: []string{"d3"}}
+	rejected1, rejected2 := shared1, shared2
+	shared1.Resolution, shared2.Resolution = "shared", "shared"
+	rejected1.Resolution, rejected2.Resolution = "rejected", "rejected"
+
+	tests := []struct {
+		name string
+		show models.Show
+		want []models.ShowConflict
+		err  bool
+	}{
+		{name: "no conflict", show: conflictShow(3, 0, "reject", 4), want: []models.ShowConflict{}},
+		{name: "share by default", show: conflictShow(3, 0, "", 2, 3, 4), want: []models.ShowConflict{shared1, shared2}},
+		{name: "share", show: conflictShow(3, 0, "share", 2, 3), want: []models.ShowConflict{shared1, shared2}},
+		{
+			name: "reject",
+			show: conflictShow(3, 9, "reject", 2, 3),
+			want: []models.ShowConflict{rejected1, rejected2},
+			err:  true,
+		},
+		// only the shows that keep it from starting are returned.
+		{
+			name: "preempt a higher priority",
+			show: conflictShow(3, 3, "preempt", 2, 3),
+			want: []models.ShowConflict{rejected1},
+			err:  true,
+		},
+		{
+			name: "preempt the same priority",
+			show: conflictShow(3, 1, "preempt", 3),
+			want: []models.ShowConflict{rejected2},
+			err:  true,
+		},
+		// a running show does not conflict with itself.
+		{name: "itself", show: conflictShow(1, 5, "reject", 1, 2), want: []models.ShowConflict{}},
+	}
+
+	for _, tt := range tests {
+		got, err := main.ResolveConflicts(*e, tt.show)
+		if (err != nil) != tt.err || (err != nil && !errors.Is(err, main.ErrShowConflict)) {
+			t.Errorf("%v: error = %v, want error %v", tt.name, err, tt.err)
+		}
+
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%v: conflicts = %+v, want %+v", tt.name, got, tt.want)
+		}
+
+		for _, id := range []int{1, 2} {
+			if !e.IsShowRunning(id) {
+				t.Errorf("%v: show%v was stopped", tt.name, id)
+			}
+		}
+	}
+}
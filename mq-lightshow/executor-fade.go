@@ -0,0 +1,238 @@
+package main
+
+import (
+	"context"
+	"math"
+	"sync"
+
+	"github.com/lovesway/hassio-addons/mq-lightshow/colors"
+	"github.com/lovesway/hassio-addons/mq-lightshow/devicetypes"
+	"github.com/lovesway/hassio-addons/mq-lightshow/models"
+)
+
+// Easing curves of group fades, the empty easing is linear.
+const (
+	fadeEasingLinear    = "linear"
+	fadeEasingEaseInOut = "ease-in-out"
+	fadeEasingSine      = "sine"
+
+	defaultFadeRate = 10 // steps per second.
+	maxFadeRate     = 50
+)
+
+// deviceColors remembers the last color sent to each device, fades start from it.
+type deviceColors struct {
+	mu     sync.Mutex
+	colors map[int]colors.HSB // keyed by device id.
+}
+
+func newDeviceColors() *deviceColors {
+	return &deviceColors{colors: map[int]colors.HSB{}}
+}
+
+func (d *deviceColors) get(deviceID int) (colors.HSB, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	c, ok := d.colors[deviceID]
+
+	return c, ok
+}
+
+func (d *deviceColors) set(deviceID int, c colors.HSB) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.colors[deviceID] = c
+}
+
+// fadeTarget is a device fading from one color to another.
+type fadeTarget struct {
+	device models.Device
+	from   colors.HSB
+	to     colors.HSB
+}
+
+// runFadeGroup runs the actions of a group with a fade. Actions that set a color are faded to
+// it together by publishing the colors in between, starting from the last color sent to each
+// device, or from black when there is none. The other actions are run as usual first. Devices
+// whose type cannot set a color are reported once and left out of the fade.
+func (e Executor) runFadeGroup(ctx context.Context, rs *RunningShow, gbls globals, group models.Group) {
+	targets := []fadeTarget{}
+
+	for _, action := range group.Actions {
+		rs.waitIfPaused(ctx)
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		action = e.resolveAction(gbls, action)
+
+		to, ok := actionColor(action, action.Parameter)
+		if !ok {
+			e.sendAction(ctx, action)
+
+			continue
+		}
+
+		for _, device := range action.Devices {
+			if _, ok := colorCommand(device); !ok {
+				log.Warnf("Fade: %v cannot set a color, it is left out of the fade", device.Name)
+
+				continue
+			}
+
+			from, known := e.lastColors.get(device.ID)
+			if !known {
+				from = colors.HSB{H: to.H, S: to.S}
+			}
+
+			targets = append(targets, fadeTarget{device: device, from: from, to: to})
+		}
+	}
+
+	rate := group.FadeRate
+	if rate <= 0 {
+		rate = defaultFadeRate
+	}
+
+	steps := int(math.Ceil(float64(group.FadeDuration) * float64(rate)))
+	if steps < 1 {
+		steps = 1
+	}
+
+	interval := group.FadeDuration / float32(steps)
+
+	for step := 1; step <= steps && len(targets) > 0; step++ {
+		e.waitForSeconds(ctx, interval)
+		rs.waitIfPaused(ctx)
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		t := ease(group.FadeEasing, float64(step)/float64(steps))
+
+		for _, target := range targets {
+			e.sendColor(target.device, interpolateHSB(target.from, target.to, t))
+		}
+	}
+}
+
+// sendColor sends a color generated by a fade or an effect with the command of colorCommand.
+// Devices that cannot set a color are skipped, callers report them before they start.
+func (e Executor) sendColor(device models.Device, c colors.HSB) {
+	command, ok := colorCommand(device)
+	if !ok {
+		return
+	}
+
+	parameter := c.RGB().String()
+	if command == devicetypes.CommandHsbColor {
+		parameter = c.String()
+	}
+
+	err := e.ExecuteAction(device, command, parameter)
+	if err != nil {
+		log.Error(err.Error())
+	}
+}
+
+// colorCommand returns the command that sets the color of a device, HsbColor or else Color1. It
+// returns false when the type of the device has neither.
+func colorCommand(device models.Device) (string, bool) {
+	command := ""
+
+	for _, cmd := range device.Type.Commands {
+		switch cmd.Name {
+		case devicetypes.CommandHsbColor:
+			return cmd.Name, true
+		case devicetypes.CommandColor:
+			command = cmd.Name
+		}
+	}
+
+	return command, command != ""
+}
+
+// actionColor returns the color an action sets its devices to, if it sets one.
+func actionColor(action models.Action, parameter string) (colors.HSB, bool) {
+	if action.Type != models.ActionTypeColor {
+		return commandColor(action.Command, parameter)
+	}
+
+	state, err := devicetypes.NewLightState(action)
+	if err != nil || !state.HasColor {
+		return colors.HSB{}, false
+	}
+
+	c := state.Color.HSB()
+	if state.Brightness > 0 {
+		c.B = float64(state.Brightness)
+	}
+
+	return c, true
+}
+
+// commandColor returns the color a HsbColor or Color1 command sets.
+func commandColor(command string, parameter string) (colors.HSB, bool) {
+	switch command {
+	case devicetypes.CommandHsbColor:
+		c, err := colors.ParseHSB(parameter)
+
+		return c, err == nil
+	case devicetypes.CommandColor:
+		c, err := colors.ParseRGB(parameter)
+
+		return c.HSB(), err == nil
+	}
+
+	return colors.HSB{}, false
+}
+
+// ease maps the progress of a fade in time to the progress of its color.
+func ease(easing string, t float64) float64 {
+	const half, double, cubic = 0.5, 2.0, 3.0
+
+	switch easing {
+	case fadeEasingEaseInOut:
+		if t < half {
+			return math.Pow(double*t, cubic) / double
+		}
+
+		return 1 - math.Pow(double-double*t, cubic)/double
+	case fadeEasingSine:
+		return (1 - math.Cos(math.Pi*t)) / double
+	}
+
+	return t
+}
+
+// interpolateHSB returns the color part t of the way from one color to another. The hue takes
+// the shorter way around the color wheel, and a white or black has no hue of its own so it
+// takes the hue of the other color.
+func interpolateHSB(from colors.HSB, to colors.HSB, t float64) colors.HSB {
+	const maxHue, halfHue = 360.0, 180.0
+
+	if from.S == 0 || from.B == 0 {
+		from.H = to.H
+	} else if to.S == 0 || to.B == 0 {
+		to.H = from.H
+	}
+
+	d := to.H - from.H
+	if d > halfHue {
+		d -= maxHue
+	} else if d < -halfHue {
+		d += maxHue
+	}
+
+	h := math.Mod(from.H+d*t+maxHue, maxHue)
+
+	return colors.HSB{
+		H: h,
+		S: from.S + (to.S-from.S)*t,
+		B: from.B + (to.B-from.B)*t,
+	}
+}
@@ -0,0 +1,113 @@
+package main_test
+
+import (
+	"math"
+	"testing"
+
+	main "github.com/lovesway/hassio-addons/mq-lightshow"
+	"github.com/lovesway/hassio-addons/mq-lightshow/colors"
+	"github.com/lovesway/hassio-addons/mq-lightshow/models"
+)
+
+const tolerance = 0.001
+
+func TestEase(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		easing string
+		t      float64
+		want   float64
+	}{
+		{easing: "", t: 0.25, want: 0.25},
+		{easing: "linear", t: 0.75, want: 0.75},
+		{easing: "bounce", t: 0.3, want: 0.3},
+		{easing: "ease-in-out", t: 0, want: 0},
+		{easing: "ease-in-out", t: 0.25, want: 0.0625},
+		{easing: "ease-in-out", t: 0.5, want: 0.5},
+		{easing: "ease-in-out", t: 0.75, want: 0.9375},
+		{easing: "ease-in-out", t: 1, want: 1},
+		{easing: "sine", t: 0, want: 0},
+		{easing: "sine", t: 0.25, want: 0.1464},
+		{easing: "sine", t: 0.5, want: 0.5},
+		{easing: "sine", t: 1, want: 1},
+	}
+
+	for _, tt := range tests {
+		if got := main.Ease(tt.easing, tt.t); math.Abs(got-tt.want) > tolerance {
+			t.Errorf("ease(%q, %v) = %v, want %v", tt.easing, tt.t, got, tt.want)
+		}
+	}
+}
+
+func TestInterpolateHSB(t *testing.T) {
+	t.Parallel()
+
+	red := colors.HSB{H: 0, S: 100, B: 100}
+	dimGreen := colors.HSB{H: 120, S: 50, B: 50}
+
+	tests := []struct {
+		name string
+		from colors.HSB
+		to   colors.HSB
+		t    float64
+		want colors.HSB
+	}{
+		{name: "start", from: red, to: dimGreen, t: 0, want: red},
+		{name: "end", from: red, to: dimGreen, t: 1, want: dimGreen},
+		{name: "half way", from: red, to: dimGreen, t: 0.5, want: colors.HSB{H: 60, S: 75, B: 75}},
+		// the hue takes the shorter way around the color wheel.
+		{name: "over red upwards", from: colors.HSB{H: 350, S: 100, B: 100}, to: colors.HSB{H: 10, S: 100, B: 100},
+			t: 0.5, want: red},
+		{name: "over red downwards", from: colors.HSB{H: 20, S: 100, B: 100}, to: colors.HSB{H: 340, S: 100, B: 100},
+			t: 0.25, want: colors.HSB{H: 10, S: 100, B: 100}},
+		// black and white take the hue of the other color.
+		{name: "from black", from: colors.HSB{}, to: colors.HSB{H: 240, S: 100, B: 100}, t: 0.5,
+			want: colors.HSB{H: 240, S: 50, B: 50}},
+		{name: "to white", from: colors.HSB{H: 120, S: 100, B: 100}, to: colors.HSB{B: 100}, t: 0.5,
+			want: colors.HSB{H: 120, S: 50, B: 100}},
+	}
+
+	for _, tt := range tests {
+		got := main.InterpolateHSB(tt.from, tt.to, tt.t)
+		if math.Abs(got.H-tt.want.H) > tolerance || math.Abs(got.S-tt.want.S) > tolerance ||
+			math.Abs(got.B-tt.want.B) > tolerance {
+			t.Errorf("%v: interpolateHSB = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestActionColor(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		action models.Action
+		want   colors.HSB
+		ok     bool
+	}{
+		{name: "HsbColor", action: models.Action{Command: "HsbColor", Parameter: "120,50,80"},
+			want: colors.HSB{H: 120, S: 50, B: 80}, ok: true},
+		{name: "Color1", action: models.Action{Command: "Color1", Parameter: "#FF0000"},
+			want: colors.HSB{H: 0, S: 100, B: 100}, ok: true},
+		{name: "invalid color", action: models.Action{Command: "Color1", Parameter: "red"}},
+		{name: "not a color", action: models.Action{Command: "Dimmer", Parameter: "50"}},
+		{name: "color action", action: models.Action{Type: models.ActionTypeColor, Color: "#0000FF", Brightness: 40},
+			want: colors.HSB{H: 240, S: 100, B: 40}, ok: true},
+		{name: "color action without a color", action: models.Action{Type: models.ActionTypeColor, CT: 300}},
+	}
+
+	for _, tt := range tests {
+		got, ok := main.ActionColor(tt.action, tt.action.Parameter)
+		if ok != tt.ok {
+			t.Errorf("%v: actionColor ok = %v, want %v", tt.name, ok, tt.ok)
+
+			continue
+		}
+
+		if ok && (math.Abs(got.H-tt.want.H) > tolerance || math.Abs(got.S-tt.want.S) > tolerance ||
+			math.Abs(got.B-tt.want.B) > tolerance) {
+			t.Errorf("%v: actionColor = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
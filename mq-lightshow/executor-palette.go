@@ -0,0 +1,96 @@
+package main
+
+import (
+	"strconv"
+	"sync"
+
+	"github.com/lovesway/hassio-addons/mq-lightshow/colors"
+	"github.com/lovesway/hassio-addons/mq-lightshow/devicetypes"
+	"github.com/lovesway/hassio-addons/mq-lightshow/models"
+)
+
+// showPalette is the palette of a running show, it keeps track of the next palette color.
+type showPalette struct {
+	mu     sync.Mutex
+	colors []colors.RGB
+	next   int
+}
+
+func newShowPalette(palette models.Palette) *showPalette {
+	p := &showPalette{}
+
+	for _, c := range palette.Colors {
+		rgb, err := colors.ParseRGB(c)
+		if err != nil {
+			log.Errorf("Palette %v: %v", palette.Name, err)
+
+			continue
+		}
+
+		p.colors = append(p.colors, rgb)
+	}
+
+	return p
+}
+
+// color returns the palette color an action refers to. Numbers past the last color wrap around,
+// so palettes with fewer colors can be swapped in.
+func (p *showPalette) color(ref string) (colors.RGB, bool) {
+	if p == nil || len(p.colors) == 0 {
+		return colors.RGB{}, false
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if ref == models.PaletteColorNext {
+		c := p.colors[p.next%len(p.colors)]
+		p.next++
+
+		return c, true
+	}
+
+	n, err := strconv.Atoi(ref)
+	if err != nil || n < 1 {
+		return colors.RGB{}, false
+	}
+
+	return p.colors[(n-1)%len(p.colors)], true
+}
+
+// resolveAction returns an action as it is sent, with the global parameter or the palette color
+// it uses filled in.
+func (e Executor) resolveAction(gbls globals, action models.Action) models.Action {
+	action.Parameter = e.actionParameter(gbls, action)
+
+	if action.PaletteColor == "" {
+		return action
+	}
+
+	c, ok := gbls.Palette.color(action.PaletteColor)
+	if !ok {
+		return action
+	}
+
+	if action.Type == models.ActionTypeColor {
+		action.Color, action.CT = c.Hex(), 0
+	} else {
+		action.Parameter = paletteParameter(action, c)
+	}
+
+	return action
+}
+
+// paletteParameter formats a palette color for the command of an action, as hue, saturation
+// and brightness for commands that take them.
+func paletteParameter(action models.Action, c colors.RGB) string {
+	for _, device := range action.Devices {
+		for _, cmd := range device.Type.Commands {
+			if cmd.Name == action.Command && devicetypes.ParameterType(cmd) == devicetypes.ParameterHSB {
+				return c.HSB().String()
+			}
+		}
+	}
+
+	return c.String()
+}
@@ -0,0 +1,116 @@
+package main_test
+
+import (
+	"testing"
+
+	main "github.com/lovesway/hassio-addons/mq-lightshow"
+	"github.com/lovesway/hassio-addons/mq-lightshow/colors"
+	"github.com/lovesway/hassio-addons/mq-lightshow/devicetypes"
+	"github.com/lovesway/hassio-addons/mq-lightshow/models"
+)
+
+func TestShowPaletteColor(t *testing.T) {
+	t.Parallel()
+
+	red, green, blue := colors.RGB{R: 255}, colors.RGB{G: 255}, colors.RGB{B: 255}
+
+	// the color that does not parse is left out.
+	palette := main.NewShowPalette(models.Palette{Name: "rgb", Colors: []string{"#FF0000", "green", "#00FF00", "#0000FF"}})
+
+	tests := []struct {
+		ref  string
+		want colors.RGB
+		ok   bool
+	}{
+		{ref: "1", want: red, ok: true},
+		{ref: "3", want: blue, ok: true},
+		// numbers past the last color wrap around.
+		{ref: "4", want: red, ok: true},
+		{ref: "next", want: red, ok: true},
+		{ref: "next", want: green, ok: true},
+		{ref: "2", want: green, ok: true},
+		{ref: "next", want: blue, ok: true},
+		{ref: "next", want: red, ok: true},
+		{ref: "0"},
+		{ref: "first"},
+	}
+
+	for i, tt := range tests {
+		got, ok := main.PaletteColor(palette, tt.ref)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("step %v: color(%q) = %v, %v, want %v, %v", i, tt.ref, got, ok, tt.want, tt.ok)
+		}
+	}
+
+	if _, ok := main.PaletteColor(nil, "1"); ok {
+		t.Errorf("a show without a palette has colors")
+	}
+}
+
+func TestResolveAction(t *testing.T) {
+	t.Parallel()
+
+	palette := main.NewShowPalette(models.Palette{Name: "orange", Colors: []string{"#FF8000"}})
+
+	hsbDevice := models.Device{Type: models.DeviceType{Commands: []models.Command{
+		{Name: devicetypes.CommandHsbColor, Type: devicetypes.ParameterHSB},
+	}}}
+	rgbDevice := models.Device{Type: models.DeviceType{Commands: []models.Command{
+		{Name: devicetypes.CommandColor, Type: devicetypes.ParameterColor},
+	}}}
+
+	tests := []struct {
+		name          string
+		palette       *main.ShowPalette
+		action        models.Action
+		wantParameter string
+		wantColor     string
+		wantCT        int
+	}{
+		{
+			name:    "rgb command",
+			palette: palette,
+			action: models.Action{
+				Command:      devicetypes.CommandColor,
+				PaletteColor: "1",
+				Devices:      []models.Device{rgbDevice},
+			},
+			wantParameter: "255,128,0",
+		},
+		{
+			name:    "hsb command",
+			palette: palette,
+			action: models.Action{
+				Command:      devicetypes.CommandHsbColor,
+				PaletteColor: "1",
+				Devices:      []models.Device{hsbDevice},
+			},
+			wantParameter: "30,100,100",
+		},
+		{
+			name:      "color action",
+			palette:   palette,
+			action:    models.Action{Type: models.ActionTypeColor, CT: 300, PaletteColor: "next"},
+			wantColor: "#FF8000",
+		},
+		{
+			name:          "show without a palette",
+			action:        models.Action{Command: devicetypes.CommandColor, Parameter: "#0000FF", PaletteColor: "1"},
+			wantParameter: "#0000FF",
+		},
+		{
+			name:          "no palette color",
+			palette:       palette,
+			action:        models.Action{Command: devicetypes.CommandColor, Parameter: "#0000FF"},
+			wantParameter: "#0000FF",
+		},
+	}
+
+	for _, tt := range tests {
+		got := main.ResolveAction(tt.palette, tt.action)
+		if got.Parameter != tt.wantParameter || got.Color != tt.wantColor || got.CT != tt.wantCT {
+			t.Errorf("%v: resolveAction = %q, %q, %v, want %q, %q, %v", tt.name,
+				got.Parameter, got.Color, got.CT, tt.wantParameter, tt.wantColor, tt.wantCT)
+		}
+	}
+}
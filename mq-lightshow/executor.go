@@ -1,28 +1,51 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"strconv"
 	"time"
 
+	"github.com/lovesway/hassio-addons/mq-lightshow/devicetypes"
 	"github.com/lovesway/hassio-addons/mq-lightshow/models"
 )
 
+var (
+	errShowNotRunning = errors.New("show not running")
+	errShowPaused     = errors.New("show already paused")
+	errShowNotPaused  = errors.New("show not paused")
+	errCycleNotInShow = errors.New("cycle is not part of the running show")
+)
+
+// What to do about devices that reported themselves offline when a show starts.
+const (
+	offlinePolicyIgnore = "ignore"
+	offlinePolicyWarn   = "warn"
+	offlinePolicySkip   = "skip"
+)
+
+// stopWait is how long shutting down waits for stopped shows to restore their lights.
+const stopWait = 5 * time.Second
+
 // Executor represents the controller for the UI.
 type Executor struct {
-	md    Modeler
-	mq    *MQController
-	gblsZ globals // globals with zero value for usage in comparisons.
+	md            Modeler
+	mq            *MQController
+	runner        *ShowRunner
+	lastColors    *deviceColors
+	offlinePolicy string  // one of the offlinePolicy constants.
+	gblsZ         globals // globals with zero value for usage in comparisons.
 }
 
-// Shows tracks all instances of running shows.
-var Shows []Running
-
 // NewExecutor provides an instance of Executor.
-func NewExecutor(md Modeler, mq *MQController) *Executor {
+func NewExecutor(md Modeler, mq *MQController, offlinePolicy string) *Executor {
 	return &Executor{
-		md: md,
-		mq: mq,
+		md:            md,
+		mq:            mq,
+		runner:        NewShowRunner(),
+		lastColors:    newDeviceColors(),
+		offlinePolicy: offlinePolicy,
 	}
 }
 
@@ -35,23 +58,76 @@ func (e Executor) ExecuteActionByID(actionID int) {
 		return
 	}
 
+	action.Devices = zoneDevices(action.Devices, action.Zones, e.md.GetDevices())
+
+	if action.Type == models.ActionTypeColor {
+		e.executeColorAction(action)
+
+		return
+	}
+
+	for _, device := range action.Devices {
+		err = e.ExecuteAction(device, action.Command, action.Parameter)
+		if err != nil {
+			log.Error(err.Error())
+		}
+	}
+}
+
+// executeColorAction to convert a color action into the messages of each device type.
+func (e Executor) executeColorAction(action models.Action) {
+	state, err := devicetypes.NewLightState(action)
+	if err != nil {
+		log.Error(err.Error())
+
+		return
+	}
+
 	for _, device := range action.Devices {
-		e.ExecuteAction(device.Topic, action.Command, action.Parameter)
+		err = e.mq.SendLight(device, state)
+		if err != nil {
+			log.Error(err.Error())
+
+			continue
+		}
+
+		if c, ok := actionColor(action, ""); ok {
+			e.lastColors.set(device.ID, c)
+		}
 	}
 }
 
 // ExecuteAction to send an action to MQTT.
-func (e Executor) ExecuteAction(topic string, command string, parameter string) {
-	e.mq.SendAction(topic, command, parameter)
+func (e Executor) ExecuteAction(device models.Device, command string, parameter string) error {
+	err := e.mq.SendAction(device, command, parameter)
+	if err != nil {
+		return err
+	}
+
+	if c, ok := commandColor(command, parameter); ok {
+		e.lastColors.set(device.ID, c)
+	}
+
+	return nil
 }
 
-func (e Executor) waitForSeconds(secondsFloat float32) {
+// waitForSeconds sleeps for the given seconds, returning early when ctx is cancelled.
+func (e Executor) waitForSeconds(ctx context.Context, secondsFloat float32) {
 	const oneThousand = 1000
 	duration := time.Duration(int64(secondsFloat * oneThousand))
 
 	delay := duration * time.Millisecond
+	if delay <= 0 {
+		return
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
 
-	time.Sleep(delay)
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
 }
 
 // ExecuteActionGroupByID to send a group of actions to MQTT.
@@ -76,7 +152,7 @@ func (e Executor) ExecuteActionGroupByID(groupID int) {
 		e.ExecuteActionByID(a.ID)
 	}
 
-	e.waitForSeconds(g.Delay)
+	e.waitForSeconds(context.Background(), g.Delay)
 }
 
 // ExecuteSceneByID to send a Scene to MQTT.
@@ -100,9 +176,20 @@ type globals struct {
 	Speed      int
 	Parameter1 string
 	Parameter2 string
+	Palette    *showPalette
 }
 
-func (e Executor) runShow(show models.Show) {
+func (e Executor) runShow(rs *RunningShow, show models.Show) {
+	ctx := rs.ctx
+
+	defer e.runner.Done(rs)
+
+	// the lights are set back to how they were before the show, whether it finished or was stopped.
+	devices := showDevices(show)
+	snapshot := e.mq.SnapshotLights(ctx, devices)
+
+	defer e.mq.RestoreLights(devices, snapshot)
+
 	// check for show globals.
 	var gbls globals
 	if show.GlobalDelay != e.gblsZ.Delay {
@@ -121,196 +208,468 @@ func (e Executor) runShow(show models.Show) {
 		gbls.Parameter2 = show.GlobalParameter2
 	}
 
+	if show.PaletteID != 0 {
+		gbls.Palette = newShowPalette(show.Palette)
+	}
+
 	looping := false
+	ranThisPass := false
+	jumped := false
 
-	for {
-		for _, cycle := range show.Cycles {
-			if !e.IsShowRunning(show.ID) {
-				return
-			}
+	for i := 0; i < len(show.Cycles); {
+		cycle := show.Cycles[i]
 
-			if looping && !cycle.LoopInclude {
-				continue
-			}
+		// cycles excluded from the loop are still run when explicitly jumped to.
+		if looping && !cycle.LoopInclude && !jumped {
+			i = e.nextCycleIndex(rs, i, &looping, &ranThisPass)
 
-			// check for cycle globals.
-			cgbls := gbls
-			if cycle.GlobalDelay != e.gblsZ.Delay {
-				cgbls.Delay = cycle.GlobalDelay
-			}
+			continue
+		}
 
-			if cycle.GlobalSpeed != e.gblsZ.Speed {
-				cgbls.Speed = cycle.GlobalSpeed
-			}
+		ranThisPass = true
+		jumped = false
+		cctx := rs.beginCycle(i)
 
-			if cycle.GlobalParameter1 != e.gblsZ.Parameter1 {
-				cgbls.Parameter1 = cycle.GlobalParameter1
-			}
+		// check for cycle globals.
+		cgbls := gbls
+		if cycle.GlobalDelay != e.gblsZ.Delay {
+			cgbls.Delay = cycle.GlobalDelay
+		}
 
-			if cycle.GlobalParameter2 != e.gblsZ.Parameter2 {
-				cgbls.Parameter2 = cycle.GlobalParameter2
-			}
+		if cycle.GlobalSpeed != e.gblsZ.Speed {
+			cgbls.Speed = cycle.GlobalSpeed
+		}
 
-			for i := 1; i <= cycle.SceneCycles; i++ {
-				e.runScene(show.ID, cgbls, cycle.Scene)
+		if cycle.GlobalParameter1 != e.gblsZ.Parameter1 {
+			cgbls.Parameter1 = cycle.GlobalParameter1
+		}
 
-				if !e.IsShowRunning(show.ID) {
-					return
-				}
-			}
+		if cycle.GlobalParameter2 != e.gblsZ.Parameter2 {
+			cgbls.Parameter2 = cycle.GlobalParameter2
+		}
 
-			if cycle.EndDelay > 0 {
-				time.Sleep(time.Duration(cycle.EndDelay) * time.Second)
+		e.publishProgress(rs)
+
+		for c := 1; c <= cycle.SceneCycles && cctx.Err() == nil; c++ {
+			rs.beginSceneCycle(c)
+
+			if cycle.EffectID != 0 {
+				e.runEffect(cctx, rs, cycle.Effect)
+			} else {
+				e.runScene(cctx, rs, cgbls, cycle.Scene)
 			}
 		}
 
-		if !show.Repeat {
-			break
+		e.waitForSeconds(cctx, cycle.EndDelay)
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		if jump, ok := rs.takeJump(); ok {
+			i = jump
+			jumped = true
+
+			continue
 		}
 
-		looping = true
+		i = e.nextCycleIndex(rs, i, &looping, &ranThisPass)
+	}
+
+	if e.runner.Finish(rs) {
+		log.Infof("Show Finished: %v", show.Name)
+
+		e.mq.SendShowState(show.Topic, "OFF")
+		e.publishProgress(rs)
 	}
+}
 
-	if err := e.StopShow(show.ID); err != nil {
-		log.Error(err)
+// nextCycleIndex returns the index of the cycle following i, wrapping around when the show repeats.
+// It returns len(show.Cycles) when the show is complete.
+func (e Executor) nextCycleIndex(rs *RunningShow, i int, looping *bool, ranThisPass *bool) int {
+	show := rs.Show
+
+	i++
+	if i < len(show.Cycles) || !show.Repeat {
+		return i
 	}
+
+	// a repeating show where no cycle is included in the loop would otherwise spin forever.
+	if *looping && !*ranThisPass {
+		log.Warnf("Show %v has no cycles included in its loop", show.Name)
+
+		return len(show.Cycles)
+	}
+
+	*looping = true
+	*ranThisPass = false
+
+	rs.beginLoop()
+
+	return 0
 }
 
-func (e Executor) runScene(showID int, gbls globals, scene models.Scene) {
+func (e Executor) runScene(ctx context.Context, rs *RunningShow, gbls globals, scene models.Scene) {
 	for _, group := range scene.Groups {
-		if !e.IsShowRunning(showID) {
+		if ctx.Err() != nil {
 			return
 		}
 
-		e.runActionGroup(showID, gbls, group)
+		e.runActionGroup(ctx, rs, gbls, group)
 	}
 }
 
-func (e Executor) runActionGroup(showID int, gbls globals, group models.Group) {
-	for _, action := range group.Actions {
-		e.runAction(showID, gbls, action)
+func (e Executor) runActionGroup(ctx context.Context, rs *RunningShow, gbls globals, group models.Group) {
+	rs.beginGroup(group.Order)
+	e.publishProgress(rs)
 
-		if !e.IsShowRunning(showID) {
-			return
+	if group.FadeDuration > 0 {
+		e.runFadeGroup(ctx, rs, gbls, group)
+	} else {
+		for _, action := range group.Actions {
+			rs.waitIfPaused(ctx)
+
+			if ctx.Err() != nil {
+				return
+			}
+
+			e.runAction(ctx, gbls, action)
 		}
 	}
 
 	if group.GlobalDelay && gbls.Delay != e.gblsZ.Delay {
-		e.waitForSeconds(gbls.Delay)
+		e.waitForSeconds(ctx, gbls.Delay)
 	} else {
-		e.waitForSeconds(group.Delay)
+		e.waitForSeconds(ctx, group.Delay)
 	}
 }
 
-func (e Executor) runAction(showID int, gbls globals, action models.Action) {
+func (e Executor) runAction(ctx context.Context, gbls globals, action models.Action) {
+	e.sendAction(ctx, e.resolveAction(gbls, action))
+}
+
+// sendAction sends an action resolved with resolveAction to its devices.
+func (e Executor) sendAction(ctx context.Context, action models.Action) {
+	if action.Type == models.ActionTypeColor {
+		if ctx.Err() == nil {
+			e.executeColorAction(action)
+		}
+
+		return
+	}
+
 	for _, d := range action.Devices {
-		if !e.IsShowRunning(showID) {
+		if ctx.Err() != nil {
 			return
 		}
 
-		// determine whether or not to use global parameters.
-		var parameter string
-		if action.GlobalParameter == globalSpeed && gbls.Speed != e.gblsZ.Speed {
-			parameter = strconv.Itoa(gbls.Speed)
-		} else if action.GlobalParameter == globalParameter1 && gbls.Parameter1 != e.gblsZ.Parameter1 {
-			parameter = gbls.Parameter1
-		} else if action.GlobalParameter == globalParameter2 && gbls.Parameter2 != e.gblsZ.Parameter2 {
-			parameter = gbls.Parameter2
-		} else {
-			parameter = action.Parameter
+		err := e.ExecuteAction(d, action.Command, action.Parameter)
+		if err != nil {
+			log.Error(err.Error())
 		}
-
-		e.ExecuteAction(d.Topic, action.Command, parameter)
 	}
 }
 
-// Running tracks an instance of a running show.
-type Running struct {
-	ShowID int
+// actionParameter determines whether or not to use global parameters.
+func (e Executor) actionParameter(gbls globals, action models.Action) string {
+	if action.GlobalParameter == globalSpeed && gbls.Speed != e.gblsZ.Speed {
+		return strconv.Itoa(gbls.Speed)
+	} else if action.GlobalParameter == globalParameter1 && gbls.Parameter1 != e.gblsZ.Parameter1 {
+		return gbls.Parameter1
+	} else if action.GlobalParameter == globalParameter2 && gbls.Parameter2 != e.gblsZ.Parameter2 {
+		return gbls.Parameter2
+	}
+
+	return action.Parameter
 }
 
 // IsShowRunning to determine whether or not a show is running.
 func (e Executor) IsShowRunning(showID int) bool {
-	for _, running := range Shows {
-		if showID == running.ShowID {
-			return true
-		}
-	}
-
-	return false
+	return e.runner.IsRunning(showID)
 }
 
 // StartShow to run a tracked show which can be stopped.
 func (e Executor) StartShow(showID int) error {
-	var err error
+	_, err := e.StartShowConflicts(showID)
+
+	return err
+}
 
-	if e.IsShowRunning(showID) {
-		return fmt.Errorf("Show already running for showID: %v", showID)
+// StartShowConflicts runs a show like StartShow, applying its conflict policy to the running
+// shows that use its devices. It returns those shows and how each conflict was resolved.
+func (e Executor) StartShowConflicts(showID int) ([]models.ShowConflict, error) {
+	if !e.mq.IsConnected() {
+		return nil, errMQTTNotConnected
 	}
 
 	show, err := e.md.GetShowRecursive(showID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	log.Infof("Starting Show: %v", show.Name)
+	if e.runner.IsRunning(showID) {
+		return nil, fmt.Errorf("Show already running for showID: %v", showID)
+	}
 
-	go func() {
-		e.runShow(show)
-	}()
+	show = e.expandZones(show)
+	show = e.checkOfflineDevices(show)
 
-	Shows = append(Shows, Running{ShowID: showID})
+	conflicts, err := e.resolveConflicts(show)
+	if err != nil {
+		return conflicts, err
+	}
+
+	rs, ok := e.runner.Start(show)
+	if !ok {
+		return conflicts, fmt.Errorf("Show already running for showID: %v", showID)
+	}
+
+	log.Infof("Starting Show: %v", show.Name)
 
 	e.mq.SendShowState(show.Topic, "ON")
 
-	return err
+	go e.runShow(rs, show)
+
+	return conflicts, nil
 }
 
-// StopShow to stop a running show.
-func (e Executor) StopShow(showID int) error {
-	var err error
+// checkOfflineDevices applies the offline device policy to a show that is about to start.
+// With the skip policy, actions are no longer sent to offline devices for this run of the show.
+func (e Executor) checkOfflineDevices(show models.Show) models.Show {
+	if e.offlinePolicy != offlinePolicyWarn && e.offlinePolicy != offlinePolicySkip {
+		return show
+	}
+
+	offline := map[int]string{}
+
+	for i, cycle := range show.Cycles {
+		devices := []models.Device{}
+
+		for _, device := range cycle.Effect.Devices {
+			if e.mq.IsDeviceOffline(device.ID) {
+				offline[device.ID] = device.Name
+
+				if e.offlinePolicy == offlinePolicySkip {
+					continue
+				}
+			}
+
+			devices = append(devices, device)
+		}
+
+		show.Cycles[i].Effect.Devices = devices
+
+		for j, group := range cycle.Scene.Groups {
+			for k, action := range group.Actions {
+				devices := []models.Device{}
+
+				for _, device := range action.Devices {
+					if e.mq.IsDeviceOffline(device.ID) {
+						offline[device.ID] = device.Name
+
+						if e.offlinePolicy == offlinePolicySkip {
+							continue
+						}
+					}
+
+					devices = append(devices, device)
+				}
+
+				show.Cycles[i].Scene.Groups[j].Actions[k].Devices = devices
+			}
+		}
+	}
+
+	for _, name := range offline {
+		if e.offlinePolicy == offlinePolicySkip {
+			log.Warnf("Show %v: skipping offline device %v", show.Name, name)
+		} else {
+			log.Warnf("Show %v: device %v is offline", show.Name, name)
+		}
+	}
+
+	return show
+}
+
+// showDevices returns every device a show sends actions or effects to, each once.
+func showDevices(show models.Show) []models.Device {
+	devices := []models.Device{}
+	seen := map[int]bool{}
+
+	add := func(ds []models.Device) {
+		for _, d := range ds {
+			if !seen[d.ID] {
+				seen[d.ID] = true
+				devices = append(devices, d)
+			}
+		}
+	}
+
+	for _, cycle := range show.Cycles {
+		add(cycle.Effect.Devices)
+
+		for _, group := range cycle.Scene.Groups {
+			for _, action := range group.Actions {
+				add(action.Devices)
+			}
+		}
+	}
+
+	return devices
+}
 
+// StopShow to stop a running show. Any delay the show is waiting on is interrupted immediately.
+func (e Executor) StopShow(showID int) error {
 	show, err := e.md.GetShow(showID)
 	if err != nil {
 		return err
 	}
 
-	e.removeShowByIndex(e.getRunningShowIndex(showID))
+	if !e.runner.Stop(showID) {
+		return fmt.Errorf("%w for showID: %v", errShowNotRunning, showID)
+	}
 
 	log.Infof("Stopping Show: %v", show.Name)
 
 	e.mq.SendShowState(show.Topic, "OFF")
+	e.mq.SendShowProgress(show.Topic, models.ShowProgress{ShowID: show.ID, Name: show.Name})
 
 	return err
 }
 
-func (e Executor) removeShowByIndex(i int) {
-	if !e.showIndexExists(i) {
-		log.Error("no show exists for index %v", i)
+// StopAllShows to stop every running show, used when shutting down. It waits, for at most
+// stopWait, for the shows to set their lights back.
+func (e Executor) StopAllShows() {
+	timeout := time.NewTimer(stopWait)
+	defer timeout.Stop()
 
-		return
+	for _, rs := range e.runner.StopAll() {
+		log.Infof("Stopping Show: %v", rs.Show.Name)
+
+		select {
+		case <-rs.done:
+		case <-timeout.C:
+			return
+		}
 	}
+}
 
-	Shows[i] = Shows[len(Shows)-1]
-	Shows = Shows[:len(Shows)-1]
+// publishProgress sends the progress of a running show to its progress topic.
+func (e Executor) publishProgress(rs *RunningShow) {
+	e.mq.SendShowProgress(rs.Show.Topic, rs.Progress())
 }
 
-func (e Executor) getRunningShowIndex(showID int) int {
-	for i, show := range Shows {
-		if showID == show.ShowID {
-			return i
-		}
+// GetShowProgress returns where a show is. A show that is not running reports Running as false.
+func (e Executor) GetShowProgress(showID int) models.ShowProgress {
+	rs, ok := e.runner.Get(showID)
+	if !ok {
+		return models.ShowProgress{ShowID: showID}
 	}
 
-	return 0
+	return rs.Progress()
 }
 
-func (e Executor) showIndexExists(index int) bool {
-	for i := range Shows {
-		if i == index {
-			return true
+func (e Executor) getRunningShow(showID int) (*RunningShow, error) {
+	rs, ok := e.runner.Get(showID)
+	if !ok {
+		return nil, fmt.Errorf("%w for showID: %v", errShowNotRunning, showID)
+	}
+
+	return rs, nil
+}
+
+// IsShowPaused to determine whether or not a running show is paused.
+func (e Executor) IsShowPaused(showID int) bool {
+	rs, ok := e.runner.Get(showID)
+
+	return ok && rs.IsPaused()
+}
+
+// PauseShow to hold a running show on its current scene.
+func (e Executor) PauseShow(showID int) error {
+	rs, err := e.getRunningShow(showID)
+	if err != nil {
+		return err
+	}
+
+	if !rs.Pause() {
+		return fmt.Errorf("%w for showID: %v", errShowPaused, showID)
+	}
+
+	log.Infof("Pausing Show: %v", rs.Show.Name)
+
+	e.publishProgress(rs)
+
+	return nil
+}
+
+// ResumeShow to continue a paused show.
+func (e Executor) ResumeShow(showID int) error {
+	rs, err := e.getRunningShow(showID)
+	if err != nil {
+		return err
+	}
+
+	if !rs.Resume() {
+		return fmt.Errorf("%w for showID: %v", errShowNotPaused, showID)
+	}
+
+	log.Infof("Resuming Show: %v", rs.Show.Name)
+
+	e.publishProgress(rs)
+
+	return nil
+}
+
+// NextCycle to abandon the current cycle of a running show and continue with the next one.
+func (e Executor) NextCycle(showID int) error {
+	rs, err := e.getRunningShow(showID)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Skipping to next cycle in Show: %v", rs.Show.Name)
+
+	rs.Skip(noJump)
+
+	return nil
+}
+
+// PreviousCycle to abandon the current cycle of a running show and continue with the one before it.
+func (e Executor) PreviousCycle(showID int) error {
+	rs, err := e.getRunningShow(showID)
+	if err != nil {
+		return err
+	}
+
+	index := rs.Cycle() - 1
+	if index < 0 {
+		index = 0
+	}
+
+	log.Infof("Skipping to previous cycle in Show: %v", rs.Show.Name)
+
+	rs.Skip(index)
+
+	return nil
+}
+
+// GotoCycle to abandon the current cycle of a running show and continue with the given cycle.
+func (e Executor) GotoCycle(showID int, cycleID int) error {
+	rs, err := e.getRunningShow(showID)
+	if err != nil {
+		return err
+	}
+
+	for i, cycle := range rs.Show.Cycles {
+		if cycle.ID == cycleID {
+			log.Infof("Skipping to cycle %v in Show: %v", cycleID, rs.Show.Name)
+
+			rs.Skip(i)
+
+			return nil
 		}
 	}
 
-	return false
+	return fmt.Errorf("%w: %v", errCycleNotInShow, cycleID)
 }
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"errors"
+	"math/rand"
+	"os"
+	"testing"
+
+	MQTT "github.com/eclipse/paho.mqtt.golang"
+	"github.com/lovesway/hassio-addons/mq-lightshow/colors"
+	"github.com/lovesway/hassio-addons/mq-lightshow/models"
+	"go.uber.org/zap"
+)
+
+func TestMain(m *testing.M) {
+	setLogger(zap.NewNop().Sugar())
+	os.Exit(m.Run())
+}
+
+// NewTestExecutor returns an Executor that runs shows without a database or a broker.
+func NewTestExecutor() *Executor {
+	return &Executor{runner: NewShowRunner(), lastColors: newDeviceColors()}
+}
+
+// SetExecutor sets the Executor that the Modeler asks whether shows are running.
+func SetExecutor(e *Executor) {
+	ex = e
+}
+
+// Runner returns the ShowRunner of the Executor.
+func (e Executor) Runner() *ShowRunner {
+	return e.runner
+}
+
+// Stopped is closed when the run is stopped.
+func (rs *RunningShow) Stopped() <-chan struct{} {
+	return rs.ctx.Done()
+}
+
+// IsDone reports whether Done was called for the run.
+func (rs *RunningShow) IsDone() bool {
+	select {
+	case <-rs.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// Stopping returns the number of runs that ended and did not call Done yet.
+func (sr *ShowRunner) Stopping() int {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	return len(sr.stopping)
+}
+
+// ErrorField returns the request field an API validation error is about.
+func ErrorField(err error) string {
+	var fe fieldError
+	if errors.As(err, &fe) {
+		return fe.field
+	}
+
+	return ""
+}
+
+// Internals of RunningShow that the executor uses while it runs a show.
+var (
+	BeginCycle      = (*RunningShow).beginCycle
+	BeginSceneCycle = (*RunningShow).beginSceneCycle
+	BeginGroup      = (*RunningShow).beginGroup
+	BeginLoop       = (*RunningShow).beginLoop
+	TakeJump        = (*RunningShow).takeJump
+	WaitIfPaused    = (*RunningShow).waitIfPaused
+)
+
+// NoJump is the cycle index of a skip that continues with the next cycle.
+const NoJump = noJump
+
+// Options of the mqtt client.
+var (
+	MQTTQoS       = mqttQoS
+	MQTTKeepAlive = mqttKeepAlive
+	MQTTTLSConfig = mqttTLSConfig
+)
+
+// Colors of group fades.
+var (
+	ActionColor    = actionColor
+	Ease           = ease
+	InterpolateHSB = interpolateHSB
+)
+
+// Effects.
+var (
+	EffectPalette  = effectPalette
+	ValidateEffect = validateEffect
+)
+
+// EffectFrame returns the colors of the devices of an effect at a step.
+func EffectFrame(
+	effectType string, step int, devices int, palette []colors.HSB, rnd *rand.Rand,
+) (map[int]colors.HSB, bool) {
+	generate, ok := getEffectGenerator(effectType)
+	if !ok {
+		return nil, false
+	}
+
+	return generate(step, devices, palette, rnd), true
+}
+
+// Palettes.
+var (
+	NewShowPalette = newShowPalette
+	PaletteColor   = (*showPalette).color
+	DecodePalette  = decodePalette
+	ValidateAction = validateActionDevices
+)
+
+// ShowPalette is the palette of a running show.
+type ShowPalette = showPalette
+
+// ResolveAction returns an action as a show with a palette sends it.
+func ResolveAction(palette *showPalette, action models.Action) models.Action {
+	return Executor{}.resolveAction(globals{Palette: palette}, action)
+}
+
+// Zones.
+var (
+	ParseZones  = parseZones
+	SplitZones  = splitZones
+	ZoneDevices = zoneDevices
+	CheckZones  = checkZones
+)
+
+// Schedule matching, with the schedule times of newScheduleTimes.
+var (
+	NewScheduleTimes = newScheduleTimes
+	ScheduleActiveOn = scheduleActiveOn
+	ScheduleStarts   = scheduleTimes.starts
+	ScheduleStops    = scheduleTimes.stops
+	ScheduleInWindow = scheduleTimes.inWindow
+)
+
+// Conflicts between shows.
+var (
+	ErrShowConflict     = errShowConflict
+	CheckConflictPolicy = checkConflictPolicy
+	ResolveConflicts    = Executor.resolveConflicts
+)
+
+// SetClient replaces the mqtt client of the MQController.
+func (mqc *MQController) SetClient(c MQTT.Client) {
+	mqc.mc = c
+}
+
+// Backups.
+var (
+	UpgradeBackup = upgradeBackup
+	UniqueTopic   = uniqueTopic
+)
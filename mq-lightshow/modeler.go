@@ -27,6 +27,7 @@ func (md *Modeler) GetShows() ([]models.Show, error) {
 	for i, show := range shows {
 		s := &shows[i]
 		s.Running = ex.IsShowRunning(show.ID)
+		s.Paused = ex.IsShowPaused(show.ID)
 	}
 
 	return shows, err
@@ -60,6 +61,7 @@ func (md *Modeler) GetShow(showID int) (models.Show, error) {
 	}
 
 	show.Running = ex.IsShowRunning(show.ID)
+	show.Paused = ex.IsShowPaused(show.ID)
 
 	return show, err
 }
@@ -78,6 +80,13 @@ func (md *Modeler) GetShowRecursive(showID int) (models.Show, error) {
 
 	show.Cycles = cycles
 
+	if show.PaletteID != 0 {
+		show.Palette, err = md.GetPalette(show.PaletteID)
+		if err != nil {
+			return models.Show{}, err
+		}
+	}
+
 	return show, err
 }
 
@@ -89,6 +98,7 @@ func (md *Modeler) GetShowByTopic(topic string) (models.Show, error) {
 	}
 
 	show.Running = ex.IsShowRunning(show.ID)
+	show.Paused = ex.IsShowPaused(show.ID)
 
 	return show, err
 }
@@ -121,12 +131,24 @@ func (md *Modeler) GetShowCyclesRecursive(showID int) ([]models.Cycle, error) {
 	}
 
 	for i, cycle := range cycles {
+		c := &cycles[i]
+
+		if cycle.EffectID != 0 {
+			effect, err := md.GetEffect(cycle.EffectID)
+			if err != nil {
+				return []models.Cycle{}, err
+			}
+
+			c.Effect = effect
+
+			continue
+		}
+
 		scene, err := md.GetSceneRecursive(cycle.SceneID)
 		if err != nil {
 			return []models.Cycle{}, err
 		}
 
-		c := &cycles[i]
 		c.Scene = scene
 	}
 
@@ -166,7 +188,7 @@ func (md *Modeler) GetScenesRecursive() ([]models.Scene, error) {
 	}
 
 	for i, scene := range scenes {
-		groups, err := md.GetGroups(scene.ID)
+		groups, err := md.GetGroupsRecursive(scene.ID)
 		if err != nil {
 			return []models.Scene{}, err
 		}
@@ -322,3 +344,123 @@ func (md *Modeler) GetActionOrderNext(groupID int) (int, error) {
 func (md *Modeler) DeleteAction(actionID int) error {
 	return md.db.DeleteAction(actionID)
 }
+
+// GetEffects to return a slice of Effect objects.
+func (md *Modeler) GetEffects() ([]models.Effect, error) {
+	return md.db.GetEffects()
+}
+
+// GetEffect to return an Effect object.
+func (md *Modeler) GetEffect(effectID int) (models.Effect, error) {
+	return md.db.GetEffect(effectID)
+}
+
+// AddEffect to add an Effect object.
+func (md *Modeler) AddEffect(effect models.Effect) (int, error) {
+	return md.db.AddEffect(effect)
+}
+
+// SetEffect to update an Effect object.
+func (md *Modeler) SetEffect(effect models.Effect) error {
+	return md.db.SetEffect(effect)
+}
+
+// DeleteEffect to delete an Effect object.
+func (md *Modeler) DeleteEffect(effectID int) error {
+	return md.db.DeleteEffect(effectID)
+}
+
+// IsEffectUsed to check whether a show cycle runs an Effect.
+func (md *Modeler) IsEffectUsed(effectID int) (bool, error) {
+	return md.db.IsEffectUsed(effectID)
+}
+
+// GetPalettes to return a slice of Palette objects.
+func (md *Modeler) GetPalettes() ([]models.Palette, error) {
+	return md.db.GetPalettes()
+}
+
+// GetPalette to return a Palette object.
+func (md *Modeler) GetPalette(paletteID int) (models.Palette, error) {
+	return md.db.GetPalette(paletteID)
+}
+
+// AddPalette to add a Palette object.
+func (md *Modeler) AddPalette(palette models.Palette) (int, error) {
+	return md.db.AddPalette(palette)
+}
+
+// SetPalette to update a Palette object.
+func (md *Modeler) SetPalette(palette models.Palette) error {
+	return md.db.SetPalette(palette)
+}
+
+// DeletePalette to delete a Palette object.
+func (md *Modeler) DeletePalette(paletteID int) error {
+	return md.db.DeletePalette(paletteID)
+}
+
+// IsPaletteUsed to check whether a show uses a Palette.
+func (md *Modeler) IsPaletteUsed(paletteID int) (bool, error) {
+	return md.db.IsPaletteUsed(paletteID)
+}
+
+// GetDevices to return a slice of Device objects.
+func (md *Modeler) GetDevices() []models.Device {
+	return md.db.GetDevices()
+}
+
+// GetDevice to return a Device object.
+func (md *Modeler) GetDevice(deviceID int) (models.Device, error) {
+	return md.db.GetDevice(deviceID)
+}
+
+// AddDevice to add a Device object.
+func (md *Modeler) AddDevice(device models.Device) (int, error) {
+	return md.db.AddDevice(device)
+}
+
+// SetDevice to update a Device object.
+func (md *Modeler) SetDevice(device models.Device) error {
+	return md.db.SetDevice(device)
+}
+
+// DeleteDevice to delete a Device object.
+func (md *Modeler) DeleteDevice(deviceID int) error {
+	return md.db.DeleteDevice(deviceID)
+}
+
+// GetDeviceType to return a DeviceType object.
+func (md *Modeler) GetDeviceType(typeID int) models.DeviceType {
+	return md.db.GetDeviceType(typeID)
+}
+
+// GetDeviceTypes to return a slice of DeviceType objects.
+func (md *Modeler) GetDeviceTypes() []models.DeviceType {
+	return md.db.GetDeviceTypes()
+}
+
+// GetSchedules to return a slice of Schedule objects.
+func (md *Modeler) GetSchedules() ([]models.Schedule, error) {
+	return md.db.GetSchedules()
+}
+
+// GetSchedule to return a Schedule object.
+func (md *Modeler) GetSchedule(scheduleID int) (models.Schedule, error) {
+	return md.db.GetSchedule(scheduleID)
+}
+
+// AddSchedule to add a Schedule object.
+func (md *Modeler) AddSchedule(schedule models.Schedule) (int, error) {
+	return md.db.AddSchedule(schedule)
+}
+
+// SetSchedule to update a Schedule object.
+func (md *Modeler) SetSchedule(schedule models.Schedule) error {
+	return md.db.SetSchedule(schedule)
+}
+
+// DeleteSchedule to delete a Schedule object.
+func (md *Modeler) DeleteSchedule(scheduleID int) error {
+	return md.db.DeleteSchedule(scheduleID)
+}
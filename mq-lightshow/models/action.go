@@ -1,15 +1,28 @@
 // Package models provides our models
 package models
 
+// Types of actions, an action without a type is a command action.
+const (
+	ActionTypeCommand = "command" // sends Command with Parameter as is.
+	ActionTypeColor   = "color"   // sets Color or CT, Brightness and Transition on any type of light.
+)
+
 type (
 	// Action structure.
 	Action struct {
 		ID              int
 		GroupID         int
 		Devices         []Device
+		Zones           []string // the devices of these zones are added to Devices when the action runs.
+		Type            string
 		Command         string
 		Parameter       string
 		GlobalParameter string
+		Color           string  // r,g,b or #hex, empty to leave the color unchanged.
+		CT              int     // color temperature in mireds, 0 to leave it unchanged.
+		Brightness      int     // 1 to 100(%), 0 to leave it unchanged.
+		Transition      float64 // seconds to fade to the new state.
+		PaletteColor    string  // number (from 1) of the color of the show's palette to use, or PaletteColorNext.
 		Order           int
 	}
 )
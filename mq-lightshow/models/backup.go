@@ -0,0 +1,14 @@
+package models
+
+type (
+	// Backup structure. A versioned document holding everything needed to rebuild shows on another install.
+	Backup struct {
+		Version   int
+		Devices   []Device
+		Scenes    []Scene
+		Effects   []Effect
+		Palettes  []Palette
+		Shows     []Show
+		Schedules []Schedule
+	}
+)
@@ -3,7 +3,12 @@ package models
 type (
 	// Command structure.
 	Command struct {
-		Name        string
-		Description string
+		Name            string
+		Description     string
+		Type            string // the type of the parameter, see devicetypes.ValidateParameter.
+		Min             int    // Min and Max are the range of a numeric parameter, both 0 if there is none.
+		Max             int
+		Values          []string // the allowed values of an enum parameter.
+		PayloadTemplate string   // overrides the PayloadTemplate of the device type.
 	}
 )
@@ -3,9 +3,22 @@ package models
 type (
 	// Configuration structure. This all goes into the db with primitive map conversion so strings only.
 	Configuration struct {
-		MQTTHost string
-		MQTTUser string
-		MQTTPass string
-		LogLevel string
+		MQTTHost               string
+		MQTTUser               string
+		MQTTPass               string
+		MQTTClientID           string
+		MQTTCAFile             string
+		MQTTClientCert         string
+		MQTTClientKey          string
+		MQTTInsecureSkipVerify string
+		MQTTKeepAlive          string
+		MQTTQoS                string
+		MQTTStatusTopic        string
+		MQTTFullTopic          string
+		OfflineDevicePolicy    string
+		Latitude               string
+		Longitude              string
+		TimeZone               string
+		LogLevel               string
 	}
 )
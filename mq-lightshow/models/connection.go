@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+type (
+	// ConnectionEvent is a change of the mqtt connection state.
+	ConnectionEvent struct {
+		Time  time.Time
+		Event string
+		Error string
+	}
+)
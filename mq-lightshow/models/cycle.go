@@ -6,6 +6,7 @@ type (
 		ID               int
 		ShowID           int
 		SceneID          int
+		EffectID         int // runs the effect instead of the scene when set.
 		SceneCycles      int
 		EndDelay         float32
 		LoopInclude      bool
@@ -14,5 +15,6 @@ type (
 		GlobalParameter1 string
 		GlobalParameter2 string
 		Scene            Scene
+		Effect           Effect
 	}
 )
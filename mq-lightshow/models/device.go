@@ -1,12 +1,22 @@
 package models
 
+import "time"
+
 type (
 	// Device structure.
 	Device struct {
-		ID       int
-		Name     string
-		Topic    string
-		Type     DeviceType
-		Selected bool
+		ID             int
+		Name           string
+		Topic          string
+		FullTopic      string // Tasmota FullTopic pattern, the global pattern is used when empty.
+		Hostname       string // substituted for %hostname% in FullTopic.
+		Type           DeviceType
+		SupportsDimmer bool
+		SupportsColor  bool
+		SupportsCT     bool
+		Zones          []string // names of the zones the device is in.
+		Selected       bool
+		Online         bool      // from the LWT and STATE of the device, not stored.
+		LastSeen       time.Time // zero if nothing was heard from the device yet.
 	}
 )
@@ -3,8 +3,11 @@ package models
 type (
 	// DeviceType structure.
 	DeviceType struct {
-		ID       int
-		Name     string
-		Commands []Command
+		ID              int
+		Name            string
+		BuiltIn         bool   // compiled in, built in types cannot be changed.
+		TopicTemplate   string // %topic%, %hostname% and %command% are replaced, empty for Tasmota command topics.
+		PayloadTemplate string // %parameter% and %command% are replaced, empty to send the parameter as is.
+		Commands        []Command
 	}
 )
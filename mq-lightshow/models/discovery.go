@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+type (
+	// DiscoveredDevice is a device that announced itself over mqtt.
+	DiscoveredDevice struct {
+		MAC            string
+		Name           string
+		Topic          string
+		FullTopic      string
+		Hostname       string
+		IP             string
+		Module         string
+		Version        string
+		SupportsDimmer bool
+		SupportsColor  bool
+		SupportsCT     bool
+		Online         bool
+		LastSeen       time.Time
+	}
+)
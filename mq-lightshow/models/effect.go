@@ -0,0 +1,30 @@
+package models
+
+// Types of effects.
+const (
+	EffectRainbowChase = "rainbow-chase"
+	EffectSparkle      = "sparkle"
+	EffectBreathe      = "breathe"
+	EffectStrobe       = "strobe"
+)
+
+// Directions of effects.
+const (
+	EffectForward = "forward"
+	EffectReverse = "reverse"
+)
+
+type (
+	// Effect structure. An effect generates the colors of its devices while it runs instead of
+	// running a scene.
+	Effect struct {
+		ID        int
+		Name      string
+		Type      string
+		Devices   []Device
+		Palette   []string // colors as r,g,b or #hex, a rainbow when empty.
+		Speed     float32  // steps per second.
+		Direction string
+		Duration  float32 // seconds the effect runs for each cycle.
+	}
+)
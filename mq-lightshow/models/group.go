@@ -3,11 +3,14 @@ package models
 type (
 	// Group structure.
 	Group struct {
-		ID          int
-		SceneID     int
-		Delay       float32
-		GlobalDelay bool
-		Order       int
-		Actions     []Action
+		ID           int
+		SceneID      int
+		Delay        float32
+		GlobalDelay  bool
+		FadeDuration float32 // seconds to fade the colors of the group's actions in, 0 to not fade.
+		FadeRate     int     // fade steps per second, 0 for the default.
+		FadeEasing   string
+		Order        int
+		Actions      []Action
 	}
 )
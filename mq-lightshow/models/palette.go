@@ -0,0 +1,14 @@
+package models
+
+// PaletteColorNext makes an action use the palette color after the one used by the previous action.
+const PaletteColorNext = "next"
+
+type (
+	// Palette structure. An ordered list of colors that the actions of a show can refer to, so
+	// a show can be recolored by changing its palette.
+	Palette struct {
+		ID     int
+		Name   string
+		Colors []string // colors as #hex.
+	}
+)
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+type (
+	// ShowProgress structure, a snapshot of where a running show is.
+	ShowProgress struct {
+		ShowID     int
+		Name       string
+		Running    bool
+		Paused     bool
+		CycleIndex int
+		CycleID    int
+		SceneID    int
+		SceneName  string
+		EffectID   int
+		EffectName string
+		SceneCycle int
+		GroupOrder int
+		Loop       int
+		StartedAt  time.Time
+		Elapsed    float64 // seconds since the show was started.
+	}
+)
@@ -6,6 +6,7 @@ type (
 		ID             int
 		Name           string
 		AllowedDevices []Device
+		AllowedZones   []string // the devices of these zones are allowed too.
 		Groups         []Group
 	}
 )
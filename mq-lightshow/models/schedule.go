@@ -0,0 +1,19 @@
+package models
+
+type (
+	// Schedule structure. Starts and stops a show at set times, optionally only on some days
+	// of the week and during part of the year.
+	Schedule struct {
+		ID        int
+		Name      string
+		ShowID    int
+		ShowName  string
+		Enabled   bool
+		Cron      string // five field cron expression that starts the show, instead of StartTime.
+		Days      []int  // days of the week the schedule runs on with 0 as Sunday, empty for every day.
+		StartTime string // HH:MM
+		StopTime  string // HH:MM, a stop time before the start time stops the show the next day.
+		StartDate string // MM-DD, the first day of a season such as 12-01.
+		EndDate   string // MM-DD, the last day of a season, may be in the next year such as 01-06.
+	}
+)
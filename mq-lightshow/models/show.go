@@ -3,6 +3,7 @@ package models
 // Show structure.
 type Show struct {
 	Running          bool
+	Paused           bool
 	Repeat           bool
 	GlobalDelay      float32
 	ID               int
@@ -11,5 +12,18 @@ type Show struct {
 	Topic            string
 	GlobalParameter1 string
 	GlobalParameter2 string
+	PaletteID        int
+	Palette          Palette
+	Priority         int    // decides which show keeps a device another show wants.
+	ConflictPolicy   string // what starting the show does about running shows using its devices.
 	Cycles           []Cycle
 }
+
+// ShowConflict is a running show that uses some of the devices of a show being started.
+type ShowConflict struct {
+	ShowID     int
+	Name       string
+	Priority   int
+	Devices    []string // names of the devices both shows use.
+	Resolution string   // shared, preempted or rejected.
+}
@@ -0,0 +1,9 @@
+package models
+
+type (
+	// SolarEvent structure. The time of day of a sunrise, sunset or twilight as HH:MM.
+	SolarEvent struct {
+		Event string
+		Time  string
+	}
+)
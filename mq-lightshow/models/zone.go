@@ -0,0 +1,10 @@
+package models
+
+type (
+	// Zone structure. A zone is a name that devices are tagged with, actions and scenes can use
+	// it instead of listing the devices.
+	Zone struct {
+		Name    string
+		Devices []Device
+	}
+)
@@ -9,7 +9,9 @@ import (
 	"os"
 	"os/signal"
 	"sync"
+	"syscall"
 	"time"
+	_ "time/tzdata" // the image has no zoneinfo for the TimeZone option.
 
 	"github.com/lovesway/hassio-addons/mq-lightshow/database"
 	"github.com/lovesway/hassio-addons/mq-lightshow/devicetypes"
@@ -81,18 +83,46 @@ func main() {
 	log.Infof("Starting mq-lightshow version %s", version)
 
 	dt := devicetypes.NewDeviceTypes()
+
+	err := dt.LoadFile("data/devicetypes.json")
+	if err != nil {
+		log.Errorf("Error loading device types: %v", err)
+	}
+
+	// only JSON is read, a YAML file would otherwise be ignored without notice.
+	for _, f := range []string{"data/devicetypes.yaml", "data/devicetypes.yml"} {
+		if _, err := os.Stat(f); err == nil {
+			log.Errorf("Error loading device types: %v is not read, device types must be in data/devicetypes.json", f)
+		}
+	}
+
 	db := database.NewSqlite(dt)
 	ss := NewStringsToStruct()
 	md := NewModler(db)
-	mq := NewMQController(md)
-	ex = NewExecutor(md, mq)
-	ac := NewAPIController(md, ss, db)
-	c := NewController(md, db, mq, dt)
+	mq := NewMQController(md, dt)
+	ex = NewExecutor(md, mq, conf.OfflineDevicePolicy)
 
-	db.InitializeClient()
+	st, err := newScheduleTimes(conf)
+	if err != nil {
+		log.Errorf("Error reading the schedule options: %v", err)
+	}
+
+	ac := NewAPIController(md, ss, db, mq, dt, st)
+	c := NewController(md, db, mq, dt, st)
+
+	err = db.InitializeClient()
+	if err != nil {
+		panic(fmt.Sprintf("Error opening the database: %s", err.Error()))
+	}
+
+	scheduler := NewScheduler(md, ex, st)
+	mq.SetConnectHandler(scheduler.Connected)
 
 	mq.MqttConnect(conf)
 
+	schedulerCtx, stopScheduler := context.WithCancel(context.Background())
+	go scheduler.Run(schedulerCtx)
+
 	const five = 5
 
 	serverCfg := Config{
@@ -111,10 +141,13 @@ func main() {
 	}()
 
 	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, os.Interrupt)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
 	<-sigChan
 
 	log.Info("Shutting down")
+	stopScheduler()
+	ex.StopAllShows()
+	mq.ResetShowStates()
 	mq.MqttDisconnect()
 	db.Disconnect()
 }
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	MQTT "github.com/eclipse/paho.mqtt.golang"
+	"github.com/lovesway/hassio-addons/mq-lightshow/devicetypes"
+	"github.com/lovesway/hassio-addons/mq-lightshow/models"
+)
+
+var (
+	errMQTTQoS       = errors.New("MQTTQoS must be 0, 1 or 2")
+	errMQTTKeepAlive = errors.New("MQTTKeepAlive must be a number of seconds")
+	errMQTTCAFile    = errors.New("MQTTCAFile contains no certificates")
+	errMQTTCertKey   = errors.New("MQTTClientCert and MQTTClientKey must be set together")
+)
+
+// clientOptions builds the options of the mqtt client from the add-on configuration.
+func (mqc *MQController) clientOptions(config models.Configuration) (*MQTT.ClientOptions, error) {
+	qos, err := mqttQoS(config.MQTTQoS)
+	if err != nil {
+		return nil, err
+	}
+
+	keepAlive, err := mqttKeepAlive(config.MQTTKeepAlive)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig, err := mqttTLSConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	mqc.qos = qos
+
+	mqc.statusTopic = defaultStatusTopic
+	if config.MQTTStatusTopic != "" {
+		mqc.statusTopic = config.MQTTStatusTopic
+	}
+
+	mqc.fullTopic = devicetypes.DefaultFullTopic
+	if config.MQTTFullTopic != "" {
+		mqc.fullTopic = config.MQTTFullTopic
+	}
+
+	const (
+		retryInterval        = 10 * time.Second
+		maxReconnectInterval = time.Minute
+	)
+
+	opts := MQTT.NewClientOptions().AddBroker(config.MQTTHost)
+	opts.SetClientID(mqttClientID(config.MQTTClientID))
+
+	// anonymous brokers need neither.
+	if config.MQTTUser != "" {
+		opts.SetUsername(config.MQTTUser)
+	}
+
+	if config.MQTTPass != "" {
+		opts.SetPassword(config.MQTTPass)
+	}
+
+	if tlsConfig != nil {
+		opts.SetTLSConfig(tlsConfig)
+	}
+
+	if keepAlive != 0 {
+		opts.SetKeepAlive(keepAlive)
+	}
+
+	opts.SetDefaultPublishHandler(mqc.f)
+	opts.SetWill(mqc.statusTopic, payloadOffline, qos, true)
+	opts.SetAutoReconnect(true)
+	opts.SetConnectRetry(true)
+	opts.SetConnectRetryInterval(retryInterval)
+	opts.SetMaxReconnectInterval(maxReconnectInterval)
+	opts.SetOnConnectHandler(mqc.onConnect)
+	opts.SetConnectionLostHandler(mqc.onConnectionLost)
+	opts.SetReconnectingHandler(func(client MQTT.Client, opts *MQTT.ClientOptions) {
+		mqc.addConnectionEvent("reconnecting", nil)
+	})
+
+	return opts, nil
+}
+
+func mqttQoS(s string) (byte, error) {
+	if s == "" {
+		return 0, nil
+	}
+
+	const maxQoS = 2
+
+	qos, err := strconv.Atoi(s)
+	if err != nil || qos < 0 || qos > maxQoS {
+		return 0, fmt.Errorf("%w: %v", errMQTTQoS, s)
+	}
+
+	return byte(qos), nil
+}
+
+// mqttKeepAlive returns 0 when not configured, leaving the client default in place.
+func mqttKeepAlive(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+
+	seconds, err := strconv.Atoi(s)
+	if err != nil || seconds < 0 {
+		return 0, fmt.Errorf("%w: %v", errMQTTKeepAlive, s)
+	}
+
+	return time.Duration(seconds) * time.Second, nil
+}
+
+// mqttClientID returns the configured client ID, or a random one so that two instances
+// connected to the same broker do not disconnect each other.
+func mqttClientID(clientID string) string {
+	if clientID != "" {
+		return clientID
+	}
+
+	const suffixBytes = 4
+
+	b := make([]byte, suffixBytes)
+
+	_, err := rand.Read(b)
+	if err != nil {
+		log.Error(err.Error())
+
+		return "mq-lightshow"
+	}
+
+	return "mq-lightshow-" + hex.EncodeToString(b)
+}
+
+// mqttTLSConfig returns nil when no TLS option is configured. A broker URL starting with
+// ssl:// or tls:// still connects with TLS using the system certificates in that case.
+func mqttTLSConfig(config models.Configuration) (*tls.Config, error) {
+	insecure := config.MQTTInsecureSkipVerify == "true"
+
+	if config.MQTTCAFile == "" && config.MQTTClientCert == "" && config.MQTTClientKey == "" && !insecure {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: insecure, //nolint:gosec // the user opted in, for brokers with self-signed certificates.
+	}
+
+	if config.MQTTCAFile != "" {
+		pem, err := os.ReadFile(config.MQTTCAFile)
+		if err != nil {
+			return nil, err
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("%w: %v", errMQTTCAFile, config.MQTTCAFile)
+		}
+
+		tlsConfig.RootCAs = pool
+	}
+
+	if (config.MQTTClientCert == "") != (config.MQTTClientKey == "") {
+		return nil, errMQTTCertKey
+	}
+
+	if config.MQTTClientCert != "" {
+		cert, err := tls.LoadX509KeyPair(config.MQTTClientCert, config.MQTTClientKey)
+		if err != nil {
+			return nil, err
+		}
+
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
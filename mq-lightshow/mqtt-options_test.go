@@ -0,0 +1,181 @@
+package main_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	main "github.com/lovesway/hassio-addons/mq-lightshow"
+	"github.com/lovesway/hassio-addons/mq-lightshow/models"
+)
+
+func TestMQTTQoS(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in   string
+		want byte
+		err  bool
+	}{
+		{in: "", want: 0},
+		{in: "0", want: 0},
+		{in: "1", want: 1},
+		{in: "2", want: 2},
+		{in: "3", err: true},
+		{in: "-1", err: true},
+		{in: "one", err: true},
+	}
+
+	for _, tt := range tests {
+		got, err := main.MQTTQoS(tt.in)
+		if (err != nil) != tt.err {
+			t.Errorf("mqttQoS(%q) error = %v, want error %v", tt.in, err, tt.err)
+
+			continue
+		}
+
+		if got != tt.want {
+			t.Errorf("mqttQoS(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestMQTTKeepAlive(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in   string
+		want time.Duration
+		err  bool
+	}{
+		{in: "", want: 0},
+		{in: "0", want: 0},
+		{in: "30", want: 30 * time.Second},
+		{in: "-5", err: true},
+		{in: "30s", err: true},
+	}
+
+	for _, tt := range tests {
+		got, err := main.MQTTKeepAlive(tt.in)
+		if (err != nil) != tt.err {
+			t.Errorf("mqttKeepAlive(%q) error = %v, want error %v", tt.in, err, tt.err)
+
+			continue
+		}
+
+		if got != tt.want {
+			t.Errorf("mqttKeepAlive(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+// writeCertificate writes a self-signed certificate and its key as PEM files.
+func writeCertificate(t *testing.T, dir string) (string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "broker"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+
+	for file, block := range map[string]*pem.Block{
+		certFile: {Type: "CERTIFICATE", Bytes: der},
+		keyFile:  {Type: "EC PRIVATE KEY", Bytes: keyDER},
+	} {
+		if err := os.WriteFile(file, pem.EncodeToMemory(block), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return certFile, keyFile
+}
+
+func TestMQTTTLSConfig(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	certFile, keyFile := writeCertificate(t, dir)
+
+	notPEM := filepath.Join(dir, "empty.pem")
+	if err := os.WriteFile(notPEM, []byte("no certificates"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		config   models.Configuration
+		isNil    bool
+		insecure bool
+		rootCAs  bool
+		certs    int
+		err      bool
+	}{
+		{name: "no TLS options", config: models.Configuration{}, isNil: true},
+		{name: "insecure", config: models.Configuration{MQTTInsecureSkipVerify: "true"}, insecure: true},
+		{name: "CA file", config: models.Configuration{MQTTCAFile: certFile}, rootCAs: true},
+		{name: "CA file without certificates", config: models.Configuration{MQTTCAFile: notPEM}, err: true},
+		{name: "missing CA file", config: models.Configuration{MQTTCAFile: filepath.Join(dir, "none.pem")}, err: true},
+		{
+			name:   "client certificate",
+			config: models.Configuration{MQTTClientCert: certFile, MQTTClientKey: keyFile},
+			certs:  1,
+		},
+		{name: "client certificate without key", config: models.Configuration{MQTTClientCert: certFile}, err: true},
+		{name: "client key without certificate", config: models.Configuration{MQTTClientKey: keyFile}, err: true},
+	}
+
+	for _, tt := range tests {
+		got, err := main.MQTTTLSConfig(tt.config)
+		if (err != nil) != tt.err {
+			t.Errorf("%v: error = %v, want error %v", tt.name, err, tt.err)
+
+			continue
+		}
+
+		if tt.err {
+			continue
+		}
+
+		if (got == nil) != tt.isNil {
+			t.Errorf("%v: config = %v, want nil %v", tt.name, got, tt.isNil)
+
+			continue
+		}
+
+		if got == nil {
+			continue
+		}
+
+		if got.InsecureSkipVerify != tt.insecure || (got.RootCAs != nil) != tt.rootCAs || len(got.Certificates) != tt.certs {
+			t.Errorf("%v: insecure %v, root CAs %v, %v certificates, want %v, %v, %v", tt.name,
+				got.InsecureSkipVerify, got.RootCAs != nil, len(got.Certificates), tt.insecure, tt.rootCAs, tt.certs)
+		}
+	}
+}
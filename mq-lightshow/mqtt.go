@@ -1,114 +1,231 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	MQTT "github.com/eclipse/paho.mqtt.golang"
+	"github.com/lovesway/hassio-addons/mq-lightshow/devicetypes"
 	"github.com/lovesway/hassio-addons/mq-lightshow/models"
 )
 
+var errMQTTNotConnected = errors.New("mqtt client is not connected")
+
+const (
+	defaultStatusTopic = "mqlightshow/status"
+	payloadOnline      = "online"
+	payloadOffline     = "offline"
+)
+
 // MQController struct to represent a class.
 type MQController struct {
 	mc                          MQTT.Client
+	statusTopic                 string
+	fullTopic                   string // global Tasmota FullTopic pattern for devices.
+	qos                         byte
+	discoveredMu                sync.Mutex
+	discovered                  map[string]models.DiscoveredDevice // keyed by device topic.
+	healthMu                    sync.Mutex
+	health                      map[int]deviceHealth // keyed by device id.
+	healthTopics                map[string]healthTopic
+	mu                          sync.Mutex // guards messages, connectionEvents and subscribeInitIgnoreMessages.
 	messages                    []models.Message
+	connectionEvents            []models.ConnectionEvent
 	subscribeInitIgnoreMessages bool
 	f                           MQTT.MessageHandler
 	md                          Modeler
+	dt                          *devicetypes.DeviceTypes
+	connectHandler              func() // called after each connect, see SetConnectHandler.
 }
 
 // NewMQController method to instantiate class/struct.
-func NewMQController(md Modeler) *MQController {
+func NewMQController(md Modeler, dt *devicetypes.DeviceTypes) *MQController {
 	mqc := &MQController{
 		md:                          md,
+		dt:                          dt,
+		statusTopic:                 defaultStatusTopic,
+		fullTopic:                   devicetypes.DefaultFullTopic,
+		discovered:                  map[string]models.DiscoveredDevice{},
+		health:                      map[int]deviceHealth{},
+		healthTopics:                map[string]healthTopic{},
 		subscribeInitIgnoreMessages: true,
 	}
 
 	mqc.f = func(client MQTT.Client, msg MQTT.Message) {
-		if mqc.subscribeInitIgnoreMessages {
+		// announcements and the LWT are retained, so they are wanted while initializing.
+		known := mqc.handleDeviceHealth(msg.Topic(), msg.Payload())
+		if mqc.handleDeviceAnnouncement(msg.Topic(), msg.Payload()) || known {
+			return
+		}
+
+		if !mqc.addMessage(models.Message{Topic: msg.Topic(), Message: string(msg.Payload())}) {
 			log.Debug("mqtt init: ignoring message while initializing")
 
 			return
 		}
 
-		const maxMessagesTracked = 50 // only keep the last 50 messages
-		if len(mqc.messages) >= maxMessagesTracked {
-			mqc.messages = mqc.messages[1:]
+		if mqc.handleShowCommand(msg.Topic(), string(msg.Payload())) {
+			return
 		}
 
-		mqc.messages = append(mqc.messages, models.Message{Topic: msg.Topic(), Message: string(msg.Payload())})
+		if mqc.handleSceneCommand(msg.Topic(), string(msg.Payload())) {
+			return
+		}
 
-		cmd := string(msg.Payload())
-		if cmd == "ON" || cmd == "OFF" {
-			topicShowSplit := strings.Split(msg.Topic(), "/")
+		log.Debug("TOPIC: %s", msg.Topic())
+		log.Debug("MSG: %s", msg.Payload())
+	}
 
-			const three = 3
-			if len(topicShowSplit) < three {
-				return
-			}
+	return mqc
+}
 
-			topicShow := topicShowSplit[2]
+// handleShowCommand runs a show control payload received on mqlightshow/show/<topic>/cmnd.
+// It returns false if the topic or the payload is not a show command.
+func (mqc *MQController) handleShowCommand(topic string, cmd string) bool {
+	var control func(showID int) error
+
+	switch cmd {
+	case "ON":
+		control = ex.StartShow
+	case "OFF":
+		control = ex.StopShow
+	case "PAUSE":
+		control = ex.PauseShow
+	case "RESUME":
+		control = ex.ResumeShow
+	case "NEXT":
+		control = ex.NextCycle
+	case "PREVIOUS":
+		control = ex.PreviousCycle
+	default:
+		return false
+	}
 
-			show, err := md.GetShowByTopic(topicShow)
-			if err != nil {
-				log.Errorf("mqtt error: cannot get show by topic: %v", topicShow)
+	// scene commands are received by the same handler, so the topic has to be a show command topic.
+	topicShowSplit := strings.Split(topic, "/")
 
-				return
-			}
+	const four = 4
+	if len(topicShowSplit) < four || topicShowSplit[0] != "mqlightshow" || topicShowSplit[1] != "show" ||
+		topicShowSplit[3] != "cmnd" {
+		return false
+	}
 
-			if cmd == "ON" {
-				err = ex.StartShow(show.ID)
-				if err != nil {
-					log.Error(err.Error())
-				}
-			} else if cmd == "OFF" {
-				err = ex.StopShow(show.ID)
-				if err != nil {
-					log.Error(err.Error())
-				}
-			}
+	topicShow := topicShowSplit[2]
 
-			return
+	show, err := mqc.md.GetShowByTopic(topicShow)
+	if err != nil {
+		log.Errorf("mqtt error: cannot get show by topic: %v", topicShow)
+
+		return true
+	}
+
+	// starting and stopping a show can wait for other shows, which must not block the mqtt handler.
+	go func() {
+		if err := control(show.ID); err != nil {
+			log.Error(err.Error())
 		}
+	}()
 
-		log.Debug("TOPIC: %s", msg.Topic())
-		log.Debug("MSG: %s", msg.Payload())
+	return true
+}
+
+// addMessage to track a received message. It returns false if the message should be ignored
+// because it arrived while initializing.
+func (mqc *MQController) addMessage(m models.Message) bool {
+	mqc.mu.Lock()
+	defer mqc.mu.Unlock()
+
+	if mqc.subscribeInitIgnoreMessages {
+		return false
 	}
 
-	return mqc
+	const maxMessagesTracked = 50 // only keep the last 50 messages
+	if len(mqc.messages) >= maxMessagesTracked {
+		mqc.messages = mqc.messages[1:]
+	}
+
+	mqc.messages = append(mqc.messages, m)
+
+	return true
 }
 
-// GetMessages returns last 100 messages.
+// GetMessages returns the last 50 messages.
 func (mqc *MQController) GetMessages() []models.Message {
-	return mqc.messages
+	mqc.mu.Lock()
+	defer mqc.mu.Unlock()
+
+	return append([]models.Message{}, mqc.messages...)
 }
 
-// MqttConnect to the mqtt server.
+// MqttConnect to the mqtt server. The client keeps retrying the initial connection and reconnects
+// automatically when the connection is lost, subscriptions are restored each time it connects.
 func (mqc *MQController) MqttConnect(config models.Configuration) {
-	if config.MQTTHost == "" || config.MQTTUser == "" || config.MQTTPass == "" {
+	if config.MQTTHost == "" {
 		log.Error("Connect requested, but needed values are missing.")
 
 		return
 	}
 
-	opts := MQTT.NewClientOptions().AddBroker(config.MQTTHost)
-	opts.SetClientID("mq-lightshow")
-	opts.SetUsername(config.MQTTUser)
-	opts.SetPassword(config.MQTTPass)
-	opts.SetDefaultPublishHandler(mqc.f)
+	if mqc.mc != nil {
+		mqc.MqttDisconnect()
+	}
+
+	opts, err := mqc.clientOptions(config)
+	if err != nil {
+		log.Errorf("Connect requested, but the configuration is invalid: %v", err)
+		mqc.addConnectionEvent("invalid configuration", err)
+
+		return
+	}
+
+	const connectTimeout = 5 * time.Second
+
+	mqc.addConnectionEvent("connecting to "+config.MQTTHost, nil)
 
 	mqc.mc = MQTT.NewClient(opts)
-	if token := mqc.mc.Connect(); token.Wait() && token.Error() != nil {
+	if token := mqc.mc.Connect(); !token.WaitTimeout(connectTimeout) {
+		log.Warn("client not connected yet, retrying in the background")
+	} else if token.Error() != nil {
 		log.Errorf("%v", token.Error())
-	} else {
-		log.Info("client connected")
+		mqc.addConnectionEvent("connect failed", token.Error())
 	}
+}
+
+// onConnect runs every time the client (re)connects. The subscriptions are not kept by the broker
+// as the session is clean, so they are all made again.
+func (mqc *MQController) onConnect(client MQTT.Client) {
+	log.Info("client connected")
+	mqc.addConnectionEvent("connected", nil)
+
+	mqc.sendStatus(payloadOnline)
 
-	mqc.subscribeInitIgnoreMessages = true
+	mqc.setSubscribeInitIgnoreMessages(true)
 	mqc.SubscribeShows()
+	mqc.Subscribe(sceneCommandTopic)
+	mqc.SubscribeDeviceAnnouncements()
+	mqc.SubscribeDevices()
+	mqc.PublishDiscovery()
 
 	go mqc.resetSubscribeInit()
+
+	if mqc.connectHandler != nil {
+		mqc.connectHandler()
+	}
+}
+
+// SetConnectHandler sets a function that is called every time the client connects. It must not
+// block and must be set before MqttConnect.
+func (mqc *MQController) SetConnectHandler(f func()) {
+	mqc.connectHandler = f
+}
+
+func (mqc *MQController) onConnectionLost(client MQTT.Client, err error) {
+	log.Errorf("client connection lost: %v", err)
+	mqc.addConnectionEvent("connection lost", err)
 }
 
 func (mqc *MQController) resetSubscribeInit() {
@@ -116,12 +233,50 @@ func (mqc *MQController) resetSubscribeInit() {
 
 	time.Sleep(five * time.Second)
 
-	mqc.subscribeInitIgnoreMessages = false
+	mqc.setSubscribeInitIgnoreMessages(false)
+}
+
+func (mqc *MQController) setSubscribeInitIgnoreMessages(ignore bool) {
+	mqc.mu.Lock()
+	defer mqc.mu.Unlock()
+
+	mqc.subscribeInitIgnoreMessages = ignore
+}
+
+// addConnectionEvent to record a change of the connection state.
+func (mqc *MQController) addConnectionEvent(event string, err error) {
+	mqc.mu.Lock()
+	defer mqc.mu.Unlock()
+
+	const maxConnectionEventsTracked = 50 // only keep the last 50 events
+	if len(mqc.connectionEvents) >= maxConnectionEventsTracked {
+		mqc.connectionEvents = mqc.connectionEvents[1:]
+	}
+
+	ce := models.ConnectionEvent{Time: time.Now(), Event: event}
+	if err != nil {
+		ce.Error = err.Error()
+	}
+
+	mqc.connectionEvents = append(mqc.connectionEvents, ce)
+}
+
+// GetConnectionEvents returns the connection state history, newest first.
+func (mqc *MQController) GetConnectionEvents() []models.ConnectionEvent {
+	mqc.mu.Lock()
+	defer mqc.mu.Unlock()
+
+	events := make([]models.ConnectionEvent, 0, len(mqc.connectionEvents))
+	for i := len(mqc.connectionEvents) - 1; i >= 0; i-- {
+		events = append(events, mqc.connectionEvents[i])
+	}
+
+	return events
 }
 
 // MqttDisconnect from the mqtt server.
 func (mqc *MQController) MqttDisconnect() {
-	if !mqc.IsConnected() {
+	if mqc.mc == nil {
 		log.Info("client was already disconnected")
 
 		return
@@ -129,9 +284,16 @@ func (mqc *MQController) MqttDisconnect() {
 
 	log.Info("client disconnecting")
 
+	// a graceful disconnect does not trigger the will, so announce it.
+	if mqc.IsConnected() {
+		mqc.sendStatus(payloadOffline)
+	}
+
 	const eightHundred = 800
 
 	mqc.mc.Disconnect(eightHundred)
+
+	mqc.addConnectionEvent("disconnected", nil)
 }
 
 // IsConnected determines the state of the mqtt server connection.
@@ -154,30 +316,149 @@ func (mqc *MQController) SubscribeShows() {
 
 	for _, show := range shows {
 		if show.Topic != "" {
-			mqc.SendShowState(show.Topic, "OFF")
+			state := "OFF"
+			if ex.IsShowRunning(show.ID) {
+				state = "ON"
+			}
+
+			mqc.SendShowState(show.Topic, state)
 			t := fmt.Sprintf("mqlightshow/show/%v/cmnd/#", show.Topic)
 			mqc.Subscribe(t)
 		}
 	}
 }
 
+// ResetShowStates sets the state of all shows to OFF.
+func (mqc *MQController) ResetShowStates() {
+	if !mqc.IsConnected() {
+		return
+	}
+
+	shows, err := mqc.md.GetShows()
+	if err != nil {
+		log.Errorf("error resetting show states: %v", err.Error())
+
+		return
+	}
+
+	for _, show := range shows {
+		if show.Topic != "" {
+			mqc.SendShowState(show.Topic, "OFF")
+		}
+	}
+}
+
+// Unsubscribe from a topic.
+func (mqc *MQController) Unsubscribe(topic string) {
+	if !mqc.IsConnected() {
+		return
+	}
+
+	if token := mqc.mc.Unsubscribe(topic); token.Wait() && token.Error() != nil {
+		log.Error(token.Error().Error())
+	}
+}
+
 // Subscribe to a topic.
 func (mqc *MQController) Subscribe(topic string) {
-	if token := mqc.mc.Subscribe(topic, 0, nil); token.Wait() && token.Error() != nil {
+	if !mqc.IsConnected() {
+		return
+	}
+
+	if token := mqc.mc.Subscribe(topic, mqc.qos, nil); token.Wait() && token.Error() != nil {
 		log.Error(token.Error().Error())
 	}
 }
 
-// SendAction to send an action message to the mqtt server.
-func (mqc *MQController) SendAction(topic string, command string, parameter string) {
-	_topic := fmt.Sprintf("%s/cmnd/%s", topic, command)
-	_token := mqc.mc.Publish(_topic, 0, false, parameter)
+// SendAction to send an action message to the mqtt server. Actions are not queued while
+// the client is disconnected, as a delayed light command is worse than a missed one.
+// The parameter is checked against the command of the device type, as parameters taken from
+// the globals of a show or cycle are only known when the show runs.
+func (mqc *MQController) SendAction(device models.Device, command string, parameter string) error {
+	if !mqc.IsConnected() {
+		return fmt.Errorf("%w: cannot send %v to %v", errMQTTNotConnected, command, device.Topic)
+	}
+
+	if c, ok := devicetypes.FindCommand(device.Type, command); ok {
+		if err := devicetypes.ValidateParameter(c, parameter); err != nil {
+			return fmt.Errorf("cannot send %v to %v: %w", command, device.Name, err)
+		}
+	}
+
+	topic, payload, err := mqc.dt.Driver(device.Type.ID).Message(mqc.withFullTopic(device), command, parameter)
+	if err != nil {
+		return fmt.Errorf("cannot send %v to %v: %w", command, device.Name, err)
+	}
+
+	_token := mqc.mc.Publish(topic, mqc.qos, false, payload)
 	_token.Wait()
+
+	return _token.Error()
+}
+
+// SendLight to send the messages that set a device to the state of a color action.
+func (mqc *MQController) SendLight(device models.Device, state devicetypes.LightState) error {
+	if !mqc.IsConnected() {
+		return fmt.Errorf("%w: cannot send color to %v", errMQTTNotConnected, device.Topic)
+	}
+
+	msgs, err := mqc.dt.Driver(device.Type.ID).Light(mqc.withFullTopic(device), state)
+	if err != nil {
+		return fmt.Errorf("cannot send color to %v: %w", device.Name, err)
+	}
+
+	for _, msg := range msgs {
+		_token := mqc.mc.Publish(msg.Topic, mqc.qos, false, msg.Payload)
+		_token.Wait()
+
+		if _token.Error() != nil {
+			return _token.Error()
+		}
+	}
+
+	return nil
+}
+
+// withFullTopic sets the FullTopic of a device to the global pattern if it has none.
+func (mqc *MQController) withFullTopic(device models.Device) models.Device {
+	if device.FullTopic == "" {
+		device.FullTopic = mqc.fullTopic
+	}
+
+	return device
 }
 
 // SendShowState to send an action message to the mqtt server.
 func (mqc *MQController) SendShowState(topicShow string, state string) {
+	if !mqc.IsConnected() {
+		return
+	}
+
 	_topic := fmt.Sprintf("mqlightshow/show/%s/stat", topicShow)
-	_token := mqc.mc.Publish(_topic, 0, true, state)
+	_token := mqc.mc.Publish(_topic, mqc.qos, true, state)
+	_token.Wait()
+}
+
+// SendShowProgress to send the progress of a show to the mqtt server.
+func (mqc *MQController) SendShowProgress(topicShow string, progress models.ShowProgress) {
+	if !mqc.IsConnected() {
+		return
+	}
+
+	payload, err := json.Marshal(progress)
+	if err != nil {
+		log.Error(err.Error())
+
+		return
+	}
+
+	_topic := fmt.Sprintf("mqlightshow/show/%s/progress", topicShow)
+	_token := mqc.mc.Publish(_topic, mqc.qos, true, payload)
+	_token.Wait()
+}
+
+// sendStatus to send the retained availability of the add-on to the mqtt server.
+func (mqc *MQController) sendStatus(status string) {
+	_token := mqc.mc.Publish(mqc.statusTopic, mqc.qos, true, status)
 	_token.Wait()
 }
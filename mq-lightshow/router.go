@@ -19,8 +19,13 @@ func getRoutesAPI(router *mux.Router, ac APIController) *mux.Router {
 	router.HandleFunc("/api/v1/shows", ac.Shows).Methods("GET")
 	router.HandleFunc("/api/v1/show", ac.ShowCreate).Methods("POST")
 	router.HandleFunc("/api/v1/show/{showID}", ac.Show).Methods("GET")
+	router.HandleFunc("/api/v1/show/{showID}/status", ac.ShowStatus).Methods("GET")
 	router.HandleFunc("/api/v1/show/{showID}/start", ac.ShowStart).Methods("POST")
 	router.HandleFunc("/api/v1/show/{showID}/stop", ac.ShowStop).Methods("POST")
+	router.HandleFunc("/api/v1/show/{showID}/pause", ac.ShowPause).Methods("POST")
+	router.HandleFunc("/api/v1/show/{showID}/resume", ac.ShowResume).Methods("POST")
+	router.HandleFunc("/api/v1/show/{showID}/next", ac.ShowNext).Methods("POST")
+	router.HandleFunc("/api/v1/show/{showID}/previous", ac.ShowPrevious).Methods("POST")
 	router.HandleFunc("/api/v1/show/{showID}/configure", ac.ShowConfigure).Methods("POST")
 	router.HandleFunc("/api/v1/show/{showID}/delete", ac.ShowDelete).Methods("POST")
 	router.HandleFunc("/api/v1/show/{showID}/cycles", ac.ShowCycles).Methods("GET")
@@ -28,6 +33,7 @@ func getRoutesAPI(router *mux.Router, ac APIController) *mux.Router {
 	router.HandleFunc("/api/v1/show/{showID}/cycle/{cycleID}", ac.ShowCycle).Methods("GET")
 	router.HandleFunc("/api/v1/show/{showID}/cycle/{cycleID}/edit", ac.ShowCycleEdit).Methods("POST")
 	router.HandleFunc("/api/v1/show/{showID}/cycle/{cycleID}/delete", ac.ShowCycleDelete).Methods("POST")
+	router.HandleFunc("/api/v1/show/{showID}/cycle/{cycleID}/goto", ac.ShowCycleGoto).Methods("POST")
 	router.HandleFunc("/api/v1/scenes", ac.Scenes).Methods("GET")
 	router.HandleFunc("/api/v1/scene", ac.SceneCreate).Methods("POST")
 	router.HandleFunc("/api/v1/scene/{sceneID}", ac.Scene).Methods("GET")
@@ -61,6 +67,36 @@ func getRoutesAPI(router *mux.Router, ac APIController) *mux.Router {
 	router.HandleFunc(
 		"/api/v1/scene/{sceneID}/group/{groupID}/action/{actionID}/delete", ac.SceneGroupActionDelete,
 	).Methods("POST")
+	router.HandleFunc("/api/v1/devices", ac.Devices).Methods("GET")
+	router.HandleFunc("/api/v1/devices/discovered", ac.DevicesDiscovered).Methods("GET")
+	router.HandleFunc("/api/v1/devices/discovered/adopt", ac.DeviceAdopt).Methods("POST")
+	router.HandleFunc("/api/v1/device", ac.DeviceCreate).Methods("POST")
+	router.HandleFunc("/api/v1/device/{deviceID}", ac.Device).Methods("GET")
+	router.HandleFunc("/api/v1/device/{deviceID}/edit", ac.DeviceEdit).Methods("POST")
+	router.HandleFunc("/api/v1/device/{deviceID}/delete", ac.DeviceDelete).Methods("POST")
+	router.HandleFunc("/api/v1/zones", ac.Zones).Methods("GET")
+	router.HandleFunc("/api/v1/devicetypes", ac.DeviceTypes).Methods("GET")
+	router.HandleFunc("/api/v1/devicetype", ac.DeviceTypeCreate).Methods("POST")
+	router.HandleFunc("/api/v1/devicetype/{typeID}", ac.DeviceType).Methods("GET")
+	router.HandleFunc("/api/v1/devicetype/{typeID}/edit", ac.DeviceTypeEdit).Methods("POST")
+	router.HandleFunc("/api/v1/devicetype/{typeID}/delete", ac.DeviceTypeDelete).Methods("POST")
+	router.HandleFunc("/api/v1/effects", ac.Effects).Methods("GET")
+	router.HandleFunc("/api/v1/effect", ac.EffectCreate).Methods("POST")
+	router.HandleFunc("/api/v1/effect/{effectID}", ac.Effect).Methods("GET")
+	router.HandleFunc("/api/v1/effect/{effectID}/edit", ac.EffectEdit).Methods("POST")
+	router.HandleFunc("/api/v1/effect/{effectID}/delete", ac.EffectDelete).Methods("POST")
+	router.HandleFunc("/api/v1/palettes", ac.Palettes).Methods("GET")
+	router.HandleFunc("/api/v1/palette", ac.PaletteCreate).Methods("POST")
+	router.HandleFunc("/api/v1/palette/{paletteID}", ac.Palette).Methods("GET")
+	router.HandleFunc("/api/v1/palette/{paletteID}/edit", ac.PaletteEdit).Methods("POST")
+	router.HandleFunc("/api/v1/palette/{paletteID}/delete", ac.PaletteDelete).Methods("POST")
+	router.HandleFunc("/api/v1/schedules", ac.Schedules).Methods("GET")
+	router.HandleFunc("/api/v1/schedule", ac.ScheduleCreate).Methods("POST")
+	router.HandleFunc("/api/v1/schedule/{scheduleID}", ac.Schedule).Methods("GET")
+	router.HandleFunc("/api/v1/schedule/{scheduleID}/edit", ac.ScheduleEdit).Methods("POST")
+	router.HandleFunc("/api/v1/schedule/{scheduleID}/delete", ac.ScheduleDelete).Methods("POST")
+	router.HandleFunc("/api/v1/export", ac.Export).Methods("GET")
+	router.HandleFunc("/api/v1/import", ac.Import).Methods("POST")
 
 	return router
 }
@@ -91,6 +127,9 @@ func getRoutesUI(router *mux.Router, c Controller) *mux.Router {
 	router.HandleFunc("/devices-add", c.DevicesAddHandler)
 	router.HandleFunc("/devices-edit", c.DevicesEditHandler)
 	router.HandleFunc("/devices-delete", c.DevicesDeleteHandler)
+	router.HandleFunc("/schedules", c.SchedulesHandler)
+	router.HandleFunc("/schedules-add", c.SchedulesAddHandler)
+	router.HandleFunc("/schedules-edit", c.SchedulesEditHandler)
 	router.HandleFunc("/mqtt", c.MqttHandler)
 	router.HandleFunc("/mqtt-log", c.MqttLogHandler)
 
@@ -0,0 +1,219 @@
+package main_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	main "github.com/lovesway/hassio-addons/mq-lightshow"
+	"github.com/lovesway/hassio-addons/mq-lightshow/models"
+)
+
+func TestPauseResume(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		op     string // pause or resume.
+		want   bool
+		paused bool
+	}{
+		{op: "resume", want: false, paused: false},
+		{op: "pause", want: true, paused: true},
+		{op: "pause", want: false, paused: true},
+		{op: "resume", want: true, paused: false},
+		{op: "resume", want: false, paused: false},
+		{op: "pause", want: true, paused: true},
+	}
+
+	rs, _ := main.NewShowRunner().Start(models.Show{ID: 1})
+
+	for i, tt := range tests {
+		var got bool
+
+		switch tt.op {
+		case "pause":
+			got = rs.Pause()
+		case "resume":
+			got = rs.Resume()
+		}
+
+		if got != tt.want {
+			t.Errorf("step %v: %v = %v, want %v", i, tt.op, got, tt.want)
+		}
+
+		if rs.IsPaused() != tt.paused {
+			t.Errorf("step %v: IsPaused = %v, want %v", i, rs.IsPaused(), tt.paused)
+		}
+	}
+}
+
+func TestWaitIfPaused(t *testing.T) {
+	t.Parallel()
+
+	const wait = 50 * time.Millisecond
+
+	tests := []struct {
+		name    string
+		pause   bool
+		resume  bool // resume the show while it waits.
+		cancel  bool // cancel the context while it waits.
+		minWait time.Duration
+	}{
+		{name: "not paused"},
+		{name: "resumed", pause: true, resume: true, minWait: wait},
+		{name: "cancelled", pause: true, cancel: true, minWait: wait},
+	}
+
+	for _, tt := range tests {
+		rs, _ := main.NewShowRunner().Start(models.Show{ID: 1})
+		ctx, cancel := context.WithCancel(context.Background())
+
+		if tt.pause {
+			rs.Pause()
+		}
+
+		go func(resume bool, cancelWait bool) {
+			time.Sleep(wait)
+
+			if resume {
+				rs.Resume()
+			}
+
+			if cancelWait {
+				cancel()
+			}
+		}(tt.resume, tt.cancel)
+
+		start := time.Now()
+		main.WaitIfPaused(rs, ctx)
+
+		if waited := time.Since(start); waited < tt.minWait {
+			t.Errorf("%v: waited %v, want at least %v", tt.name, waited, tt.minWait)
+		}
+
+		cancel()
+	}
+}
+
+func TestSkip(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		index    int
+		wantJump int
+		wantOk   bool
+	}{
+		{index: main.NoJump, wantJump: main.NoJump, wantOk: false},
+		{index: 0, wantJump: 0, wantOk: true},
+		{index: 2, wantJump: 2, wantOk: true},
+	}
+
+	for _, tt := range tests {
+		rs, _ := main.NewShowRunner().Start(models.Show{ID: 1})
+		ctx := main.BeginCycle(rs, 1)
+
+		rs.Skip(tt.index)
+
+		if ctx.Err() == nil {
+			t.Errorf("Skip(%v) did not cancel the cycle", tt.index)
+		}
+
+		jump, ok := main.TakeJump(rs)
+		if jump != tt.wantJump || ok != tt.wantOk {
+			t.Errorf("Skip(%v) jump = %v, %v, want %v, %v", tt.index, jump, ok, tt.wantJump, tt.wantOk)
+		}
+
+		if _, ok := main.TakeJump(rs); ok {
+			t.Errorf("Skip(%v) jump was not cleared", tt.index)
+		}
+
+		if rs.Cycle() != 1 {
+			t.Errorf("Skip(%v) Cycle = %v, want 1", tt.index, rs.Cycle())
+		}
+
+		if main.BeginCycle(rs, 2).Err() != nil {
+			t.Errorf("Skip(%v) cancelled the next cycle", tt.index)
+		}
+	}
+}
+
+func TestProgress(t *testing.T) {
+	t.Parallel()
+
+	show := models.Show{
+		ID:   1,
+		Name: "show",
+		Cycles: []models.Cycle{
+			{ID: 10, Scene: models.Scene{ID: 20, Name: "scene"}},
+			{ID: 11, Scene: models.Scene{ID: 21, Name: "finale"}},
+		},
+	}
+
+	tests := []struct {
+		name  string
+		setup func(rs *main.RunningShow)
+		want  models.ShowProgress
+	}{
+		{
+			name:  "started",
+			setup: func(rs *main.RunningShow) {},
+			want:  models.ShowProgress{Running: true, CycleID: 10, SceneID: 20, SceneName: "scene"},
+		},
+		{
+			name: "scene group",
+			setup: func(rs *main.RunningShow) {
+				main.BeginCycle(rs, 0)
+				main.BeginSceneCycle(rs, 2)
+				main.BeginGroup(rs, 3)
+			},
+			want: models.ShowProgress{
+				Running: true, CycleID: 10, SceneID: 20, SceneName: "scene", SceneCycle: 2, GroupOrder: 3,
+			},
+		},
+		{
+			name: "next scene after a loop",
+			setup: func(rs *main.RunningShow) {
+				main.BeginCycle(rs, 0)
+				main.BeginGroup(rs, 3)
+				main.BeginLoop(rs)
+				main.BeginCycle(rs, 1)
+			},
+			want: models.ShowProgress{
+				Running: true, CycleIndex: 1, CycleID: 11, SceneID: 21, SceneName: "finale", Loop: 1,
+			},
+		},
+		{
+			name: "paused",
+			setup: func(rs *main.RunningShow) {
+				rs.Pause()
+			},
+			want: models.ShowProgress{Running: true, Paused: true, CycleID: 10, SceneID: 20, SceneName: "scene"},
+		},
+		{
+			name:  "cycle out of range",
+			setup: func(rs *main.RunningShow) { main.BeginCycle(rs, len(show.Cycles)) },
+			want:  models.ShowProgress{Running: true, CycleIndex: len(show.Cycles)},
+		},
+	}
+
+	for _, tt := range tests {
+		sr := main.NewShowRunner()
+		rs, _ := sr.Start(show)
+
+		tt.setup(rs)
+
+		got := rs.Progress()
+		tt.want.ShowID, tt.want.Name, tt.want.StartedAt = show.ID, show.Name, rs.StartedAt
+		got.Elapsed = 0
+
+		if got != tt.want {
+			t.Errorf("%v: Progress = %+v, want %+v", tt.name, got, tt.want)
+		}
+
+		sr.Stop(show.ID)
+
+		if rs.Progress().Running {
+			t.Errorf("%v: Progress of a stopped show is running", tt.name)
+		}
+	}
+}
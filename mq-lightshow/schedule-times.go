@@ -0,0 +1,278 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lovesway/hassio-addons/mq-lightshow/cron"
+	"github.com/lovesway/hassio-addons/mq-lightshow/models"
+	"github.com/lovesway/hassio-addons/mq-lightshow/solar"
+)
+
+const (
+	scheduleClockFormat = "15:04"
+	scheduleDateFormat  = "01-02"
+)
+
+var (
+	errScheduleTime     = errors.New("times must be HH:MM or a solar event with an offset, such as sunset+30")
+	errScheduleLocation = errors.New("solar events need the Latitude and Longitude options of the add-on")
+	errLatitude         = errors.New("latitude must be a number from -90 to 90")
+	errLongitude        = errors.New("longitude must be a number from -180 to 180")
+)
+
+// scheduleTime is a parsed start or stop time of a schedule: a time of day, or a solar event
+// moved by an offset.
+type scheduleTime struct {
+	clock  time.Time
+	event  solar.Event
+	offset time.Duration
+}
+
+// parseScheduleTime reads HH:MM, or a solar event followed by an offset in minutes or as a
+// duration, such as sunset+30, civil-dusk-15 or sunrise+1h30m.
+func parseScheduleTime(s string) (scheduleTime, error) {
+	if clock, err := time.Parse(scheduleClockFormat, s); err == nil {
+		return scheduleTime{clock: clock}, nil
+	}
+
+	st := scheduleTime{}
+
+	for _, e := range solar.Events {
+		if strings.HasPrefix(s, string(e)) && len(e) > len(st.event) {
+			st.event = e
+		}
+	}
+
+	if st.event == "" {
+		return st, fmt.Errorf("%w: %q", errScheduleTime, s)
+	}
+
+	offset := strings.TrimPrefix(s, string(st.event))
+	if offset == "" {
+		return st, nil
+	}
+
+	if offset[0] != '+' && offset[0] != '-' {
+		return st, fmt.Errorf("%w: %q", errScheduleTime, s)
+	}
+
+	if minutes, err := strconv.Atoi(offset); err == nil {
+		st.offset = time.Duration(minutes) * time.Minute
+
+		return st, nil
+	}
+
+	d, err := time.ParseDuration(offset)
+	if err != nil {
+		return st, fmt.Errorf("%w: %q", errScheduleTime, s)
+	}
+
+	st.offset = d
+
+	return st, nil
+}
+
+// scheduleTimes resolves the start and stop times of schedules to times on a day, in the time
+// zone and at the location configured for the add-on.
+type scheduleTimes struct {
+	observer solar.Observer
+	located  bool // whether a latitude and longitude are configured.
+	loc      *time.Location
+}
+
+// newScheduleTimes reads the Latitude, Longitude and TimeZone options. Without a TimeZone the
+// TZ environment variable, or else UTC, is used.
+func newScheduleTimes(conf models.Configuration) (scheduleTimes, error) {
+	st := scheduleTimes{loc: time.Local}
+
+	if conf.TimeZone != "" {
+		loc, err := time.LoadLocation(conf.TimeZone)
+		if err != nil {
+			return st, err
+		}
+
+		st.loc = loc
+	}
+
+	if conf.Latitude == "" && conf.Longitude == "" {
+		return st, nil
+	}
+
+	const maxLatitude, maxLongitude = 90, 180
+
+	lat, err := strconv.ParseFloat(conf.Latitude, 64)
+	if err != nil || lat < -maxLatitude || lat > maxLatitude {
+		return st, fmt.Errorf("%w: %q", errLatitude, conf.Latitude)
+	}
+
+	lon, err := strconv.ParseFloat(conf.Longitude, 64)
+	if err != nil || lon < -maxLongitude || lon > maxLongitude {
+		return st, fmt.Errorf("%w: %q", errLongitude, conf.Longitude)
+	}
+
+	st.observer = solar.Observer{Latitude: lat, Longitude: lon}
+	st.located = true
+
+	return st, nil
+}
+
+// now returns the current time in the time zone of the schedules.
+func (st scheduleTimes) now() time.Time {
+	return time.Now().In(st.loc)
+}
+
+// check validates a start or stop time of a schedule.
+func (st scheduleTimes) check(s string) error {
+	t, err := parseScheduleTime(s)
+	if err != nil {
+		return err
+	}
+
+	if t.event != "" && !st.located {
+		return errScheduleLocation
+	}
+
+	return nil
+}
+
+// at returns when a start or stop time happens on the day of t, to the minute. It returns false
+// when the time is invalid or when the solar event does not happen that day.
+func (st scheduleTimes) at(s string, t time.Time) (time.Time, bool) {
+	parsed, err := parseScheduleTime(s)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	if parsed.event == "" {
+		c := parsed.clock
+
+		return time.Date(t.Year(), t.Month(), t.Day(), c.Hour(), c.Minute(), 0, 0, t.Location()), true
+	}
+
+	if !st.located {
+		return time.Time{}, false
+	}
+
+	event, ok := st.observer.Time(parsed.event, t)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	return event.Add(parsed.offset).Round(time.Minute), true
+}
+
+// solarDay returns the solar events on the day of t as HH:MM, leaving out the ones that do not happen.
+func (st scheduleTimes) solarDay(t time.Time) []models.SolarEvent {
+	events := []models.SolarEvent{}
+	if !st.located {
+		return events
+	}
+
+	for _, e := range solar.Events {
+		if at, ok := st.observer.Time(e, t); ok {
+			at = at.Round(time.Minute)
+			events = append(events, models.SolarEvent{Event: string(e), Time: at.Format(scheduleClockFormat)})
+		}
+	}
+
+	return events
+}
+
+// scheduleActiveOn reports whether a schedule runs on the day of t.
+func scheduleActiveOn(sc models.Schedule, t time.Time) bool {
+	if len(sc.Days) > 0 {
+		found := false
+
+		for _, d := range sc.Days {
+			if time.Weekday(d) == t.Weekday() {
+				found = true
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return scheduleInSeason(sc, t)
+}
+
+// scheduleInSeason reports whether the day of t is in the date range of a schedule. A range
+// that ends before it starts, such as 12-01 to 01-06, runs over the new year.
+func scheduleInSeason(sc models.Schedule, t time.Time) bool {
+	if sc.StartDate == "" || sc.EndDate == "" {
+		return true
+	}
+
+	day := t.Format(scheduleDateFormat)
+
+	if sc.StartDate <= sc.EndDate {
+		return day >= sc.StartDate && day <= sc.EndDate
+	}
+
+	return day >= sc.StartDate || day <= sc.EndDate
+}
+
+// starts reports whether a schedule starts its show at minute t.
+func (st scheduleTimes) starts(sc models.Schedule, t time.Time) bool {
+	if sc.Cron != "" {
+		e, err := cron.Parse(sc.Cron)
+
+		return err == nil && e.Match(t) && scheduleActiveOn(sc, t)
+	}
+
+	start, ok := st.at(sc.StartTime, t)
+
+	return ok && start.Equal(t) && scheduleActiveOn(sc, t)
+}
+
+// stops reports whether a schedule stops its show at minute t. When the stop time is not
+// after the start time the show is stopped on the day after the day it was started.
+func (st scheduleTimes) stops(sc models.Schedule, t time.Time) bool {
+	stop, ok := st.at(sc.StopTime, t)
+	if !ok || !stop.Equal(t) {
+		return false
+	}
+
+	day := t
+
+	if start, ok := st.at(sc.StartTime, t); ok && !stop.After(start) {
+		day = t.AddDate(0, 0, -1)
+	}
+
+	return scheduleActiveOn(sc, day)
+}
+
+// inWindow reports whether t is between the start and stop time of a schedule. Only schedules
+// with both a start and a stop time have a window.
+func (st scheduleTimes) inWindow(sc models.Schedule, t time.Time) bool {
+	if sc.Cron != "" {
+		return false
+	}
+
+	for _, day := range []time.Time{t, t.AddDate(0, 0, -1)} {
+		start, ok := st.at(sc.StartTime, day)
+		if !ok {
+			continue
+		}
+
+		stop, ok := st.at(sc.StopTime, day)
+		if !ok {
+			continue
+		}
+
+		if !stop.After(start) {
+			stop = stop.AddDate(0, 0, 1)
+		}
+
+		if !t.Before(start) && t.Before(stop) && scheduleActiveOn(sc, day) {
+			return true
+		}
+	}
+
+	return false
+}
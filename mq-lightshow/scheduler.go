@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/lovesway/hassio-addons/mq-lightshow/models"
+)
+
+// maxCatchUp is how far back missed minutes are still checked when the scheduler falls
+// behind, for example after the host was suspended.
+const maxCatchUp = 5 * time.Minute
+
+// Scheduler starts and stops shows at the times of their schedules.
+type Scheduler struct {
+	md        Modeler
+	ex        *Executor
+	times     scheduleTimes
+	last      time.Time     // the last minute that was checked.
+	connected chan struct{} // signalled by Connected.
+	resumed   bool          // whether the shows that were due at startup have been resumed.
+}
+
+// NewScheduler provides an instance of Scheduler.
+func NewScheduler(md Modeler, e *Executor, times scheduleTimes) *Scheduler {
+	return &Scheduler{
+		md:        md,
+		ex:        e,
+		times:     times,
+		connected: make(chan struct{}, 1),
+	}
+}
+
+// Connected tells the scheduler that the mqtt client connected. Shows cannot be started before
+// that, so the shows that were due at startup are resumed then.
+func (s *Scheduler) Connected() {
+	select {
+	case s.connected <- struct{}{}:
+	default:
+	}
+}
+
+// Run checks the schedules at the start of every minute until ctx is done.
+func (s *Scheduler) Run(ctx context.Context) {
+	now := s.times.now()
+	s.last = now.Truncate(time.Minute)
+	s.resumed = s.resume(now)
+
+	for {
+		next := s.times.now().Truncate(time.Minute).Add(time.Minute)
+		timer := time.NewTimer(time.Until(next))
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return
+		case <-s.connected:
+			timer.Stop()
+
+			if !s.resumed {
+				s.resumed = s.resume(s.times.now())
+			}
+		case <-timer.C:
+			s.tick(s.times.now())
+		}
+	}
+}
+
+// tick checks every minute since the last check, so a late timer does not skip a schedule.
+func (s *Scheduler) tick(now time.Time) {
+	minute := now.Truncate(time.Minute)
+
+	if minute.Sub(s.last) > maxCatchUp {
+		s.last = minute.Add(-maxCatchUp)
+	}
+
+	for t := s.last.Add(time.Minute); !t.After(minute); t = t.Add(time.Minute) {
+		s.check(t)
+		s.last = t
+	}
+}
+
+// check starts and stops the shows of the schedules that fire at minute t.
+func (s *Scheduler) check(t time.Time) {
+	schedules, err := s.md.GetSchedules()
+	if err != nil {
+		log.Errorf("Scheduler: %v", err)
+
+		return
+	}
+
+	// stop first so that one schedule can hand over to another in the same minute.
+	for _, sc := range schedules {
+		if sc.Enabled && s.times.stops(sc, t) && s.ex.IsShowRunning(sc.ShowID) {
+			log.Infof("Schedule %v: stopping show %v", sc.Name, sc.ShowName)
+
+			if err := s.ex.StopShow(sc.ShowID); err != nil {
+				log.Errorf("Schedule %v: %v", sc.Name, err)
+			}
+		}
+	}
+
+	for _, sc := range schedules {
+		if sc.Enabled && s.times.starts(sc, t) {
+			s.start(sc)
+		}
+	}
+}
+
+// resume starts the shows that should be running at startup because their schedule was
+// already between its start and stop time, for example after the add-on was restarted. It
+// returns false when the mqtt client is not connected yet, resume is called again by Run when
+// it connects.
+func (s *Scheduler) resume(now time.Time) bool {
+	if !s.ex.mq.IsConnected() {
+		log.Info("Scheduler: waiting for the mqtt connection to resume scheduled shows")
+
+		return false
+	}
+
+	schedules, err := s.md.GetSchedules()
+	if err != nil {
+		log.Errorf("Scheduler: %v", err)
+
+		return true
+	}
+
+	for _, sc := range schedules {
+		if sc.Enabled && s.times.inWindow(sc, now) {
+			s.start(sc)
+		}
+	}
+
+	return true
+}
+
+func (s *Scheduler) start(sc models.Schedule) {
+	if s.ex.IsShowRunning(sc.ShowID) {
+		return
+	}
+
+	log.Infof("Schedule %v: starting show %v", sc.Name, sc.ShowName)
+
+	if err := s.ex.StartShow(sc.ShowID); err != nil {
+		log.Errorf("Schedule %v: %v", sc.Name, err)
+	}
+}
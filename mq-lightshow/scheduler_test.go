@@ -0,0 +1,252 @@
+package main_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	MQTT "github.com/eclipse/paho.mqtt.golang"
+	main "github.com/lovesway/hassio-addons/mq-lightshow"
+	"github.com/lovesway/hassio-addons/mq-lightshow/devicetypes"
+	"github.com/lovesway/hassio-addons/mq-lightshow/models"
+)
+
+// at returns the time of day on a day.
+func at(day time.Time, hour int, minute int) time.Time {
+	return day.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+}
+
+func TestScheduleActiveOn(t *testing.T) {
+	t.Parallel()
+
+	// Friday the 13th of December.
+	friday := time.Date(2024, time.December, 13, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		days      []int
+		startDate string
+		endDate   string
+		t         time.Time
+		want      bool
+	}{
+		{t: friday, want: true},
+		{days: []int{int(time.Friday)}, t: friday, want: true},
+		{days: []int{int(time.Monday), int(time.Friday)}, t: friday, want: true},
+		{days: []int{int(time.Saturday), int(time.Sunday)}, t: friday, want: false},
+		{startDate: "12-01", endDate: "12-31", t: friday, want: true},
+		{startDate: "12-13", endDate: "12-13", t: friday, want: true},
+		{startDate: "01-01", endDate: "06-30", t: friday, want: false},
+		{startDate: "12-14", endDate: "12-31", t: friday, want: false},
+		// a range that ends before it starts runs over the new year.
+		{startDate: "12-01", endDate: "01-06", t: friday, want: true},
+		{startDate: "12-01", endDate: "01-06", t: time.Date(2025, time.January, 3, 0, 0, 0, 0, time.UTC), want: true},
+		{startDate: "12-01", endDate: "01-06", t: time.Date(2025, time.February, 1, 0, 0, 0, 0, time.UTC), want: false},
+		// a range needs both dates.
+		{startDate: "01-01", t: friday, want: true},
+		{days: []int{int(time.Friday)}, startDate: "01-01", endDate: "06-30", t: friday, want: false},
+		{days: []int{int(time.Monday)}, startDate: "12-01", endDate: "12-31", t: friday, want: false},
+	}
+
+	for _, tt := range tests {
+		sc := models.Schedule{Days: tt.days, StartDate: tt.startDate, EndDate: tt.endDate}
+
+		if got := main.ScheduleActiveOn(sc, tt.t); got != tt.want {
+			t.Errorf("ScheduleActiveOn(%v, %v to %v, %v) = %v, want %v",
+				tt.days, tt.startDate, tt.endDate, tt.t.Format("2006-01-02"), got, tt.want)
+		}
+	}
+}
+
+func TestScheduleStartsAndStops(t *testing.T) {
+	t.Parallel()
+
+	times, err := main.NewScheduleTimes(models.Configuration{TimeZone: "UTC"})
+	if err != nil {
+		t.Fatalf("NewScheduleTimes error = %v", err)
+	}
+
+	evening := models.Schedule{StartTime: "18:30", StopTime: "23:00", Days: []int{int(time.Friday)}}
+	overnight := models.Schedule{StartTime: "22:00", StopTime: "02:00", Days: []int{int(time.Friday)}}
+	cron := models.Schedule{Cron: "30 18 * * *"}
+	friday := time.Date(2024, time.December, 13, 0, 0, 0, 0, time.UTC)
+	saturday := friday.AddDate(0, 0, 1)
+
+	tests := []struct {
+		name   string
+		sc     models.Schedule
+		t      time.Time
+		starts bool
+		stops  bool
+	}{
+		{name: "evening start", sc: evening, t: at(friday, 18, 30), starts: true},
+		{name: "evening after the start", sc: evening, t: at(friday, 18, 31)},
+		{name: "evening stop", sc: evening, t: at(friday, 23, 0), stops: true},
+		{name: "evening on another day", sc: evening, t: at(saturday, 18, 30)},
+		{name: "overnight start", sc: overnight, t: at(friday, 22, 0), starts: true},
+		// the show is stopped on the day after the day it was started.
+		{name: "overnight stop", sc: overnight, t: at(saturday, 2, 0), stops: true},
+		{name: "overnight stop of thursday", sc: overnight, t: at(friday, 2, 0)},
+		{name: "cron", sc: cron, t: at(friday, 18, 30), starts: true},
+		{name: "cron on another day", sc: models.Schedule{Cron: cron.Cron, Days: []int{1}}, t: at(friday, 18, 30)},
+		{name: "solar event without a location", sc: models.Schedule{StartTime: "sunset"}, t: at(friday, 16, 0)},
+		{name: "invalid time", sc: models.Schedule{StartTime: "25:00"}, t: at(friday, 1, 0)},
+	}
+
+	for _, tt := range tests {
+		if got := main.ScheduleStarts(times, tt.sc, tt.t); got != tt.starts {
+			t.Errorf("%v: starts at %v = %v, want %v", tt.name, tt.t, got, tt.starts)
+		}
+
+		if got := main.ScheduleStops(times, tt.sc, tt.t); got != tt.stops {
+			t.Errorf("%v: stops at %v = %v, want %v", tt.name, tt.t, got, tt.stops)
+		}
+	}
+}
+
+func TestScheduleInWindow(t *testing.T) {
+	t.Parallel()
+
+	times, err := main.NewScheduleTimes(models.Configuration{TimeZone: "UTC"})
+	if err != nil {
+		t.Fatalf("NewScheduleTimes error = %v", err)
+	}
+
+	evening := models.Schedule{StartTime: "18:00", StopTime: "23:00"}
+	overnight := models.Schedule{StartTime: "22:00", StopTime: "02:00", Days: []int{int(time.Friday)}}
+	friday := time.Date(2024, time.December, 13, 0, 0, 0, 0, time.UTC)
+	saturday := friday.AddDate(0, 0, 1)
+
+	tests := []struct {
+		name string
+		sc   models.Schedule
+		t    time.Time
+		want bool
+	}{
+		{name: "evening before", sc: evening, t: at(friday, 17, 59), want: false},
+		{name: "evening start", sc: evening, t: at(friday, 18, 0), want: true},
+		{name: "evening during", sc: evening, t: at(friday, 20, 0), want: true},
+		{name: "evening stop", sc: evening, t: at(friday, 23, 0), want: false},
+		{name: "overnight before midnight", sc: overnight, t: at(friday, 23, 0), want: true},
+		{name: "overnight after midnight", sc: overnight, t: at(saturday, 1, 0), want: true},
+		{name: "overnight of thursday", sc: overnight, t: at(friday, 1, 0), want: false},
+		{name: "overnight on saturday", sc: overnight, t: at(saturday, 23, 0), want: false},
+		{name: "no stop time", sc: models.Schedule{StartTime: "18:00"}, t: at(friday, 20, 0), want: false},
+		{name: "cron", sc: models.Schedule{Cron: "* * * * *", StartTime: "18:00", StopTime: "23:00"}, t: at(friday, 20, 0)},
+	}
+
+	for _, tt := range tests {
+		if got := main.ScheduleInWindow(times, tt.sc, tt.t); got != tt.want {
+			t.Errorf("%v: inWindow at %v = %v, want %v", tt.name, tt.t, got, tt.want)
+		}
+	}
+}
+
+// doneToken is the token of an mqtt operation that has completed.
+type doneToken struct{}
+
+func (doneToken) Wait() bool                     { return true }
+func (doneToken) WaitTimeout(time.Duration) bool { return true }
+func (doneToken) Error() error                   { return nil }
+
+func (doneToken) Done() <-chan struct{} {
+	done := make(chan struct{})
+	close(done)
+
+	return done
+}
+
+// fakeClient is an mqtt client that records what is published to it. Other calls panic.
+type fakeClient struct {
+	MQTT.Client
+	connected atomic.Bool
+	mu        sync.Mutex
+	published map[string][]interface{} // the payloads of each topic.
+}
+
+func (c *fakeClient) IsConnected() bool {
+	return c.connected.Load()
+}
+
+func (c *fakeClient) Publish(topic string, qos byte, retained bool, payload interface{}) MQTT.Token {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.published[topic] = append(c.published[topic], payload)
+
+	return doneToken{}
+}
+
+func (c *fakeClient) payloads(topic string) []interface{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]interface{}{}, c.published[topic]...)
+}
+
+//nolint:paralleltest // the Modeler uses the global Executor.
+func TestSchedulerResumesAfterConnect(t *testing.T) {
+	db := openDatabase(t)
+
+	times, err := main.NewScheduleTimes(models.Configuration{TimeZone: "UTC"})
+	if err != nil {
+		t.Fatalf("NewScheduleTimes error = %v", err)
+	}
+
+	showID, err := db.AddShow(models.Show{Name: "evening", Topic: "evening", ConflictPolicy: "share"})
+	if err != nil {
+		t.Fatalf("AddShow error = %v", err)
+	}
+
+	// the schedule is already between its start and stop time when the scheduler starts.
+	now := time.Now().UTC()
+
+	_, err = db.AddSchedule(models.Schedule{
+		Name:      "evening",
+		ShowID:    showID,
+		Enabled:   true,
+		StartTime: now.Add(-time.Hour).Format("15:04"),
+		StopTime:  now.Add(time.Hour).Format("15:04"),
+	})
+	if err != nil {
+		t.Fatalf("AddSchedule error = %v", err)
+	}
+
+	md := main.NewModler(db)
+	mq := main.NewMQController(md, devicetypes.NewDeviceTypes())
+	client := &fakeClient{published: map[string][]interface{}{}}
+	mq.SetClient(client)
+
+	e := main.NewExecutor(md, mq, "")
+	main.SetExecutor(e)
+
+	s := main.NewScheduler(md, e, times)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go s.Run(ctx)
+
+	const state = "mqlightshow/show/evening/stat"
+
+	// the show cannot start before the client is connected.
+	time.Sleep(50 * time.Millisecond)
+
+	if payloads := client.payloads(state); len(payloads) != 0 {
+		t.Fatalf("show states before the connection = %v, want none", payloads)
+	}
+
+	client.connected.Store(true)
+	s.Connected()
+
+	deadline := time.Now().Add(time.Second)
+	for len(client.payloads(state)) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// the show has no cycles, so it may have finished already.
+	if payloads := client.payloads(state); len(payloads) == 0 || payloads[0] != "ON" {
+		t.Errorf("show states after the connection = %v, want ON first", payloads)
+	}
+}
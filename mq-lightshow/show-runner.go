@@ -0,0 +1,357 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/lovesway/hassio-addons/mq-lightshow/models"
+)
+
+// ShowRunner tracks all instances of running shows. It is safe for concurrent use by
+// the HTTP handlers, the MQTT callback and the show goroutines.
+type ShowRunner struct {
+	mu       sync.Mutex
+	shows    map[int]*RunningShow
+	stopping map[int]*RunningShow // runs that ended and are still restoring their lights.
+}
+
+// noJump marks that no cycle jump has been requested.
+const noJump = -1
+
+// RunningShow tracks an instance of a running show. Its context is cancelled when the show is stopped.
+type RunningShow struct {
+	ShowID    int
+	Show      models.Show
+	StartedAt time.Time
+	ctx       context.Context
+	cancel    context.CancelFunc
+	done      chan struct{} // closed when the show has stopped and restored its lights.
+
+	mu          sync.Mutex
+	paused      bool
+	resumed     chan struct{}      // closed when a paused show is resumed.
+	cycle       int                // index of the cycle currently running.
+	cycleCancel context.CancelFunc // cancels the current cycle when skipping.
+	jump        int                // index of the cycle to continue with, or noJump.
+	sceneCycle  int                // repetition of the cycle's scene currently running, from 1.
+	groupOrder  int                // order of the scene group currently running.
+	loop        int                // number of times a repeating show has started over.
+}
+
+// NewShowRunner provides an instance of ShowRunner.
+func NewShowRunner() *ShowRunner {
+	return &ShowRunner{
+		shows:    map[int]*RunningShow{},
+		stopping: map[int]*RunningShow{},
+	}
+}
+
+// Start registers a show as running. It returns false if the show is already running. When
+// an earlier run of the show is still restoring its lights, Start waits for it first, for at
+// most stopWait, so that the new run does not take the lights the old run is about to restore.
+func (sr *ShowRunner) Start(show models.Show) (*RunningShow, bool) {
+	sr.mu.Lock()
+	previous, ok := sr.stopping[show.ID]
+	sr.mu.Unlock()
+
+	if ok {
+		timer := time.NewTimer(stopWait)
+
+		select {
+		case <-previous.done:
+		case <-timer.C:
+			log.Warnf("Show %v: starting before the previous run restored its lights", show.Name)
+		}
+
+		timer.Stop()
+	}
+
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	if _, ok := sr.shows[show.ID]; ok {
+		return nil, false
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	rs := &RunningShow{
+		ShowID:    show.ID,
+		Show:      show,
+		StartedAt: time.Now(),
+		ctx:       ctx,
+		cancel:    cancel,
+		done:      make(chan struct{}),
+		jump:      noJump,
+	}
+
+	sr.shows[show.ID] = rs
+
+	return rs, true
+}
+
+// Stop cancels a running show and removes it. It returns false if the show was not running.
+// The run is kept as stopping until Done is called for it.
+func (sr *ShowRunner) Stop(showID int) bool {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	rs, ok := sr.shows[showID]
+	if !ok {
+		return false
+	}
+
+	rs.cancel()
+	sr.end(rs)
+
+	return true
+}
+
+// StopAll cancels and removes all running shows. It returns every run that has not called Done
+// yet, including runs that were stopped before, so that they can be waited for.
+func (sr *ShowRunner) StopAll() []*RunningShow {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	for _, rs := range sr.shows {
+		rs.cancel()
+		sr.end(rs)
+	}
+
+	stopped := []*RunningShow{}
+
+	for _, rs := range sr.stopping {
+		stopped = append(stopped, rs)
+	}
+
+	return stopped
+}
+
+// Finish removes a show that ran to completion. It returns false if the run was already
+// stopped, which keeps a finishing run from removing a newer run of the same show.
+func (sr *ShowRunner) Finish(rs *RunningShow) bool {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	rs.cancel()
+
+	if sr.shows[rs.ShowID] != rs {
+		return false
+	}
+
+	sr.end(rs)
+
+	return true
+}
+
+// end moves a run from the running shows to the stopping ones. The caller holds mu.
+func (sr *ShowRunner) end(rs *RunningShow) {
+	delete(sr.shows, rs.ShowID)
+	sr.stopping[rs.ShowID] = rs
+}
+
+// Done is called by a run when it has ended and restored its lights.
+func (sr *ShowRunner) Done(rs *RunningShow) {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	if sr.stopping[rs.ShowID] == rs {
+		delete(sr.stopping, rs.ShowID)
+	}
+
+	close(rs.done)
+}
+
+// Get returns the running instance of a show.
+func (sr *ShowRunner) Get(showID int) (*RunningShow, bool) {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	rs, ok := sr.shows[showID]
+
+	return rs, ok
+}
+
+// Running returns the running instances of all shows.
+func (sr *ShowRunner) Running() []*RunningShow {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	running := make([]*RunningShow, 0, len(sr.shows))
+
+	for _, rs := range sr.shows {
+		running = append(running, rs)
+	}
+
+	return running
+}
+
+// IsRunning to determine whether or not a show is running.
+func (sr *ShowRunner) IsRunning(showID int) bool {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	_, ok := sr.shows[showID]
+
+	return ok
+}
+
+// Pause holds the show at its next action. It returns false if the show is already paused.
+func (rs *RunningShow) Pause() bool {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	if rs.paused {
+		return false
+	}
+
+	rs.paused = true
+	rs.resumed = make(chan struct{})
+
+	return true
+}
+
+// Resume continues a paused show. It returns false if the show is not paused.
+func (rs *RunningShow) Resume() bool {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	if !rs.paused {
+		return false
+	}
+
+	rs.paused = false
+	close(rs.resumed)
+
+	return true
+}
+
+// IsPaused to determine whether or not the show is paused.
+func (rs *RunningShow) IsPaused() bool {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	return rs.paused
+}
+
+// waitIfPaused blocks while the show is paused, returning early when ctx is cancelled.
+func (rs *RunningShow) waitIfPaused(ctx context.Context) {
+	rs.mu.Lock()
+	paused, resumed := rs.paused, rs.resumed
+	rs.mu.Unlock()
+
+	if !paused {
+		return
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-resumed:
+	}
+}
+
+// beginCycle records the cycle being run and returns a context that is cancelled when
+// the show is stopped or the cycle is skipped.
+func (rs *RunningShow) beginCycle(index int) context.Context {
+	ctx, cancel := context.WithCancel(rs.ctx)
+
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	if rs.cycleCancel != nil {
+		rs.cycleCancel()
+	}
+
+	rs.cycle = index
+	rs.cycleCancel = cancel
+	rs.sceneCycle = 0
+	rs.groupOrder = 0
+
+	return ctx
+}
+
+// Cycle returns the index of the cycle currently running.
+func (rs *RunningShow) Cycle() int {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	return rs.cycle
+}
+
+// Skip abandons the current cycle. When index is not noJump the show continues with that cycle,
+// otherwise it continues with the cycle that would have followed.
+func (rs *RunningShow) Skip(index int) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	rs.jump = index
+
+	if rs.cycleCancel != nil {
+		rs.cycleCancel()
+	}
+}
+
+// takeJump returns and clears a requested cycle jump.
+func (rs *RunningShow) takeJump() (int, bool) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	index := rs.jump
+	rs.jump = noJump
+
+	return index, index != noJump
+}
+
+// beginSceneCycle records the repetition of the cycle's scene being run.
+func (rs *RunningShow) beginSceneCycle(sceneCycle int) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	rs.sceneCycle = sceneCycle
+}
+
+// beginGroup records the scene group being run.
+func (rs *RunningShow) beginGroup(order int) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	rs.groupOrder = order
+}
+
+// beginLoop records that a repeating show has started over.
+func (rs *RunningShow) beginLoop() {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	rs.loop++
+}
+
+// Progress returns a snapshot of where the show is.
+func (rs *RunningShow) Progress() models.ShowProgress {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	p := models.ShowProgress{
+		ShowID:     rs.ShowID,
+		Name:       rs.Show.Name,
+		Running:    rs.ctx.Err() == nil,
+		Paused:     rs.paused,
+		CycleIndex: rs.cycle,
+		SceneCycle: rs.sceneCycle,
+		GroupOrder: rs.groupOrder,
+		Loop:       rs.loop,
+		StartedAt:  rs.StartedAt,
+		Elapsed:    time.Since(rs.StartedAt).Seconds(),
+	}
+
+	if rs.cycle < len(rs.Show.Cycles) {
+		cycle := rs.Show.Cycles[rs.cycle]
+		p.CycleID = cycle.ID
+		p.SceneID = cycle.Scene.ID
+		p.SceneName = cycle.Scene.Name
+		p.EffectID = cycle.Effect.ID
+		p.EffectName = cycle.Effect.Name
+	}
+
+	return p
+}
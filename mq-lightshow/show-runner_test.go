@@ -0,0 +1,248 @@
+package main_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	main "github.com/lovesway/hassio-addons/mq-lightshow"
+	"github.com/lovesway/hassio-addons/mq-lightshow/models"
+)
+
+func TestShowRunner(t *testing.T) {
+	t.Parallel()
+
+	// each step is applied to show 1, running and stopping are what the runner reports after it.
+	type step struct {
+		op       string // start, stop, finish, done or stopall.
+		want     bool   // what start, stop or finish returns.
+		running  bool
+		stopping int // number of runs that are still restoring their lights.
+	}
+
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "start and stop",
+			steps: []step{
+				{op: "start", want: true, running: true},
+				{op: "stop", want: true, stopping: 1},
+				{op: "done"},
+			},
+		},
+		{
+			name: "start twice",
+			steps: []step{
+				{op: "start", want: true, running: true},
+				{op: "start", want: false, running: true},
+			},
+		},
+		{
+			name: "stop a show that is not running",
+			steps: []step{
+				{op: "stop", want: false},
+			},
+		},
+		{
+			name: "finish",
+			steps: []step{
+				{op: "start", want: true, running: true},
+				{op: "finish", want: true, stopping: 1},
+				{op: "done"},
+			},
+		},
+		{
+			name: "finish after stop",
+			steps: []step{
+				{op: "start", want: true, running: true},
+				{op: "stop", want: true, stopping: 1},
+				{op: "finish", want: false, stopping: 1},
+				{op: "done"},
+			},
+		},
+		{
+			name: "stop all",
+			steps: []step{
+				{op: "start", want: true, running: true},
+				{op: "stopall", stopping: 1},
+				{op: "stop", want: false, stopping: 1},
+				{op: "done"},
+			},
+		},
+	}
+
+	show := models.Show{ID: 1, Name: "show"}
+
+	for _, tt := range tests {
+		sr := main.NewShowRunner()
+
+		var rs *main.RunningShow
+
+		for i, s := range tt.steps {
+			var got bool
+
+			switch s.op {
+			case "start":
+				var started *main.RunningShow
+
+				started, got = sr.Start(show)
+				if got {
+					rs = started
+				}
+			case "stop":
+				got = sr.Stop(show.ID)
+			case "finish":
+				got = sr.Finish(rs)
+			case "done":
+				sr.Done(rs)
+
+				if !rs.IsDone() {
+					t.Errorf("%v: step %v: the run is not done", tt.name, i)
+				}
+			case "stopall":
+				sr.StopAll()
+			}
+
+			if got != s.want {
+				t.Errorf("%v: step %v: %v = %v, want %v", tt.name, i, s.op, got, s.want)
+			}
+
+			if running := sr.IsRunning(show.ID); running != s.running {
+				t.Errorf("%v: step %v: IsRunning = %v, want %v", tt.name, i, running, s.running)
+			}
+
+			if stopping := sr.Stopping(); stopping != s.stopping {
+				t.Errorf("%v: step %v: %v runs are stopping, want %v", tt.name, i, stopping, s.stopping)
+			}
+		}
+	}
+}
+
+func TestShowRunnerFinishKeepsNewerRun(t *testing.T) {
+	t.Parallel()
+
+	show := models.Show{ID: 1, Name: "show"}
+	sr := main.NewShowRunner()
+
+	old, _ := sr.Start(show)
+	sr.Stop(show.ID)
+	sr.Done(old)
+	sr.Start(show)
+
+	if sr.Finish(old) {
+		t.Errorf("Finish of a stopped run = true, want false")
+	}
+
+	if !sr.IsRunning(show.ID) {
+		t.Errorf("Finish of a stopped run removed the newer run")
+	}
+}
+
+func TestShowRunnerConcurrentStart(t *testing.T) {
+	t.Parallel()
+
+	const starts = 20
+
+	show := models.Show{ID: 1, Name: "show"}
+	sr := main.NewShowRunner()
+	started := make(chan bool, starts)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < starts; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			_, ok := sr.Start(show)
+			started <- ok
+		}()
+	}
+
+	wg.Wait()
+	close(started)
+
+	count := 0
+
+	for ok := range started {
+		if ok {
+			count++
+		}
+	}
+
+	if count != 1 {
+		t.Errorf("%v concurrent starts succeeded, want 1", count)
+	}
+}
+
+func TestShowRunnerStartWaitsForStopping(t *testing.T) {
+	t.Parallel()
+
+	const restore = 50 * time.Millisecond
+
+	sr := main.NewShowRunner()
+	show := models.Show{ID: 1, Name: "show"}
+
+	previous, _ := sr.Start(show)
+	sr.Stop(show.ID)
+
+	go func() {
+		time.Sleep(restore)
+		sr.Done(previous)
+	}()
+
+	rs, ok := sr.Start(show)
+	if !ok {
+		t.Fatalf("Start after Stop = false, want true")
+	}
+
+	if !previous.IsDone() {
+		t.Errorf("Start returned before the previous run was done")
+	}
+
+	if sr.Stopping() != 0 {
+		t.Errorf("the previous run is still stopping")
+	}
+
+	if stopped := sr.StopAll(); len(stopped) != 1 || stopped[0] != rs {
+		t.Errorf("StopAll = %v, want the new run", stopped)
+	}
+}
+
+func TestStopAllShows(t *testing.T) {
+	t.Parallel()
+
+	e := main.NewTestExecutor()
+	shows := []models.Show{{ID: 1, Name: "one"}, {ID: 2, Name: "two"}}
+	runs := []*main.RunningShow{}
+
+	for _, show := range shows {
+		rs, ok := e.Runner().Start(show)
+		if !ok {
+			t.Fatalf("Start(%v) = false, want true", show.Name)
+		}
+
+		runs = append(runs, rs)
+
+		// what runShow does when it is stopped.
+		go func() {
+			<-rs.Stopped()
+			e.Runner().Done(rs)
+		}()
+	}
+
+	e.StopAllShows()
+
+	for _, rs := range runs {
+		if !rs.IsDone() {
+			t.Errorf("StopAllShows returned before %v was done", rs.Show.Name)
+		}
+
+		if e.IsShowRunning(rs.ShowID) {
+			t.Errorf("%v is still running", rs.Show.Name)
+		}
+	}
+}
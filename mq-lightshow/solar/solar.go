@@ -0,0 +1,142 @@
+// Package solar computes the times of sunrise, sunset and twilight offline, using the sunrise
+// equation as published by NOAA. The results are accurate to about a minute away from the poles.
+package solar
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+var errEvent = errors.New("unknown solar event")
+
+// Event is a moment of the day defined by the height of the sun.
+type Event string
+
+// The events that can be computed, in the order they happen during a day.
+const (
+	NauticalDawn Event = "nautical-dawn"
+	CivilDawn    Event = "civil-dawn"
+	Sunrise      Event = "sunrise"
+	SolarNoon    Event = "noon"
+	Sunset       Event = "sunset"
+	CivilDusk    Event = "civil-dusk"
+	NauticalDusk Event = "nautical-dusk"
+)
+
+// Events lists every Event in the order they happen during a day.
+var Events = []Event{NauticalDawn, CivilDawn, Sunrise, SolarNoon, Sunset, CivilDusk, NauticalDusk}
+
+const (
+	// elevations of the center of the sun in degrees. Sunrise and sunset allow for refraction
+	// and the size of the sun.
+	elevationSunrise  = -0.833
+	elevationCivil    = -6
+	elevationNautical = -12
+
+	julian2000    = 2451545.0 // julian day of 2000-01-01 12:00 UTC.
+	julianUnix    = 2440587.5 // julian day of 1970-01-01 00:00 UTC.
+	secondsPerDay = 86400
+	degrees       = 360
+	obliquity     = 23.4397 // tilt of the axis of the earth in degrees.
+	perihelion    = 102.9372
+	halfCircle    = 180
+)
+
+// ParseEvent returns the Event with the given name.
+func ParseEvent(s string) (Event, error) {
+	for _, e := range Events {
+		if string(e) == s {
+			return e, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: %q", errEvent, s)
+}
+
+// Observer is a place on earth, with the latitude in degrees north and the longitude in
+// degrees east (negative for west).
+type Observer struct {
+	Latitude  float64
+	Longitude float64
+}
+
+func radians(d float64) float64 {
+	return d * math.Pi / halfCircle
+}
+
+func toDegrees(r float64) float64 {
+	return r * halfCircle / math.Pi
+}
+
+func fromJulian(j float64, loc *time.Location) time.Time {
+	unix := (j - julianUnix) * secondsPerDay
+
+	return time.Unix(int64(math.Round(unix)), 0).In(loc)
+}
+
+// Time returns when an event happens on the day of t, in the location of t. It returns false
+// when the event does not happen that day, such as sunset during the polar day.
+func (o Observer) Time(event Event, t time.Time) (time.Time, bool) {
+	const (
+		noonHour = 12
+
+		anomalyAtEpoch = 357.5291   // mean anomaly of the sun in 2000 in degrees.
+		anomalyPerDay  = 0.98560028 // degrees.
+		center1        = 1.9148     // terms of the equation of the center.
+		center2        = 0.02
+		center3        = 0.0003
+		triple         = 3
+		eccentricity   = 0.0053 // terms of the equation of time.
+		tilt           = 0.0069
+	)
+
+	noon := time.Date(t.Year(), t.Month(), t.Day(), noonHour, 0, 0, 0, time.UTC)
+
+	// mean solar noon in days since 2000-01-01 12:00 UTC.
+	n := math.Round(float64(noon.Unix())/secondsPerDay + julianUnix - julian2000)
+	mean := n - o.Longitude/degrees
+
+	anomaly := math.Mod(anomalyAtEpoch+anomalyPerDay*mean, degrees)
+	m := radians(anomaly)
+	center := center1*math.Sin(m) + center2*math.Sin(2*m) + center3*math.Sin(triple*m)
+	lambda := radians(math.Mod(anomaly+center+halfCircle+perihelion, degrees))
+	transit := julian2000 + mean + eccentricity*math.Sin(m) - tilt*math.Sin(2*lambda)
+
+	if event == SolarNoon {
+		return fromJulian(transit, t.Location()), true
+	}
+
+	var elevation float64
+
+	switch event {
+	case Sunrise, Sunset:
+		elevation = elevationSunrise
+	case CivilDawn, CivilDusk:
+		elevation = elevationCivil
+	case NauticalDawn, NauticalDusk:
+		elevation = elevationNautical
+	default:
+		return time.Time{}, false
+	}
+
+	sinDeclination := math.Sin(lambda) * math.Sin(radians(obliquity))
+	cosDeclination := math.Cos(math.Asin(sinDeclination))
+	phi := radians(o.Latitude)
+
+	cosHourAngle := (math.Sin(radians(elevation)) - math.Sin(phi)*sinDeclination) /
+		(math.Cos(phi) * cosDeclination)
+	if cosHourAngle < -1 || cosHourAngle > 1 {
+		return time.Time{}, false
+	}
+
+	offset := toDegrees(math.Acos(cosHourAngle)) / degrees
+
+	switch event {
+	case NauticalDawn, CivilDawn, Sunrise:
+		return fromJulian(transit-offset, t.Location()), true
+	default:
+		return fromJulian(transit+offset, t.Location()), true
+	}
+}
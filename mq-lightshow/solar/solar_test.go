@@ -0,0 +1,86 @@
+package solar_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lovesway/hassio-addons/mq-lightshow/solar"
+)
+
+func TestTime(t *testing.T) {
+	t.Parallel()
+
+	london, err := time.LoadLocation("Europe/London")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	londonObserver := solar.Observer{Latitude: 51.5074, Longitude: -0.1278}
+	newYorkObserver := solar.Observer{Latitude: 40.7128, Longitude: -74.0060}
+	midsummer := time.Date(2024, time.June, 21, 0, 0, 0, 0, london)
+	midwinter := time.Date(2024, time.December, 21, 0, 0, 0, 0, newYork)
+
+	// reference times from the NOAA solar calculator.
+	tests := []struct {
+		observer solar.Observer
+		event    solar.Event
+		day      time.Time
+		want     time.Time
+	}{
+		{londonObserver, solar.Sunrise, midsummer, time.Date(2024, time.June, 21, 4, 43, 0, 0, london)},
+		{londonObserver, solar.Sunset, midsummer, time.Date(2024, time.June, 21, 21, 21, 0, 0, london)},
+		{londonObserver, solar.SolarNoon, midsummer, time.Date(2024, time.June, 21, 13, 2, 0, 0, london)},
+		{newYorkObserver, solar.Sunrise, midwinter, time.Date(2024, time.December, 21, 7, 16, 0, 0, newYork)},
+		{newYorkObserver, solar.Sunset, midwinter, time.Date(2024, time.December, 21, 16, 32, 0, 0, newYork)},
+		{newYorkObserver, solar.CivilDawn, midwinter, time.Date(2024, time.December, 21, 6, 45, 0, 0, newYork)},
+		{newYorkObserver, solar.NauticalDusk, midwinter, time.Date(2024, time.December, 21, 17, 37, 0, 0, newYork)},
+	}
+
+	const tolerance = 2 * time.Minute
+
+	for _, tt := range tests {
+		got, ok := tt.observer.Time(tt.event, tt.day)
+		if !ok {
+			t.Errorf("%v on %v did not happen", tt.event, tt.day)
+
+			continue
+		}
+
+		if diff := got.Sub(tt.want); diff > tolerance || diff < -tolerance {
+			t.Errorf("%v on %v = %v, want %v", tt.event, tt.day.Format("2006-01-02"), got, tt.want)
+		}
+	}
+}
+
+func TestTimePolar(t *testing.T) {
+	t.Parallel()
+
+	tromso := solar.Observer{Latitude: 69.6492, Longitude: 18.9553}
+
+	if _, ok := tromso.Time(solar.Sunrise, time.Date(2024, time.December, 21, 0, 0, 0, 0, time.UTC)); ok {
+		t.Error("the sun rose during the polar night")
+	}
+
+	if _, ok := tromso.Time(solar.Sunset, time.Date(2024, time.June, 21, 0, 0, 0, 0, time.UTC)); ok {
+		t.Error("the sun set during the polar day")
+	}
+}
+
+func TestParseEvent(t *testing.T) {
+	t.Parallel()
+
+	for _, e := range solar.Events {
+		if got, err := solar.ParseEvent(string(e)); err != nil || got != e {
+			t.Errorf("ParseEvent(%q) = %v, %v", e, got, err)
+		}
+	}
+
+	if _, err := solar.ParseEvent("dusk"); err == nil {
+		t.Error("ParseEvent(\"dusk\") did not fail")
+	}
+}
@@ -123,8 +123,14 @@ func (ss StringsToStruct) Show(in interface{}, out models.Show) (models.Show, er
 			out.GlobalSpeed = fieldValInt
 		} else if field.Name == globalParameter1 {
 			out.GlobalParameter1 = fieldValString
-		} else if field.Name == out.GlobalParameter2 {
+		} else if field.Name == globalParameter2 {
 			out.GlobalParameter2 = fieldValString
+		} else if field.Name == "PaletteID" {
+			out.PaletteID = fieldValInt
+		} else if field.Name == "Priority" {
+			out.Priority = fieldValInt
+		} else if field.Name == "ConflictPolicy" {
+			out.ConflictPolicy = fieldValString
 		}
 	}
 
@@ -223,6 +229,8 @@ func (ss StringsToStruct) Cycle(in interface{}, out models.Cycle) (models.Cycle,
 			out.ShowID = fieldValInt
 		} else if field.Name == "SceneID" {
 			out.SceneID = fieldValInt
+		} else if field.Name == "EffectID" {
+			out.EffectID = fieldValInt
 		} else if field.Name == "SceneCycles" {
 			out.SceneCycles = fieldValInt
 		} else if field.Name == "EndDelay" {
@@ -434,6 +442,12 @@ func (ss StringsToStruct) Group(in interface{}, out models.Group) (models.Group,
 			out.Delay = fieldValFloat32
 		} else if field.Name == globalDelay {
 			out.GlobalDelay = fieldValBool
+		} else if field.Name == "FadeDuration" {
+			out.FadeDuration = fieldValFloat32
+		} else if field.Name == "FadeRate" {
+			out.FadeRate = fieldValInt
+		} else if field.Name == "FadeEasing" {
+			out.FadeEasing = fieldValString
 		} else if field.Name == "Order" {
 			out.Order = fieldValInt
 		}
@@ -532,12 +546,24 @@ func (ss StringsToStruct) Action(in interface{}, out models.Action) (models.Acti
 			log.Errorf("Unsupported type of '%s' in %v", field.Type, field.Name)
 		}
 
-		if field.Name == "Command" {
+		if field.Name == "Type" {
+			out.Type = fieldValString
+		} else if field.Name == "Command" {
 			out.Command = fieldValString
 		} else if field.Name == "Parameter" {
 			out.Parameter = fieldValString
 		} else if field.Name == "GlobalParameter" {
 			out.GlobalParameter = fieldValString
+		} else if field.Name == "Color" {
+			out.Color = fieldValString
+		} else if field.Name == "CT" {
+			out.CT = fieldValInt
+		} else if field.Name == "Brightness" {
+			out.Brightness = fieldValInt
+		} else if field.Name == "Transition" {
+			out.Transition = fieldValFloat64
+		} else if field.Name == "PaletteColor" {
+			out.PaletteColor = fieldValString
 		} else if field.Name == "Order" {
 			out.Order = fieldValInt
 		}
@@ -545,3 +571,342 @@ func (ss StringsToStruct) Action(in interface{}, out models.Action) (models.Acti
 
 	return out, err
 }
+
+// Device will convert for a Device model.
+func (ss StringsToStruct) Device(in interface{}, out models.Device) (models.Device, error) {
+	fieldsIn := reflect.TypeOf(in)
+	valuesIn := reflect.ValueOf(in)
+
+	fields := reflect.TypeOf(out)
+	values := reflect.ValueOf(out)
+	num := fields.NumField()
+
+	var err error
+
+	for i := 0; i < num; i++ {
+		field := fields.Field(i)
+		value := values.Field(i)
+
+		_, found := fieldsIn.FieldByName(field.Name)
+		if !found {
+			continue
+		}
+
+		fieldVal := valuesIn.FieldByName(field.Name).String()
+		if fieldVal == "" {
+			continue
+		}
+
+		var (
+			fieldValString  string
+			fieldValBool    bool
+			fieldValInt     int
+			fieldValInt32   int32
+			fieldValInt64   int64
+			fieldValFloat32 float32
+			fieldValFloat64 float64
+		)
+
+		switch value.Kind() {
+		case reflect.String:
+			if !value.IsValid() {
+				return out, fmt.Errorf("no such field: %s in obj", field.Name)
+			}
+
+			fieldValString = fieldVal
+
+		case reflect.Int:
+			_, err = strconv.Atoi(fieldVal)
+			if err != nil {
+				return out, err
+			}
+		case reflect.Int32:
+			fieldValInt, err := strconv.Atoi(fieldVal)
+			if err != nil {
+				return out, err
+			}
+
+			fieldValInt32 = int32(fieldValInt)
+			log.Debugf("Int32: %v", fieldValInt32)
+		case reflect.Int64:
+			fieldValInt, err = strconv.Atoi(fieldVal)
+			if err != nil {
+				return out, err
+			}
+
+			fieldValInt64 = int64(fieldValInt)
+			log.Debugf("Int64: %v", fieldValInt64)
+		case reflect.Float32:
+			fieldValFloat, err := strconv.ParseFloat(fieldVal, thirtyTwo)
+			if err != nil {
+				return out, err
+			}
+
+			fieldValFloat32 = float32(fieldValFloat)
+			log.Debugf("Float32: %v", fieldValFloat32)
+		case reflect.Float64:
+			fieldValFloat, err := strconv.ParseFloat(fieldVal, sixtyFour)
+			if err != nil {
+				return out, err
+			}
+
+			fieldValFloat64 = float64(fieldValFloat)
+			log.Debugf("Float64: %v", fieldValFloat64)
+		case reflect.Bool:
+			fieldValBool, err = strconv.ParseBool(fieldVal)
+			if err != nil {
+				return out, err
+			}
+
+			log.Debugf("Bool: %v", fieldValBool)
+		default:
+			log.Errorf("Unsupported type of '%s' in %v", field.Type, field.Name)
+		}
+
+		if field.Name == "Name" {
+			out.Name = fieldValString
+		} else if field.Name == "Topic" {
+			out.Topic = fieldValString
+		} else if field.Name == "FullTopic" {
+			out.FullTopic = fieldValString
+		} else if field.Name == "Hostname" {
+			out.Hostname = fieldValString
+		} else if field.Name == "SupportsDimmer" {
+			out.SupportsDimmer = fieldValBool
+		} else if field.Name == "SupportsColor" {
+			out.SupportsColor = fieldValBool
+		} else if field.Name == "SupportsCT" {
+			out.SupportsCT = fieldValBool
+		}
+	}
+
+	return out, err
+}
+
+// Effect will convert for an Effect model.
+func (ss StringsToStruct) Effect(in interface{}, out models.Effect) (models.Effect, error) {
+	fieldsIn := reflect.TypeOf(in)
+	valuesIn := reflect.ValueOf(in)
+
+	fields := reflect.TypeOf(out)
+	values := reflect.ValueOf(out)
+	num := fields.NumField()
+
+	var err error
+
+	for i := 0; i < num; i++ {
+		field := fields.Field(i)
+		value := values.Field(i)
+
+		_, found := fieldsIn.FieldByName(field.Name)
+		if !found {
+			continue
+		}
+
+		// lists, such as the devices or days, are converted by the caller.
+		if valuesIn.FieldByName(field.Name).Kind() != reflect.String {
+			continue
+		}
+
+		fieldVal := valuesIn.FieldByName(field.Name).String()
+		if fieldVal == "" {
+			continue
+		}
+
+		var (
+			fieldValString  string
+			fieldValBool    bool
+			fieldValInt     int
+			fieldValInt32   int32
+			fieldValInt64   int64
+			fieldValFloat32 float32
+			fieldValFloat64 float64
+		)
+
+		switch value.Kind() {
+		case reflect.String:
+			if !value.IsValid() {
+				return out, fmt.Errorf("no such field: %s in obj", field.Name)
+			}
+
+			fieldValString = fieldVal
+		case reflect.Int:
+			fieldValInt, err = strconv.Atoi(fieldVal)
+			if err != nil {
+				return out, err
+			}
+		case reflect.Int32:
+			fieldValInt, err := strconv.Atoi(fieldVal)
+			if err != nil {
+				return out, err
+			}
+
+			fieldValInt32 = int32(fieldValInt)
+			log.Debugf("Int32: %v", fieldValInt32)
+		case reflect.Int64:
+			fieldValInt, err = strconv.Atoi(fieldVal)
+			if err != nil {
+				return out, err
+			}
+
+			fieldValInt64 = int64(fieldValInt)
+			log.Debugf("Int64: %v", fieldValInt64)
+		case reflect.Float32:
+			fieldValFloat, err := strconv.ParseFloat(fieldVal, thirtyTwo)
+			if err != nil {
+				return out, err
+			}
+
+			fieldValFloat32 = float32(fieldValFloat)
+			log.Debugf("Float32: %v", fieldValFloat32)
+		case reflect.Float64:
+			fieldValFloat, err := strconv.ParseFloat(fieldVal, sixtyFour)
+			if err != nil {
+				return out, err
+			}
+
+			fieldValFloat64 = float64(fieldValFloat)
+			log.Debugf("Float64: %v", fieldValFloat64)
+		case reflect.Bool:
+			fieldValBool, err = strconv.ParseBool(fieldVal)
+			if err != nil {
+				return out, err
+			}
+
+			log.Debugf("Bool: %v", fieldValBool)
+		default:
+			log.Errorf("Unsupported type of '%s' in %v", field.Type, field.Name)
+		}
+
+		if field.Name == "Name" {
+			out.Name = fieldValString
+		} else if field.Name == "Type" {
+			out.Type = fieldValString
+		} else if field.Name == "Speed" {
+			out.Speed = fieldValFloat32
+		} else if field.Name == "Direction" {
+			out.Direction = fieldValString
+		} else if field.Name == "Duration" {
+			out.Duration = fieldValFloat32
+		}
+	}
+
+	return out, err
+}
+
+// Schedule will convert for a Schedule model.
+func (ss StringsToStruct) Schedule(in interface{}, out models.Schedule) (models.Schedule, error) {
+	fieldsIn := reflect.TypeOf(in)
+	valuesIn := reflect.ValueOf(in)
+
+	fields := reflect.TypeOf(out)
+	values := reflect.ValueOf(out)
+	num := fields.NumField()
+
+	var err error
+
+	for i := 0; i < num; i++ {
+		field := fields.Field(i)
+		value := values.Field(i)
+
+		_, found := fieldsIn.FieldByName(field.Name)
+		if !found {
+			continue
+		}
+
+		// lists, such as the devices or days, are converted by the caller.
+		if valuesIn.FieldByName(field.Name).Kind() != reflect.String {
+			continue
+		}
+
+		fieldVal := valuesIn.FieldByName(field.Name).String()
+		if fieldVal == "" {
+			continue
+		}
+
+		var (
+			fieldValString  string
+			fieldValBool    bool
+			fieldValInt     int
+			fieldValInt32   int32
+			fieldValInt64   int64
+			fieldValFloat32 float32
+			fieldValFloat64 float64
+		)
+
+		switch value.Kind() {
+		case reflect.String:
+			if !value.IsValid() {
+				return out, fmt.Errorf("no such field: %s in obj", field.Name)
+			}
+
+			fieldValString = fieldVal
+		case reflect.Int:
+			fieldValInt, err = strconv.Atoi(fieldVal)
+			if err != nil {
+				return out, err
+			}
+		case reflect.Int32:
+			fieldValInt, err := strconv.Atoi(fieldVal)
+			if err != nil {
+				return out, err
+			}
+
+			fieldValInt32 = int32(fieldValInt)
+			log.Debugf("Int32: %v", fieldValInt32)
+		case reflect.Int64:
+			fieldValInt, err = strconv.Atoi(fieldVal)
+			if err != nil {
+				return out, err
+			}
+
+			fieldValInt64 = int64(fieldValInt)
+			log.Debugf("Int64: %v", fieldValInt64)
+		case reflect.Float32:
+			fieldValFloat, err := strconv.ParseFloat(fieldVal, thirtyTwo)
+			if err != nil {
+				return out, err
+			}
+
+			fieldValFloat32 = float32(fieldValFloat)
+			log.Debugf("Float32: %v", fieldValFloat32)
+		case reflect.Float64:
+			fieldValFloat, err := strconv.ParseFloat(fieldVal, sixtyFour)
+			if err != nil {
+				return out, err
+			}
+
+			fieldValFloat64 = float64(fieldValFloat)
+			log.Debugf("Float64: %v", fieldValFloat64)
+		case reflect.Bool:
+			fieldValBool, err = strconv.ParseBool(fieldVal)
+			if err != nil {
+				return out, err
+			}
+
+			log.Debugf("Bool: %v", fieldValBool)
+		default:
+			log.Errorf("Unsupported type of '%s' in %v", field.Type, field.Name)
+		}
+
+		if field.Name == "Name" {
+			out.Name = fieldValString
+		} else if field.Name == "ShowID" {
+			out.ShowID = fieldValInt
+		} else if field.Name == "Enabled" {
+			out.Enabled = fieldValBool
+		} else if field.Name == "Cron" {
+			out.Cron = fieldValString
+		} else if field.Name == "StartTime" {
+			out.StartTime = fieldValString
+		} else if field.Name == "StopTime" {
+			out.StopTime = fieldValString
+		} else if field.Name == "StartDate" {
+			out.StartDate = fieldValString
+		} else if field.Name == "EndDate" {
+			out.EndDate = fieldValString
+		}
+	}
+
+	return out, err
+}
@@ -26,6 +26,9 @@
             <li class="nav-item{{if .PageInfo.DevicesLinkEnabled}} active{{end}}">
               <a class="nav-link" href="devices">Devices {{if .PageInfo.DevicesLinkEnabled}}<span class="sr-only">(current)</span>{{end}}</a>
             </li>
+            <li class="nav-item{{if .PageInfo.SchedulesLinkEnabled}} active{{end}}">
+              <a class="nav-link" href="schedules">Schedules {{if .PageInfo.SchedulesLinkEnabled}}<span class="sr-only">(current)</span>{{end}}</a>
+            </li>
             <li class="nav-item{{if .PageInfo.MQTTLinkEnabled}} active{{end}}">
               <a class="nav-link" href="mqtt">MQTT {{if .PageInfo.MQTTLinkEnabled}}<span class="sr-only">(current)</span>{{end}}</a>
             </li>
@@ -58,6 +61,135 @@
     };
 })(jQuery);
 
+// renderParameter replaces the contents of selector with an input named Parameter that fits the
+// parameter type of a command: a slider, a color picker or a dropdown.
+function renderParameter(selector, schema, value) {
+  var type = schema ? schema.Type : "";
+  if (!type && schema && (schema.Min || schema.Max)) {
+    type = "int";
+  }
+
+  var text = $('<input>', {type: 'text', 'class': 'form-control', id: 'inputParameter', name: 'Parameter'}).val(value);
+  var container = $(selector).empty();
+
+  switch (type) {
+  case "int":
+    if (!schema.Min && !schema.Max) {
+      container.append($('<input>', {type: 'number', 'class': 'form-control', id: 'inputParameter', name: 'Parameter'}).val(value));
+      break;
+    }
+    var range = $('<input>', {type: 'range', 'class': 'custom-range', min: schema.Min, max: schema.Max});
+    var number = $('<input>', {type: 'number', 'class': 'form-control', id: 'inputParameter', name: 'Parameter', min: schema.Min, max: schema.Max}).val(value);
+    range.val(value === "" ? schema.Min : value);
+    range.on('input', function() { number.val(range.val()); });
+    number.on('input', function() { range.val(number.val()); });
+    container.append(range, number);
+    break;
+  case "enum":
+  case "toggle":
+    var values = type == "toggle" ? ["ON", "OFF", "TOGGLE"] : (schema.Values || []);
+    var select = $('<select>', {'class': 'custom-select', id: 'inputParameter', name: 'Parameter'});
+    select.append($('<option>', {value: ''}));
+    values.forEach(v => select.append($('<option>', {value: v, text: v})));
+    if (values.some(v => v.toUpperCase() == String(value).toUpperCase())) {
+      select.val(values.find(v => v.toUpperCase() == String(value).toUpperCase()));
+    }
+    container.append(select);
+    break;
+  case "color":
+  case "hsb":
+    var picker = $('<input>', {type: 'color', 'class': 'form-control'});
+    picker.on('input', function() {
+      text.val(type == "hsb" ? hexToHsb(picker.val()) : picker.val().toUpperCase());
+    });
+    if (/^#?[0-9a-fA-F]{6}$/.test(value)) {
+      picker.val('#' + value.replace('#', ''));
+    }
+    container.append(picker, text);
+    break;
+  default:
+    container.append(text);
+  }
+}
+
+// hexToHsb converts #RRGGBB to the hue,saturation,brightness of HsbColor.
+function hexToHsb(hex) {
+  var r = parseInt(hex.substr(1, 2), 16) / 255;
+  var g = parseInt(hex.substr(3, 2), 16) / 255;
+  var b = parseInt(hex.substr(5, 2), 16) / 255;
+  var max = Math.max(r, g, b), d = max - Math.min(r, g, b);
+  var h = 0;
+  if (d > 0) {
+    if (max == r) {
+      h = 60 * (((g - b) / d) % 6);
+    } else if (max == g) {
+      h = 60 * ((b - r) / d + 2);
+    } else {
+      h = 60 * ((r - g) / d + 4);
+    }
+  }
+  if (h < 0) {
+    h += 360;
+  }
+  var s = max == 0 ? 0 : d / max;
+  return Math.round(h) + ',' + Math.round(s * 100) + ',' + Math.round(max * 100);
+}
+
+// showActionType shows the inputs of the selected action type.
+function showActionType() {
+  var color = $('#inputType').val() == "color";
+  $('#commandFields').toggle(!color);
+  $('#colorFields').toggle(color);
+}
+
+// bindColorInputs keeps the color picker and sliders of a color action in step with their
+// inputs.
+function bindColorInputs() {
+  $('#inputColorPicker').on('input', function() { $('#inputColor').val($(this).val().toUpperCase()); });
+  $('#inputColor').on('input', function() {
+    if (/^#?[0-9a-fA-F]{6}$/.test($(this).val())) {
+      $('#inputColorPicker').val('#' + $(this).val().replace('#', ''));
+    }
+  });
+  $('#inputColor').trigger('input');
+  $('#inputBrightnessRange').on('input', function() { $('#inputBrightness').val($(this).val()); });
+  $('#inputBrightness').on('input', function() { $('#inputBrightnessRange').val($(this).val()); });
+  $('#inputBrightness').trigger('input');
+}
+
+// actionSummary returns what the command and parameter columns show for an action.
+function actionSummary(action) {
+  var palette = action.PaletteColor ? "palette color " + action.PaletteColor : "";
+  if (action.Type != "color") {
+    return [action.Command, palette ? palette + " (" + action.Parameter + ")" : action.Parameter];
+  }
+  var parts = [];
+  if (palette) {
+    parts.push(palette);
+  }
+  if (action.Color) {
+    parts.push(action.Color);
+  }
+  if (action.CT) {
+    parts.push("CT " + action.CT);
+  }
+  if (action.Brightness) {
+    parts.push(action.Brightness + "%");
+  }
+  if (action.Transition) {
+    parts.push(action.Transition + "s");
+  }
+  return ["Color", parts.join(", ")];
+}
+
+// showFieldError marks the input of the field an API error is about.
+function showFieldError(data) {
+  $('.is-invalid').removeClass('is-invalid');
+  if (data.Field) {
+    $('[name="' + data.Field + '"]').addClass('is-invalid');
+  }
+}
+
 function makeDialog(selector) {
   if ($(selector).hasClass('ui-dialog-content')) {
     $(selector).dialog('destroy');
@@ -7,7 +7,35 @@
     </div>
     <div class="form-group">
         <label for="inputName">MQTT Topic</label>
-        <input type="text" class="form-control" id="inputTopic" name="topic" value="{{ .Device.Topic }}">
+        <input type="text" class="form-control" id="inputTopic" aria-describedby="inputTopicHelp" name="topic" value="{{ .Device.Topic }}">
+        <small id="inputTopicHelp" class="form-text text-muted">the Tasmota topic, or the base topic for other types (example: wled/kitchen, zigbee2mqtt/porch_light)</small>
+    </div>
+    <div class="form-group">
+        <label for="inputFullTopic">MQTT Full Topic</label>
+        <input type="text" class="form-control" id="inputFullTopic" aria-describedby="inputFullTopicHelp" name="fulltopic" value="{{ .Device.FullTopic }}">
+        <small id="inputFullTopicHelp" class="form-text text-muted">optional, example: %prefix%/%topic%/ (leave empty to use the add-on configuration)</small>
+    </div>
+    <div class="form-group">
+        <label for="inputHostname">Hostname</label>
+        <input type="text" class="form-control" id="inputHostname" aria-describedby="inputHostnameHelp" name="hostname" value="{{ .Device.Hostname }}">
+        <small id="inputHostnameHelp" class="form-text text-muted">optional, only needed when the Full Topic uses %hostname%</small>
+    </div>
+    <div class="form-group">
+        <label for="inputZones">Zones</label>
+        <input type="text" class="form-control" id="inputZones" aria-describedby="inputZonesHelp" name="zones" value="{{range $i, $zone := .Device.Zones}}{{if $i}}, {{end}}{{$zone}}{{end}}">
+        <small id="inputZonesHelp" class="form-text text-muted">optional, names of the zones the device is in separated by commas (example: porch, fence-left)</small>
+    </div>
+    <div class="form-check">
+        <input type="checkbox" class="form-check-input" id="inputSupportsDimmer" name="supportsdimmer" value="true" {{if .Device.SupportsDimmer}}checked{{end}}>
+        <label class="form-check-label" for="inputSupportsDimmer">Supports Dimmer</label>
+    </div>
+    <div class="form-check">
+        <input type="checkbox" class="form-check-input" id="inputSupportsColor" name="supportscolor" value="true" {{if .Device.SupportsColor}}checked{{end}}>
+        <label class="form-check-label" for="inputSupportsColor">Supports Color</label>
+    </div>
+    <div class="form-check">
+        <input type="checkbox" class="form-check-input" id="inputSupportsCT" name="supportsct" value="true" {{if .Device.SupportsCT}}checked{{end}}>
+        <label class="form-check-label" for="inputSupportsCT">Supports Color Temperature (CT)</label>
     </div>
     <div class="form-group">
         <label for="inputType">Device Type</label>
@@ -6,6 +6,10 @@
       <th scope="col">Name</th>
       <th scope="col">Topic</th>
       <th scope="col">Type</th>
+      <th scope="col">Zones</th>
+      <th scope="col">Capabilities</th>
+      <th scope="col">Status</th>
+      <th scope="col">Last Seen</th>
       <th scope="col"><a href="devices-add" class="btn btn-sm btn-primary">Add</a></th>
     </tr>
   </thead>
@@ -15,6 +19,10 @@
       <td>{{.Name}}</td>
       <td>{{.Topic}}</td>
       <td>{{.Type.Name}}</td>
+      <td>{{range $i, $zone := .Zones}}{{if $i}}, {{end}}{{$zone}}{{end}}</td>
+      <td>{{if .SupportsDimmer}}Dimmer {{end}}{{if .SupportsColor}}Color {{end}}{{if .SupportsCT}}CT{{end}}</td>
+      <td>{{if .LastSeen.IsZero}}<span class="badge badge-secondary">Unknown</span>{{else if .Online}}<span class="badge badge-success">Online</span>{{else}}<span class="badge badge-danger">Offline</span>{{end}}</td>
+      <td>{{if not .LastSeen.IsZero}}{{.LastSeen.Format "2006-01-02 15:04:05"}}{{end}}</td>
       <td>
         <a href="devices-delete?deviceID={{.ID}}" class="btn btn-sm btn-danger" title="Delete Device" onclick="return confirm('Are you sure you want to delete this device? Note that this may cause problems if the device is used in any scenes!')">
           <div class="icon-button-delete">&nbsp;</div>
@@ -25,8 +33,45 @@
 {{end}}
   </tbody>
 </table>
+<h2>Discovered Devices</h2>
+<table class="table">
+  <thead>
+    <tr>
+      <th scope="col">Name</th>
+      <th scope="col">Topic</th>
+      <th scope="col">Module</th>
+      <th scope="col">IP</th>
+      <th scope="col">Capabilities</th>
+      <th scope="col"></th>
+    </tr>
+  </thead>
+  <tbody>
+{{ range .Discovered }}
+    <tr>
+      <td>{{.Name}}</td>
+      <td>{{.Topic}}</td>
+      <td>{{.Module}}</td>
+      <td>{{.IP}}</td>
+      <td>{{if .SupportsDimmer}}Dimmer {{end}}{{if .SupportsColor}}Color {{end}}{{if .SupportsCT}}CT{{end}}</td>
+      <td><button onclick="adoptDevice('{{.Topic}}')" class="btn btn-sm btn-primary" title="Add Device">Add</button></td>
+    </tr>
+{{ else }}
+    <tr><td colspan="6">No new devices have announced themselves.</td></tr>
+{{end}}
+  </tbody>
+</table>
 <script>
 $(document).ready(function() {
 });
+
+function adoptDevice(topic) {
+  $.post("api/v1/devices/discovered/adopt", JSON.stringify({Topic: topic}), function(data) {
+      if (data.Error != false) {
+          alert ("Error: " + data.Message)
+      } else {
+          location.reload()
+      }
+  }, "json");
+}
 </script>
 {{end}}
@@ -11,6 +11,29 @@ MQTT client disconnected
 <br>
 <br>
 <div>
+<h2>Connection History</h2>
+<table class="table">
+  <thead>
+    <tr>
+      <th scope="col">Time</th>
+      <th scope="col">Event</th>
+      <th scope="col">Error</th>
+    </tr>
+  </thead>
+  <tbody>
+  {{range .ConnectionEvents}}
+    <tr>
+      <td>{{.Time.Format "2006-01-02 15:04:05"}}</td>
+      <td>{{.Event}}</td>
+      <td>{{.Error}}</td>
+    </tr>
+  {{else}}
+    <tr><td colspan="3">No connection events yet.</td></tr>
+  {{end}}
+  </tbody>
+</table>
+</div>
+<div>
 <h2>Message Log</h2>
     <ul id="log">
     </ul>
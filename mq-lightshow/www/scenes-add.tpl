@@ -13,11 +13,21 @@
         </select>
         <small id="inputAllowedDevicesHelp" class="form-text text-muted">Limit the devices shown when adding actions in this scene.</small>
     </div>
+    <div class="form-group">
+        <label for="inputAllowedZones">Allowed Zones</label>
+        <select class="custom-select" class="form-control" id="inputAllowedZones" aria-describedby="inputAllowedZonesHelp" name="AllowedZoneNames" multiple="">
+{{ range $zone := .Zones }}
+            <option value="{{.Name}}">{{.Name}} ({{len .Devices}})</option>
+{{ end }}
+        </select>
+        <small id="inputAllowedZonesHelp" class="form-text text-muted">The devices in these zones are allowed too, including devices added to the zones later.</small>
+    </div>
     <button type="submit" value="submit" class="btn btn-primary">Submit</button>
 </form>
 <script>
 function ResetDevices() {
     $('#inputAllowedDevices').val('');
+    $('#inputAllowedZones').val('');
     return false;
 }
 
@@ -36,6 +46,9 @@ $(document).ready(function() {
         if (typeof objData.AllowedDeviceIDs == "string") {
             objData['AllowedDeviceIDs'] = [objData.AllowedDeviceIDs]
         }
+        if (typeof objData.AllowedZoneNames == "string") {
+            objData['AllowedZoneNames'] = [objData.AllowedZoneNames]
+        }
         formData = JSON.stringify(objData);
 
         $.post("api/v1/scene", formData, function(data) {
@@ -13,11 +13,21 @@
         </select>
         <small id="inputNameHelp" class="form-text text-muted">Limit the devices shown when adding actions in this scene.</small>
     </div>
+    <div class="form-group">
+        <label for="inputAllowedZones">Allowed Zones</label>
+        <select class="custom-select" class="form-control" id="inputAllowedZones" aria-describedby="inputAllowedZonesHelp" name="AllowedZoneNames" multiple="">
+{{ range $zone := .Zones }}
+            <option value="{{.Name}}"{{range $.Scene.AllowedZones}}{{if eq . $zone.Name}} selected{{end}}{{end}}>{{.Name}} ({{len .Devices}})</option>
+{{ end }}
+        </select>
+        <small id="inputAllowedZonesHelp" class="form-text text-muted">The devices in these zones are allowed too, including devices added to the zones later.</small>
+    </div>
     <button type="submit" class="btn btn-primary">Update Scene</button>
 </form>
 <script>
 function ResetDevices() {
     $('#inputDevices').val('');
+    $('#inputAllowedZones').val('');
     return false;
 }
 $(document).ready(function() {
@@ -35,6 +45,9 @@ $(document).ready(function() {
         if (typeof objData.AllowedDeviceIDs == "string") {
             objData['AllowedDeviceIDs'] = [objData.AllowedDeviceIDs]
         }
+        if (typeof objData.AllowedZoneNames == "string") {
+            objData['AllowedZoneNames'] = [objData.AllowedZoneNames]
+        }
         formData = JSON.stringify(objData);
 
         $.post("api/v1/scene/{{.Scene.ID}}/configure", formData, function(data) {
@@ -9,39 +9,100 @@
         </select>
     </div>
     <div class="form-group">
-        <label for="inputCommand">Command</label>
-        <select class="custom-select" class="form-control" id="inputCommand" name="Command">
-{{ range .Commands }}
-            <option value="{{.Name}}"{{if eq $.Action.Command .Name}} selected{{end}}>{{.Description}}</option>
+        <label for="inputZones">Zones</label>
+        <select class="custom-select form-control" id="inputZones" aria-describedby="inputZonesHelp" name="ZoneNames" multiple="">
+{{ range $zone := .Zones }}
+            <option value="{{.Name}}"{{range $.Action.Zones}}{{if eq . $zone.Name}} selected{{end}}{{end}}>{{.Name}} ({{len .Devices}})</option>
 {{ end }}
         </select>
+        <small id="inputZonesHelp" class="form-text text-muted">The devices in these zones when the action runs are added to the selected devices.</small>
     </div>
     <div class="form-group">
-        <label for="inputParameter">Parameter</label>
-        <input type="text" class="form-control" id="inputParameter" name="Parameter" value="{{.Action.Parameter}}">
+        <label for="inputType">Type</label>
+        <select class="custom-select" id="inputType" name="Type">
+            <option value="command"{{if eq .Action.Type "command"}} selected{{end}}>Command</option>
+            <option value="color"{{if eq .Action.Type "color"}} selected{{end}}>Color</option>
+        </select>
+    </div>
+    <div id="commandFields">
+        <div class="form-group">
+            <label for="inputCommand">Command</label>
+            <select class="custom-select" class="form-control" id="inputCommand" name="Command">
+{{ range .Commands }}
+                <option value="{{.Name}}"{{if eq $.Action.Command .Name}} selected{{end}}>{{.Description}}</option>
+{{ end }}
+            </select>
+        </div>
+        <div class="form-group">
+            <label for="inputParameter">Parameter</label>
+            <div id="parameterInput">
+                <input type="text" class="form-control" id="inputParameter" name="Parameter" value="{{.Action.Parameter}}">
+            </div>
+        </div>
+        <div class="form-group">
+            <label for="inputGlobalParameter">Use Global Parameter</label>
+            <select class="custom-select" class="form-control" id="inputGlobalParameter" aria-describedby="inputGlobalParameterHelp" name="GlobalParameter">
+                <option value="false"{{if (eq .Action.GlobalParameter "false")}} selected{{end}}>false</option>
+                <option value="GlobalSpeed"{{if (eq .Action.GlobalParameter "GlobalSpeed")}} selected{{end}}>GlobalSpeed</option>
+                <option value="GlobalParameter1"{{if (eq .Action.GlobalParameter "GlobalParameter1")}} selected{{end}}>GlobalParameter1</option>
+                <option value="GlobalParameter2"{{if (eq .Action.GlobalParameter "GlobalParameter2")}} selected{{end}}>GlobalParameter2</option>
+            </select>
+            <small id="inputGlobalParameterHelp" class="form-text text-muted">Use a Global Parameter set in the Show configuration (only works when running in show context).</small>
+        </div>
+    </div>
+    <div id="colorFields" style="display:none">
+        <div class="form-group">
+            <label for="inputColor">Color</label>
+            <input type="color" class="form-control" id="inputColorPicker">
+            <input type="text" class="form-control" id="inputColor" aria-describedby="inputColorHelp" name="Color" value="{{.Action.Color}}">
+            <small id="inputColorHelp" class="form-text text-muted">r,g,b or #hex. Lights without color get the nearest white.</small>
+        </div>
+        <div class="form-group">
+            <label for="inputCT">Color Temperature</label>
+            <input type="number" class="form-control" id="inputCT" aria-describedby="inputCTHelp" name="CT" min="153" max="500" value="{{if .Action.CT}}{{.Action.CT}}{{end}}">
+            <small id="inputCTHelp" class="form-text text-muted">153 (cold) to 500 (warm), instead of a color. Lights without white channels get the color of the white.</small>
+        </div>
+        <div class="form-group">
+            <label for="inputBrightness">Brightness</label>
+            <input type="range" class="custom-range" id="inputBrightnessRange" min="0" max="100" value="0">
+            <input type="number" class="form-control" id="inputBrightness" aria-describedby="inputBrightnessHelp" name="Brightness" min="0" max="100" value="{{if .Action.Brightness}}{{.Action.Brightness}}{{end}}">
+            <small id="inputBrightnessHelp" class="form-text text-muted">1 to 100(%), empty to leave the brightness unchanged.</small>
+        </div>
+        <div class="form-group">
+            <label for="inputTransition">Transition</label>
+            <input type="number" class="form-control" id="inputTransition" aria-describedby="inputTransitionHelp" name="Transition" min="0" step="0.1" value="{{if .Action.Transition}}{{.Action.Transition}}{{end}}">
+            <small id="inputTransitionHelp" class="form-text text-muted">Seconds to fade to the new color, Tasmota fades in steps of 0.5 seconds up to 20 seconds.</small>
+        </div>
     </div>
     <div class="form-group">
-        <label for="inputGlobalParameter">Use Global Parameter</label>
-        <select class="custom-select" class="form-control" id="inputGlobalParameter" aria-describedby="inputGlobalParameterHelp" name="GlobalParameter">
-            <option value="false"{{if (eq .Action.GlobalParameter "false")}} selected{{end}}>false</option>
-            <option value="GlobalSpeed"{{if (eq .Action.GlobalParameter "GlobalSpeed")}} selected{{end}}>GlobalSpeed</option>
-            <option value="GlobalParameter1"{{if (eq .Action.GlobalParameter "GlobalParameter1")}} selected{{end}}>GlobalParameter1</option>
-            <option value="GlobalParameter2"{{if (eq .Action.GlobalParameter "GlobalParameter2")}} selected{{end}}>GlobalParameter2</option>
-        </select>
-        <small id="inputGlobalParameterHelp" class="form-text text-muted">Use a Global Parameter set in the Show configuration (only works when running in show context).</small>
+        <label for="inputPaletteColor">Palette Color</label>
+        <input type="text" class="form-control" id="inputPaletteColor" aria-describedby="inputPaletteColorHelp" name="PaletteColor" list="paletteColors" value="{{.Action.PaletteColor}}">
+        <datalist id="paletteColors">
+            <option value="1">
+            <option value="2">
+            <option value="3">
+            <option value="next">
+        </datalist>
+        <small id="inputPaletteColorHelp" class="form-text text-muted">Optional number of the color of the Show's palette to use (1 is the first color), or next for the color after the one used last. Replaces the Color or the color Parameter when running in a show with a palette.</small>
     </div>
     <button type="submit" class="btn btn-primary">Submit</button>
 </form>
 <script>
 $(document).ready(function() {
     populateCommands({{.Action.Command}});
+    bindColorInputs();
+    showActionType();
+
+    $("#inputType").change(function() {
+        showActionType();
+    });
 
     $('#editActionForm').submit(function() {
-        if ($("#inputDevices").val() === "" ) {
-            alert('Please select at least one Device.');
+        if (selectedDeviceIds().length == 0) {
+            alert('Please select at least one Device or Zone.');
             return false;
         } else
-        if ($("#inputCommand").val() === "" ) {
+        if ($("#inputType").val() != "color" && $("#inputCommand").val() === "" ) {
             alert('Please select a Command.');
             return false;
         }
@@ -52,10 +113,14 @@ $(document).ready(function() {
         if (typeof objData.DeviceIDs == "string") {
             objData['DeviceIDs'] = [objData.DeviceIDs]
         }
+        if (typeof objData.ZoneNames == "string") {
+            objData['ZoneNames'] = [objData.ZoneNames]
+        }
         formData = JSON.stringify(objData);
 
         $.post("api/v1/scene/{{.SceneID}}/group/{{.GroupID}}/action/{{.Action.ID}}/edit", formData, function(data) {
             if (data.Error != false) {
+                showFieldError(data);
                 alert("Error: " + data.Message);
             } else {
                 $('#focusSelector').text('#actionRow{{.Action.ID}}');
@@ -67,10 +132,45 @@ $(document).ready(function() {
         return false;
     });
 
-    $("#inputDevices").change(function() {
+    $("#inputDevices, #inputZones").change(function() {
         populateCommands($("#inputCommand").val());
     });
 
+    $("#inputCommand").change(function() {
+        populateParameter("");
+    });
+
+    // selectedDeviceIds returns the ids of the selected devices and of the devices in the
+    // selected zones.
+    function selectedDeviceIds() {
+        var zoneDevices = {};{{ range .Zones }}
+        zoneDevices[{{.Name}}] = [{{ range .Devices }}"{{.ID}}",{{ end }}];{{ end }}
+        var ids = ($("#inputDevices").val() || []).slice();
+        ($("#inputZones").val() || []).forEach(zone => {
+            (zoneDevices[zone] || []).forEach(id => {
+                if (!ids.includes(id)) {
+                    ids.push(id);
+                }
+            });
+        });
+        return ids;
+    }
+
+    // populateParameter renders the parameter input for the selected command of the first device.
+    function populateParameter(value) {
+        var commandSchemas = {};{{ range .DeviceTypes }}
+        commandSchemas[{{.ID}}] = { {{ range .Commands }}
+            {{.Name}}: {Type: {{.Type}}, Min: {{.Min}}, Max: {{.Max}}, Values: {{.Values}}},{{ end }}
+        };
+{{ end }}
+        var deviceToDeviceType = {};{{ range .Devices }}
+        deviceToDeviceType[{{.ID}}] = {{.Type.ID}};{{ end }}{{ range .Zones }}{{ range .Devices }}
+        deviceToDeviceType[{{.ID}}] = {{.Type.ID}};{{ end }}{{ end }}
+        var deviceId = selectedDeviceIds()[0];
+        var schemas = commandSchemas[deviceToDeviceType[deviceId]] || {};
+        renderParameter('#parameterInput', schemas[$("#inputCommand").val()], value);
+    }
+
     function populateCommands(selected) {
         var deviceCommands = {};{{ range .DeviceTypes }}
         deviceCommands[{{.ID}}] = { {{ range .Commands }}
@@ -78,20 +178,28 @@ $(document).ready(function() {
         };
 {{ end }}
         var deviceToDeviceType = {};{{ range .Devices }}
-        deviceToDeviceType[{{.ID}}] = {{.Type.ID}};{{ end }}
+        deviceToDeviceType[{{.ID}}] = {{.Type.ID}};{{ end }}{{ range .Zones }}{{ range .Devices }}
+        deviceToDeviceType[{{.ID}}] = {{.Type.ID}};{{ end }}{{ end }}
         $("#inputCommand").empty();
         // Device selection changed, need to populate the commands
-        if($("#inputDevices").val()){            
+        if(selectedDeviceIds().length){            
             $("#inputCommand").attr('disabled',false);
-            var str = $("#inputDevices").val().toString();
+            var str = selectedDeviceIds().toString();
             var deviceId = str.split(",")[0];
             if (deviceId == "") {
                 $("#inputCommand").attr('disabled',true);
                 $("#inputCommand").empty();
                 return
             }
-            typeId = deviceToDeviceType[deviceId];
+            // only offer the commands all selected device types understand, so a mix of
+            // device types can share an action.
+            var typeId = deviceToDeviceType[deviceId];
+            var deviceIds = str.split(",");
             Object.keys(deviceCommands[typeId]).forEach(key => {
+                var shared = deviceIds.every(id => id == "" || key in deviceCommands[deviceToDeviceType[id]]);
+                if (!shared) {
+                    return;
+                }
                 if (key == selected) {
                     $("#inputCommand").append('<option value="'+key+'" selected>'+deviceCommands[typeId][key]+'</option>'); 
                 } else {
@@ -102,6 +210,7 @@ $(document).ready(function() {
             $("#inputCommand").attr('disabled',true);
             $("#inputCommand").empty();
         }
+        populateParameter($("#inputParameter").val() || "");
     }
 });
 </script>
@@ -13,6 +13,24 @@
         </select>
         <small id="inputGlobalDelayHelp" class="form-text text-muted">Use the Global Delay set in the Show configuration (only works when running in show context).</small>
     </div>
+    <div class="form-group">
+        <label for="inputFadeDuration">Fade</label>
+        <input type="text" class="form-control" id="inputFadeDuration" aria-describedby="inputFadeDurationHelp" name="FadeDuration" value="0">
+        <small id="inputFadeDurationHelp" class="form-text text-muted">Seconds to fade from the previous colors to the colors of this group, 0 to not fade (only works when running in show context).</small>
+    </div>
+    <div class="form-group">
+        <label for="inputFadeRate">Fade Steps per Second</label>
+        <input type="number" class="form-control" id="inputFadeRate" aria-describedby="inputFadeRateHelp" name="FadeRate" min="1" max="50" value="">
+        <small id="inputFadeRateHelp" class="form-text text-muted">How many colors in between are sent each second, 10 when empty.</small>
+    </div>
+    <div class="form-group">
+        <label for="inputFadeEasing">Fade Easing</label>
+        <select class="custom-select" id="inputFadeEasing" name="FadeEasing">
+            <option value="linear">linear</option>
+            <option value="ease-in-out">ease-in-out</option>
+            <option value="sine">sine</option>
+        </select>
+    </div>
     <button type="submit" class="btn btn-primary">Submit</button>
 </form>
 <script>
@@ -23,12 +41,17 @@ $(document).ready(function() {
         if (!$.isNumeric($("#inputDelay").val())) {
             alert('Please enter a float value for the Delay time in seconds (5 seconds is 5.0).');
             return false;
+        } else
+        if (!$.isNumeric($("#inputFadeDuration").val())) {
+            alert('Please enter a float value for the Fade time in seconds (0 to not fade).');
+            return false;
         }
 
         var formData = JSON.stringify($(this).serializeFormJSON());
 
         $.post("api/v1/scene/{{.SceneID}}/group", formData, function(data) {
             if (data.Error != false) {
+                showFieldError(data);
                 alert("Error: " + data.Message);
             } else {
                 $('#focusSelector').text('#groupRow{{.OrderNext}}')
@@ -13,6 +13,24 @@
         </select>
         <small id="inputGlobalDelayHelp" class="form-text text-muted">Use the Global Delay set in the Show configuration (only works when running in show context).</small>
     </div>
+    <div class="form-group">
+        <label for="inputFadeDuration">Fade</label>
+        <input type="text" class="form-control" id="inputFadeDuration" aria-describedby="inputFadeDurationHelp" name="FadeDuration" value="{{.Group.FadeDuration}}">
+        <small id="inputFadeDurationHelp" class="form-text text-muted">Seconds to fade from the previous colors to the colors of this group, 0 to not fade (only works when running in show context).</small>
+    </div>
+    <div class="form-group">
+        <label for="inputFadeRate">Fade Steps per Second</label>
+        <input type="number" class="form-control" id="inputFadeRate" aria-describedby="inputFadeRateHelp" name="FadeRate" min="1" max="50" value="{{if .Group.FadeRate}}{{.Group.FadeRate}}{{end}}">
+        <small id="inputFadeRateHelp" class="form-text text-muted">How many colors in between are sent each second, 10 when empty.</small>
+    </div>
+    <div class="form-group">
+        <label for="inputFadeEasing">Fade Easing</label>
+        <select class="custom-select" id="inputFadeEasing" name="FadeEasing">
+            <option value="linear"{{if eq .Group.FadeEasing "linear"}} selected{{end}}>linear</option>
+            <option value="ease-in-out"{{if eq .Group.FadeEasing "ease-in-out"}} selected{{end}}>ease-in-out</option>
+            <option value="sine"{{if eq .Group.FadeEasing "sine"}} selected{{end}}>sine</option>
+        </select>
+    </div>
     <div class="form-group">
         <label for="inputName">Order</label>
         <input type="text" class="form-control" id="inputOrder" name="Order" value="{{.Group.Order}}">
@@ -28,6 +46,10 @@ $(document).ready(function() {
             alert('Please enter a float value for the Delay time in seconds (5 seconds is 5.0).');
             return false;
         } else
+        if (!$.isNumeric($("#inputFadeDuration").val())) {
+            alert('Please enter a float value for the Fade time in seconds (0 to not fade).');
+            return false;
+        } else
         if ($.trim($("#inputOrder").val()) === "" || !$.isNumeric($("#inputOrder").val())) {
             alert('Please enter a numeric value for the order.');
             return false;
@@ -37,6 +59,7 @@ $(document).ready(function() {
 
         $.post("api/v1/scene/{{.SceneID}}/group/{{.Group.ID}}/configure", formData, function(data) {
             if (data.Error != false) {
+                showFieldError(data);
                 alert("Error: " + data.Message);
             } else {
                 $('#focusSelector').text('#groupRow{{.Group.ID}}')
@@ -42,7 +42,7 @@ function populateContent() {
     <table class="table table-borderless table-dark mb-0">
       <tr>
         <td width="20%">order: ${groups[i].Order}</td>
-        <td width="20%">delay: ${groups[i].Delay}</td>
+        <td width="20%">delay: ${groups[i].Delay}${groups[i].FadeDuration > 0 ? ", fade: " + groups[i].FadeDuration : ""}</td>
         <td width="20%">globalDelay: ${groups[i].GlobalDelay}</td>
         <td width="40%" class="text-right">
           <button onclick="runGroup({{.Scene.ID}}, ${groups[i].ID})" class="btn btn-sm btn-primary" title="Run Actions in Group">
@@ -125,11 +125,12 @@ function populateContent() {
       });
       getActions.done(function() {
         for (i=0; i<actions.length; i++) {
+            var summary = actionSummary(actions[i]);
             thisHtml = `
           <tr id="actionRow${actions[i].ID}">
             <td style="display:none">${actions[i].ID}</td>
-            <td class="">${actions[i].Command}</td> 
-            <td>${actions[i].Parameter}</td>
+            <td class="">${summary[0]}</td> 
+            <td>${summary[1]}</td>
             <td>${actions[i].GlobalParameter}</td>
             <td>`;
             looped = false
@@ -142,6 +143,15 @@ function populateContent() {
 
               thisHtml += actions[i].Devices[ii].Name;
             }
+            for (ii=0; ii<actions[i].Zones.length; ii++) {
+              if (looped == true) {
+                 thisHtml += ", ";
+              } else {
+                looped = true;
+              }
+
+              thisHtml += "zone " + actions[i].Zones[ii];
+            }
             thisHtml +=`</td>
             <td>
               <button onclick="runAction({{.Scene.ID}}, ${actions[i].GroupID}, ${actions[i].ID})" class="btn btn-sm btn-primary" title="Run Action"><div class="icon-button-execute">&nbsp;</div></button>
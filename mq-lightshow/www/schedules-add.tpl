@@ -0,0 +1,93 @@
+{{define "content"}}
+<form method="POST" id="scheduleAddForm">
+    <div class="form-group">
+        <label for="inputName">Schedule Name</label>
+        <input type="text" class="form-control" id="inputName" aria-describedby="inputNameHelp" name="Name" value="">
+        <small id="inputNameHelp" class="form-text text-muted">example: Christmas Evenings</small>
+    </div>
+    <div class="form-group">
+        <label for="inputShow">Show</label>
+        <select class="custom-select" class="form-control" id="inputShow" name="ShowID">
+            <option value=""></option>
+{{ range .Shows }}
+            <option value="{{.ID}}">{{.Name}}</option>
+{{ end }}
+        </select>
+    </div>
+    <div class="form-group">
+        <label for="inputEnabled">Enabled</label>
+        <select class="custom-select" class="form-control" id="inputEnabled" name="Enabled">
+            <option value="true">true</option>
+            <option value="false">false</option>
+        </select>
+    </div>
+    <div class="form-group">
+        <label for="inputStartTime">Start Time</label>
+        <input type="text" class="form-control" id="inputStartTime" aria-describedby="inputStartTimeHelp" name="StartTime" value="" placeholder="HH:MM or sunset+30">
+        <small id="inputStartTimeHelp" class="form-text text-muted">example: 17:30, or a solar event with an offset in minutes such as sunset+30 or civil-dusk-15</small>
+    </div>
+    <div class="form-group">
+        <label for="inputCron">Cron Expression</label>
+        <input type="text" class="form-control" id="inputCron" aria-describedby="inputCronHelp" name="Cron" value="">
+        <small id="inputCronHelp" class="form-text text-muted">optional, starts the show instead of the Start Time: minute hour day-of-month month day-of-week (example: 0 18 * 12 fri,sat)</small>
+    </div>
+    <div class="form-group">
+        <label for="inputStopTime">Stop Time</label>
+        <input type="text" class="form-control" id="inputStopTime" aria-describedby="inputStopTimeHelp" name="StopTime" value="" placeholder="HH:MM or sunset+30">
+        <small id="inputStopTimeHelp" class="form-text text-muted">optional, a time before the start time stops the show the next day (example: 23:00 or sunrise)</small>
+    </div>
+    <div class="form-group">
+        <label>Days</label>
+        <div>
+{{ range .Days }}
+            <div class="form-check form-check-inline">
+                <input type="checkbox" class="form-check-input" id="inputDay{{.Value}}" name="Days" value="{{.Value}}"{{if .Checked}} checked{{end}}>
+                <label class="form-check-label" for="inputDay{{.Value}}">{{.Name}}</label>
+            </div>
+{{ end }}
+        </div>
+        <small class="form-text text-muted">leave all unchecked to run every day</small>
+    </div>
+    <div class="form-row">
+        <div class="form-group col">
+            <label for="inputStartDate">Start Date</label>
+            <input type="text" class="form-control" id="inputStartDate" name="StartDate" value="" placeholder="MM-DD">
+        </div>
+        <div class="form-group col">
+            <label for="inputEndDate">End Date</label>
+            <input type="text" class="form-control" id="inputEndDate" name="EndDate" value="" placeholder="MM-DD">
+        </div>
+    </div>
+    <small class="form-text text-muted">optional, runs the schedule only during part of every year (example: 12-01 to 01-06)</small>
+    <br>
+    <button type="submit" class="btn btn-primary">Submit</button>
+</form>
+<script>
+$(document).ready(function() {
+    $('#scheduleAddForm').submit(function(event) {
+        event.preventDefault();
+
+        if ($.trim($("#inputName").val()) === "" ) {
+            alert('Please fill out the Schedule Name.');
+            return false;
+        } else
+        if ($.trim($("#inputShow").val()) === "" ) {
+            alert('Please select a Show.');
+            return false;
+        }
+
+        $.post("api/v1/schedule", scheduleFormJSON(this), function(data) {
+            if (data.Error != false) {
+                showFieldError(data);
+                alert("Error: " + data.Message);
+            } else {
+                populateContent();
+                $("#addScheduleModal").dialog("close");
+            }
+        }, "json");
+
+        return false;
+    });
+});
+</script>
+{{end}}
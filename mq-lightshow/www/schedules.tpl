@@ -0,0 +1,111 @@
+{{define "content"}}
+<h1>Schedules</h1>
+<p>Schedules start and stop shows at set times, or relative to sunrise and sunset. Times are in the {{.TimeZone}} time zone.</p>
+{{if .SolarEvents}}<p class="text-muted">Today: {{range $i, $e := .SolarEvents}}{{if $i}}, {{end}}{{$e.Event}} {{$e.Time}}{{end}}</p>
+{{else}}<p class="text-muted">Set the Latitude and Longitude options of the add-on to use sunrise, sunset and twilight.</p>
+{{end}}<table class="table">
+  <thead class="thead-dark">
+    <tr>
+      <th scope="col">Name</th>
+      <th scope="col">Show</th>
+      <th scope="col">Start</th>
+      <th scope="col">Stop</th>
+      <th scope="col">Days</th>
+      <th scope="col">Dates</th>
+      <th scope="col" class="text-right"><button class="btn btn-sm btn-primary" onclick="addModal()" title="Add a New Schedule">Add Schedule</button></th>
+    </tr>
+  </thead>
+  <tbody id="schedulesContainer">
+  </tbody>
+</table>
+<div title="Add Schedule" id="addScheduleModal"></div>
+<div title="Edit Schedule" id="editScheduleModal"></div>
+<script>
+var weekdays = ["Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"];
+
+function populateContent() {
+    var schedulesContainer = $('#schedulesContainer');
+
+    $.getJSON('api/v1/schedules', function (data) {
+      schedulesContainer.empty();
+      schedules = data.Data;
+      var html = "";
+
+      for (i=0; i<schedules.length; i++) {
+        var start = schedules[i].Cron != "" ? `<code>${schedules[i].Cron}</code>` : schedules[i].StartTime;
+        var days = schedules[i].Days.length == 0 ? 'every day' : schedules[i].Days.map(function(d) { return weekdays[d]; }).join(', ');
+        var dates = schedules[i].StartDate == "" ? 'all year' : `${schedules[i].StartDate} to ${schedules[i].EndDate}`;
+
+        html +=`
+    <tr${schedules[i].Enabled ? '' : ' class="text-muted"'}>
+      <td>${schedules[i].Name}${schedules[i].Enabled ? '' : ' (disabled)'}</td>
+      <td>${schedules[i].ShowName}</td>
+      <td>${start}</td>
+      <td>${schedules[i].StopTime}</td>
+      <td>${days}</td>
+      <td>${dates}</td>
+      <td class="text-right">
+        <button onclick="editModal(${schedules[i].ID})" class="btn btn-sm btn-primary" title="Edit Schedule"><div class="icon-button-edit">&nbsp;</div></button>
+        <button onclick="deleteSchedule(${schedules[i].ID})" class="btn btn-sm btn-danger" title="Delete Schedule"><div class="icon-button-delete">&nbsp;</div></button>
+      </td>
+    </tr>`;
+      }
+
+      if (schedules.length == 0) {
+        html = '<tr><td colspan="7">No schedules have been added.</td></tr>';
+      }
+
+      schedulesContainer.append(html);
+    });
+
+    schedulesContainer.html('<tr><td colspan="7">Loading Schedules from the API...</td></tr>');
+}
+
+// scheduleFormJSON posts the days of a schedule form as a list, also when one or none is checked.
+function scheduleFormJSON(form) {
+  var o = $(form).serializeFormJSON();
+
+  if (o.Days === undefined) {
+    o.Days = [];
+  } else if (!o.Days.push) {
+    o.Days = [o.Days];
+  }
+
+  return JSON.stringify(o);
+}
+
+function addModal() {
+  $("#addScheduleModal").dialog("open");
+  $.get("schedules-add", function(html){
+    $('#addScheduleModal').append(html);
+    $('#inputName').trigger('focus');
+  });
+}
+
+function editModal(id) {
+  $("#editScheduleModal").dialog("open");
+  $.get("schedules-edit?scheduleID="+id, function(html){
+    $('#editScheduleModal').append(html);
+    $('#inputName').trigger('focus');
+  });
+}
+
+function deleteSchedule(id) {
+  if (confirm('Are you sure you want to delete this schedule?')) {
+    $.post("api/v1/schedule/"+id+"/delete", function(data) {
+        if (data.Error != false) {
+            alert ("Error: " + data.Message)
+        } else {
+            populateContent()
+        }
+    }, "json");
+  }
+}
+
+$(document).ready(function() {
+  populateContent();
+  makeDialog("#addScheduleModal");
+  makeDialog("#editScheduleModal");
+});
+</script>
+{{end}}
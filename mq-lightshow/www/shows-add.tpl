@@ -38,6 +38,30 @@
         <input type="text" class="form-control" id="inputGlobalParameterValue2" aria-describedby="inputGlobalParameterValue2Help" name="GlobalParameter2" value="">
         <small id="inputGlobalParameterValue2Help" class="form-text text-muted">This string value is exported to the scene action parameters in this show.</small>
     </div>
+    <div class="form-group">
+        <label for="inputPalette">Palette</label>
+        <select class="custom-select" class="form-control" id="inputPalette" aria-describedby="inputPaletteHelp" name="PaletteID">
+            <option value=""></option>
+{{ range .Palettes }}
+            <option value="{{.ID}}">{{.Name}}</option>
+{{ end }}
+        </select>
+        <small id="inputPaletteHelp" class="form-text text-muted">The colors used by actions with a Palette Color in this show. Choosing another palette recolors the show.</small>
+    </div>
+    <div class="form-group">
+        <label for="inputPriority">Priority</label>
+        <input type="text" class="form-control" id="inputPriority" aria-describedby="inputPriorityHelp" name="Priority" value="0">
+        <small id="inputPriorityHelp" class="form-text text-muted">When shows want the same devices, a show with a higher priority can preempt one with a lower priority.</small>
+    </div>
+    <div class="form-group">
+        <label for="inputConflictPolicy">Conflict Policy</label>
+        <select class="custom-select" class="form-control" id="inputConflictPolicy" aria-describedby="inputConflictPolicyHelp" name="ConflictPolicy">
+            <option value="share">Share the devices with running shows</option>
+            <option value="reject">Do not start while running shows use the devices</option>
+            <option value="preempt">Stop running shows with a lower priority</option>
+        </select>
+        <small id="inputConflictPolicyHelp" class="form-text text-muted">What starting this show does when running shows use some of its devices.</small>
+    </div>
     <button type="submit" class="btn btn-primary">Submit</button>
 </form>
 <script>
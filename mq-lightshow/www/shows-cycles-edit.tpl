@@ -9,6 +9,16 @@
 {{ end }}
         </select>
     </div>
+    <div class="form-group">
+        <label for="inputEffect">Effect</label>
+        <select class="custom-select" class="form-control" id="inputEffect" aria-describedby="inputEffectHelp" name="EffectID">
+            <option value=""></option>
+{{ range .Effects }}
+            <option value="{{.ID}}"{{if eq $.Cycle.EffectID .ID}} selected{{end}}>{{.Name}}</option>
+{{ end }}
+        </select>
+        <small id="inputEffectHelp" class="form-text text-muted">Run a procedural effect instead of a scene. Select either a Scene or an Effect.</small>
+    </div>
     <div class="form-group">
         <label for="inputCycles">Scene Cycles</label>
         <input type="text" class="form-control" id="inputCycles" aria-describedby="inputCyclesHelp" name="SceneCycles" value="{{.Cycle.SceneCycles}}">
@@ -54,8 +64,8 @@ $(document).ready(function() {
     $('#cycleForm').submit(function(event) {        
         event.preventDefault();
 
-        if ($.trim($("#inputScene").val()) === "" ) {
-            alert('Please select a Scene.');
+        if (($.trim($("#inputScene").val()) === "") === ($.trim($("#inputEffect").val()) === "")) {
+            alert('Please select either a Scene or an Effect.');
             return false;
         } else
         if ($.trim($("#inputCycles").val()) === "" || !$.isNumeric($("#inputCycles").val())) {
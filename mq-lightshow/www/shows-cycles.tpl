@@ -4,7 +4,7 @@
 <table class="table">
   <thead class="thead-dark">
     <tr>
-      <th scope="col">Scene</th>
+      <th scope="col">Scene / Effect</th>
       <th scope="col">Cycles</th>
       <th scope="col">End Delay</th>
       <th scope="col">Loop Include</th>
@@ -56,9 +56,15 @@ function populateContent() {
         var gSpeed = cycles[i].GlobalSpeed;
       }
 
+      if (cycles[i].EffectID != 0) {
+        var name = cycles[i].Effect.Name + ' (' + cycles[i].Effect.Type + ')';
+      } else {
+        var name = cycles[i].Scene.Name;
+      }
+
       html +=`
       <tr>
-        <td>${cycles[i].Scene.Name}</td>
+        <td>${name}</td>
         <td>${cycles[i].SceneCycles}</td>
         <td>${cycles[i].EndDelay}</td>
         <td>${cycles[i].LoopInclude}</td>
@@ -1,6 +1,11 @@
 {{define "content"}}
 <h1>Light Shows</h1>
 <p>Shows are collections of Scenes with individual settings. You must create at least one scene before you can create a show.</p>
+<p>
+  <a href="api/v1/export" class="btn btn-sm btn-secondary" title="Download a backup of all devices, scenes and shows" download>Export</a>
+  <button onclick="$('#importFile').trigger('click')" class="btn btn-sm btn-secondary" title="Restore devices, scenes and shows from a backup">Import</button>
+  <input type="file" id="importFile" accept=".json,application/json" style="display:none">
+</p>
 <table class="table">
   <thead class="thead-dark">
     <tr>
@@ -55,6 +60,18 @@ function populateContent() {
         <button onclick="stopShow(${shows[i].ID})" class="btn btn-sm btn-danger" title="Stop Show">
           <div class="icon-button-execute">&nbsp;</div>
         </button>`;
+
+          if (shows[i].Paused == true) {
+          html +=`
+        <button onclick="controlShow(${shows[i].ID}, 'resume')" class="btn btn-sm btn-primary" title="Resume Show">Resume</button>`;
+          } else {
+          html +=`
+        <button onclick="controlShow(${shows[i].ID}, 'pause')" class="btn btn-sm btn-primary" title="Pause Show">Pause</button>`;
+          }
+
+          html +=`
+        <button onclick="controlShow(${shows[i].ID}, 'previous')" class="btn btn-sm btn-primary" title="Previous Cycle">&laquo;</button>
+        <button onclick="controlShow(${shows[i].ID}, 'next')" class="btn btn-sm btn-primary" title="Next Cycle">&raquo;</button>`;
         } else {
         html +=`
         <button onclick="startShow(${shows[i].ID})" class="btn btn-sm btn-primary" title="Start Show">
@@ -112,6 +129,16 @@ function stopShow(id) {
   }, "json");
 }
 
+function controlShow(id, control) {
+  $.post("api/v1/show/"+id+"/"+control, function(data) {
+      if (data.Error != false) {
+          alert ("Error: " + data.Message)
+      } else {
+          populateContent()
+      }
+  }, "json");
+}
+
 function deleteShow(id) {
   if (confirm('Are you sure you want to delete this show?')) {
     $.post("api/v1/show/"+id+"/delete", function(data) {
@@ -124,8 +151,28 @@ function deleteShow(id) {
   }  
 }
 
+function importBackup(file) {
+  var reader = new FileReader();
+  reader.onload = function(e) {
+    $.post("api/v1/import", e.target.result, function(data) {
+        if (data.Error != false) {
+            alert ("Error: " + data.Message)
+        } else {
+            populateContent()
+        }
+    }, "json");
+  };
+  reader.readAsText(file);
+}
+
 $(document).ready(function() {
   populateContent();
+  $('#importFile').change(function() {
+    if (this.files.length > 0 && confirm('Importing adds every scene and show in the backup. Continue?')) {
+      importBackup(this.files[0]);
+    }
+    $(this).val('');
+  });
   makeDialog("#addShowModal");
   makeDialog("#configureShowModal");
 });
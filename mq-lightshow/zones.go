@@ -0,0 +1,148 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/lovesway/hassio-addons/mq-lightshow/models"
+)
+
+var (
+	errZoneName    = errors.New("zone names can only contain a-z, 0-9, - and _")
+	errZoneUnknown = errors.New("no device is in zone")
+)
+
+var zoneNamePattern = regexp.MustCompile(`^[a-z0-9_-]+$`)
+
+// parseZones checks zone names and returns them in lower case, without empty or repeated names.
+func parseZones(names []string) ([]string, error) {
+	zones := []string{}
+
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || containsZone(zones, name) {
+			continue
+		}
+
+		if !zoneNamePattern.MatchString(name) {
+			return zones, fmt.Errorf("%w: %q", errZoneName, name)
+		}
+
+		zones = append(zones, name)
+	}
+
+	return zones, nil
+}
+
+// splitZones splits zone names separated by commas or spaces, as they are typed in a form.
+func splitZones(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})
+}
+
+func containsZone(zones []string, zone string) bool {
+	for _, z := range zones {
+		if z == zone {
+			return true
+		}
+	}
+
+	return false
+}
+
+// inZones reports whether a device is in any of zones.
+func inZones(device models.Device, zones []string) bool {
+	for _, z := range device.Zones {
+		if containsZone(zones, z) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// zoneDevices returns devices followed by the devices of all that are in zones, each device once.
+func zoneDevices(devices []models.Device, zones []string, all []models.Device) []models.Device {
+	ds := append([]models.Device{}, devices...)
+	if len(zones) == 0 {
+		return ds
+	}
+
+	seen := map[int]bool{}
+	for _, d := range devices {
+		seen[d.ID] = true
+	}
+
+	for _, d := range all {
+		if !seen[d.ID] && inZones(d, zones) {
+			seen[d.ID] = true
+			ds = append(ds, d)
+		}
+	}
+
+	return ds
+}
+
+// checkZones returns an error for the first of zones that none of all is in.
+func checkZones(zones []string, all []models.Device) error {
+	for _, zone := range zones {
+		found := false
+
+		for _, d := range all {
+			if containsZone(d.Zones, zone) {
+				found = true
+
+				break
+			}
+		}
+
+		if !found {
+			return fmt.Errorf("%w: %v", errZoneUnknown, zone)
+		}
+	}
+
+	return nil
+}
+
+// GetZones to return the zones that devices are in, sorted by name.
+func (md *Modeler) GetZones() []models.Zone {
+	devices := map[string][]models.Device{}
+
+	for _, d := range md.GetDevices() {
+		for _, z := range d.Zones {
+			devices[z] = append(devices[z], d)
+		}
+	}
+
+	zones := []models.Zone{}
+	for name, ds := range devices {
+		zones = append(zones, models.Zone{Name: name, Devices: ds})
+	}
+
+	sort.Slice(zones, func(i, j int) bool {
+		return zones[i].Name < zones[j].Name
+	})
+
+	return zones
+}
+
+// expandZones adds the devices of their zones to the actions of a show, so a device added to a
+// zone is used by the show without editing its actions.
+func (e Executor) expandZones(show models.Show) models.Show {
+	all := e.md.GetDevices()
+
+	for i, cycle := range show.Cycles {
+		for j, group := range cycle.Scene.Groups {
+			for k, action := range group.Actions {
+				show.Cycles[i].Scene.Groups[j].Actions[k].Devices = zoneDevices(action.Devices, action.Zones, all)
+			}
+		}
+	}
+
+	return show
+}
@@ -0,0 +1,113 @@
+package main_test
+
+import (
+	"reflect"
+	"testing"
+
+	main "github.com/lovesway/hassio-addons/mq-lightshow"
+	"github.com/lovesway/hassio-addons/mq-lightshow/models"
+)
+
+func TestParseZones(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in   []string
+		want []string
+		err  bool
+	}{
+		{in: nil, want: []string{}},
+		{in: []string{"Porch", " garden ", "porch", ""}, want: []string{"porch", "garden"}},
+		{in: []string{"living_room", "floor-2"}, want: []string{"living_room", "floor-2"}},
+		{in: []string{"living room"}, err: true},
+		{in: []string{"porch", "café"}, err: true},
+	}
+
+	for _, tt := range tests {
+		got, err := main.ParseZones(tt.in)
+		if (err != nil) != tt.err {
+			t.Errorf("parseZones(%q) error = %v, want error %v", tt.in, err, tt.err)
+
+			continue
+		}
+
+		if !tt.err && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseZones(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSplitZones(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{in: "", want: []string{}},
+		{in: "porch", want: []string{"porch"}},
+		{in: "porch, garden  kitchen,,", want: []string{"porch", "garden", "kitchen"}},
+	}
+
+	for _, tt := range tests {
+		if got := main.SplitZones(tt.in); !reflect.DeepEqual(got, tt.want) && len(got)+len(tt.want) > 0 {
+			t.Errorf("splitZones(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestZoneDevices(t *testing.T) {
+	t.Parallel()
+
+	lamp := models.Device{ID: 1, Name: "lamp", Zones: []string{"porch"}}
+	spot := models.Device{ID: 2, Name: "spot", Zones: []string{"garden", "porch"}}
+	strip := models.Device{ID: 3, Name: "strip", Zones: []string{"kitchen"}}
+	all := []models.Device{lamp, spot, strip}
+
+	tests := []struct {
+		name    string
+		devices []models.Device
+		zones   []string
+		want    []models.Device
+	}{
+		{name: "no zones", devices: []models.Device{strip}, want: []models.Device{strip}},
+		{name: "zone", zones: []string{"porch"}, want: []models.Device{lamp, spot}},
+		{name: "zones", zones: []string{"garden", "kitchen"}, want: []models.Device{spot, strip}},
+		// devices in the zone that are also listed are used once.
+		{
+			name:    "devices and zone",
+			devices: []models.Device{spot},
+			zones:   []string{"porch"},
+			want:    []models.Device{spot, lamp},
+		},
+		{name: "empty zone", zones: []string{"attic"}, want: []models.Device{}},
+	}
+
+	for _, tt := range tests {
+		if got := main.ZoneDevices(tt.devices, tt.zones, all); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%v: zoneDevices = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCheckZones(t *testing.T) {
+	t.Parallel()
+
+	all := []models.Device{{ID: 1, Zones: []string{"porch"}}, {ID: 2, Zones: []string{"garden"}}}
+
+	tests := []struct {
+		zones []string
+		err   bool
+	}{
+		{zones: nil},
+		{zones: []string{"porch", "garden"}},
+		{zones: []string{"porch", "attic"}, err: true},
+	}
+
+	for _, tt := range tests {
+		err := main.CheckZones(tt.zones, all)
+		if (err != nil) != tt.err {
+			t.Errorf("checkZones(%q) error = %v, want error %v", tt.zones, err, tt.err)
+		}
+	}
+}